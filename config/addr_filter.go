@@ -0,0 +1,49 @@
+package config
+
+import (
+	"errors"
+	"net"
+	"strings"
+)
+
+// checkAddr 校验获取到的地址是否允许发布。
+// 在 deny 中的地址被拒绝; allow 不为空时仅允许其中的地址;
+// allow 为空时默认仅允许公网地址, 私有地址需开启 allowPrivate
+func checkAddr(addr string, allowPrivate bool, allow string, deny string) error {
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return errors.New("invalid address")
+	}
+
+	if cidr := matchCIDR(ip, deny); cidr != "" {
+		return errors.New("denied by " + cidr)
+	}
+	if strings.TrimSpace(allow) != "" {
+		if matchCIDR(ip, allow) == "" {
+			return errors.New("not in the allowed CIDRs")
+		}
+		return nil
+	}
+	if !allowPrivate && (isPrivateOrShared(ip) || ip.IsUnspecified()) {
+		return errors.New("not a public address")
+	}
+	return nil
+}
+
+// matchCIDR 返回 cidrs(以逗号分割) 中第一个包含 ip 的 CIDR, 也可为单个IP
+func matchCIDR(ip net.IP, cidrs string) string {
+	for _, cidr := range strings.Split(cidrs, ",") {
+		cidr = strings.TrimSpace(cidr)
+		if cidr == "" {
+			continue
+		}
+		if _, ipNet, err := net.ParseCIDR(cidr); err == nil {
+			if ipNet.Contains(ip) {
+				return cidr
+			}
+		} else if ip.Equal(net.ParseIP(cidr)) {
+			return cidr
+		}
+	}
+	return ""
+}
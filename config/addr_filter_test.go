@@ -0,0 +1,69 @@
+package config
+
+import (
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestCheckAddr(t *testing.T) {
+	tests := []struct {
+		addr         string
+		allowPrivate bool
+		allow        string
+		deny         string
+		accepted     bool
+	}{
+		{"203.0.113.1", false, "", "", true},
+		{"10.0.0.1", false, "", "", false},
+		{"100.64.1.1", false, "", "", false},
+		{"10.0.0.1", true, "", "", true},
+		{"fd00::1", false, "", "", false},
+		{"2001:db8::1", false, "", "", true},
+		// 允许列表
+		{"10.0.0.1", false, "10.0.0.0/8", "", true},
+		{"203.0.113.1", false, "10.0.0.0/8, 192.168.0.0/16", "", false},
+		// 拒绝列表优先
+		{"203.0.113.1", false, "203.0.113.0/24", "203.0.113.1", false},
+		{"198.51.100.7", true, "", "198.51.100.0/24", false},
+	}
+
+	for _, tt := range tests {
+		err := checkAddr(tt.addr, tt.allowPrivate, tt.allow, tt.deny)
+		if (err == nil) != tt.accepted {
+			t.Errorf("%s (allowPrivate=%v, allow=%q, deny=%q): 期待 %v, 得到 %v", tt.addr, tt.allowPrivate, tt.allow, tt.deny, tt.accepted, err)
+		}
+	}
+}
+
+// TestMigrateAllowPrivate 测试之前的配置保持允许发布私有地址
+func TestMigrateAllowPrivate(t *testing.T) {
+	byt := []byte(`dnsconf:
+    - name: old
+      ipv4:
+        enable: true
+      ipv6:
+        enable: true
+    - name: new
+      ipv4:
+        allowprivate: false
+      ipv6:
+        allowprivate: false
+`)
+	conf := &Config{DnsConf: make([]DnsConfig, 2)}
+	if !conf.migrateAllowPrivate(byt) {
+		t.Fatal("期待迁移之前的配置")
+	}
+	if !conf.DnsConf[0].Ipv4.AllowPrivate || !conf.DnsConf[0].Ipv6.AllowPrivate {
+		t.Error("期待之前的配置允许私有地址")
+	}
+	if conf.DnsConf[1].Ipv4.AllowPrivate || conf.DnsConf[1].Ipv6.AllowPrivate {
+		t.Error("期待已保存过的配置不变")
+	}
+
+	// 迁移后保存的配置不再迁移
+	byt, _ = yaml.Marshal(conf)
+	if conf.migrateAllowPrivate(byt) {
+		t.Error("期待不再迁移")
+	}
+}
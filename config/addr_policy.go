@@ -0,0 +1,67 @@
+package config
+
+import (
+	"net"
+	"slices"
+	"sort"
+
+	"github.com/jeessy2/ddns-go/v6/util"
+)
+
+const (
+	// AddrPolicyStable 仅使用稳定的地址, 跳过临时(隐私)、已弃用、DAD失败及未完成DAD的地址
+	AddrPolicyStable = "stable"
+	// AddrPolicyLifetime 在稳定的地址中优先使用首选生存期最长的地址
+	AddrPolicyLifetime = "lifetime"
+)
+
+// IPv6地址标志, 见 linux/if_addr.h
+const (
+	ifaFTemporary  = 0x01
+	ifaFDadFailed  = 0x08
+	ifaFDeprecated = 0x20
+	ifaFTentative  = 0x40
+)
+
+// ipv6AddrInfo 网卡上IPv6地址的状态
+type ipv6AddrInfo struct {
+	IP    net.IP
+	Flags uint32
+	// PreferredLifetime 首选生存期(秒), 0xffffffff 为永久
+	PreferredLifetime uint32
+}
+
+// selectIpv6Addrs 按策略筛选网卡 ifName 的IPv6地址, 无法读取地址状态时返回全部地址
+func selectIpv6Addrs(policy string, ifName string, addrs []string) []string {
+	infos, err := getIpv6AddrInfos(ifName)
+	if err != nil {
+		util.Log("读取IPv6地址的状态失败, 将不使用地址选择策略! 异常信息: %s", err)
+		return addrs
+	}
+	return filterIpv6Addrs(policy, infos, addrs)
+}
+
+// filterIpv6Addrs 从 addrs 中筛选出符合策略的地址
+func filterIpv6Addrs(policy string, infos []ipv6AddrInfo, addrs []string) (result []string) {
+	var usable []ipv6AddrInfo
+	for _, info := range infos {
+		if !slices.Contains(addrs, info.IP.String()) {
+			continue
+		}
+		if info.Flags&(ifaFTemporary|ifaFDadFailed|ifaFDeprecated|ifaFTentative) != 0 || info.PreferredLifetime == 0 {
+			continue
+		}
+		usable = append(usable, info)
+	}
+
+	if policy == AddrPolicyLifetime {
+		sort.SliceStable(usable, func(i, j int) bool {
+			return usable[i].PreferredLifetime > usable[j].PreferredLifetime
+		})
+	}
+
+	for _, info := range usable {
+		result = append(result, info.IP.String())
+	}
+	return
+}
@@ -0,0 +1,68 @@
+//go:build linux
+
+package config
+
+import (
+	"encoding/binary"
+	"net"
+	"syscall"
+)
+
+// 地址属性, 见 linux/if_addr.h
+const (
+	ifaCacheInfo = 6
+	ifaFlags     = 8
+)
+
+// getIpv6AddrInfos 通过 rtnetlink 读取网卡上IPv6地址的标志及首选生存期
+func getIpv6AddrInfos(ifName string) ([]ipv6AddrInfo, error) {
+	iface, err := net.InterfaceByName(ifName)
+	if err != nil {
+		return nil, err
+	}
+	rib, err := syscall.NetlinkRIB(syscall.RTM_GETADDR, syscall.AF_INET6)
+	if err != nil {
+		return nil, err
+	}
+	msgs, err := syscall.ParseNetlinkMessage(rib)
+	if err != nil {
+		return nil, err
+	}
+
+	var infos []ipv6AddrInfo
+	for _, msg := range msgs {
+		if msg.Header.Type != syscall.RTM_NEWADDR || len(msg.Data) < syscall.SizeofIfAddrmsg {
+			continue
+		}
+		// struct ifaddrmsg: family, prefixlen, flags, scope, index
+		if msg.Data[0] != syscall.AF_INET6 || binary.NativeEndian.Uint32(msg.Data[4:8]) != uint32(iface.Index) {
+			continue
+		}
+		attrs, err := syscall.ParseNetlinkRouteAttr(&msg)
+		if err != nil {
+			continue
+		}
+
+		info := ipv6AddrInfo{Flags: uint32(msg.Data[2]), PreferredLifetime: 0xffffffff}
+		for _, attr := range attrs {
+			switch attr.Attr.Type {
+			case syscall.IFA_ADDRESS:
+				info.IP = net.IP(attr.Value)
+			case ifaFlags:
+				// 包含 ifaddrmsg 中放不下的标志
+				if len(attr.Value) >= 4 {
+					info.Flags = binary.NativeEndian.Uint32(attr.Value)
+				}
+			case ifaCacheInfo:
+				// struct ifa_cacheinfo: ifa_prefered, ifa_valid, cstamp, tstamp
+				if len(attr.Value) >= 4 {
+					info.PreferredLifetime = binary.NativeEndian.Uint32(attr.Value)
+				}
+			}
+		}
+		if info.IP != nil {
+			infos = append(infos, info)
+		}
+	}
+	return infos, nil
+}
@@ -0,0 +1,10 @@
+//go:build !linux
+
+package config
+
+import "errors"
+
+// getIpv6AddrInfos 仅 Linux 支持读取IPv6地址的状态
+func getIpv6AddrInfos(ifName string) ([]ipv6AddrInfo, error) {
+	return nil, errors.New("only supported on Linux")
+}
@@ -0,0 +1,31 @@
+package config
+
+import (
+	"net"
+	"reflect"
+	"testing"
+)
+
+func TestFilterIpv6Addrs(t *testing.T) {
+	infos := []ipv6AddrInfo{
+		{IP: net.ParseIP("2001:db8::aaaa"), Flags: ifaFTemporary, PreferredLifetime: 86400},
+		{IP: net.ParseIP("2001:db8::1"), PreferredLifetime: 3600},
+		{IP: net.ParseIP("2001:db8::dead"), Flags: ifaFDeprecated, PreferredLifetime: 0},
+		{IP: net.ParseIP("2001:db8::2"), PreferredLifetime: 0xffffffff},
+		{IP: net.ParseIP("fd00::1"), PreferredLifetime: 0xffffffff},
+	}
+	addrs := []string{"2001:db8::aaaa", "2001:db8::1", "2001:db8::dead", "2001:db8::2"}
+
+	tests := []struct {
+		policy   string
+		expected []string
+	}{
+		{AddrPolicyStable, []string{"2001:db8::1", "2001:db8::2"}},
+		{AddrPolicyLifetime, []string{"2001:db8::2", "2001:db8::1"}},
+	}
+	for _, tt := range tests {
+		if result := filterIpv6Addrs(tt.policy, infos, addrs); !reflect.DeepEqual(result, tt.expected) {
+			t.Errorf("%s: 期待 %v, 得到 %v", tt.policy, tt.expected, result)
+		}
+	}
+}
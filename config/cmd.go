@@ -0,0 +1,166 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jeessy2/ddns-go/v6/util"
+)
+
+// cmdTimeout 获取IP的命令的超时时间
+const cmdTimeout = 30 * time.Second
+
+// cmdOutput 命令输出的JSON, 如 {"ipv4":["1.1.1.1"],"ipv6":["::1"],"ttl":60}
+type cmdOutput struct {
+	Ipv4 []string `json:"ipv4"`
+	Ipv6 []string `json:"ipv6"`
+	TTL  int      `json:"ttl"`
+}
+
+// getAddrFromCmd 执行命令获取地址。
+// 标准输出为JSON时严格解析, ttl 覆盖配置中的TTL;
+// 否则使用标准输出中第一个匹配的地址, 标准输出中没有时使用标准错误中的。
+// 每个域名只发布一个地址, 因此JSON中有多个地址时不使用
+func (conf *DnsConfig) getAddrFromCmd(ctx context.Context, addrType string) string {
+	var cmd string
+	var comp *regexp.Regexp
+	if addrType == "IPv4" {
+		cmd = conf.Ipv4.Cmd
+		comp = Ipv4Reg
+	} else {
+		cmd = conf.Ipv6.Cmd
+		comp = Ipv6Reg
+	}
+	// cmd is empty
+	if cmd == "" {
+		return ""
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, cmdTimeout)
+	defer cancel()
+	// run cmd with proper shell
+	execCmd := shellCommandContext(ctx, cmd)
+	execCmd.Env = append(os.Environ(), conf.cmdEnv(addrType)...)
+	// 命令的子进程未退出时, 超时后不再等待其输出
+	execCmd.WaitDelay = time.Second
+	var stdout, stderr bytes.Buffer
+	execCmd.Stdout = &stdout
+	execCmd.Stderr = &stderr
+	// run cmd
+	if err := execCmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			err = ctx.Err()
+		}
+		util.Log("获取%s结果失败! 未能成功执行命令：%s, 错误：%q, 退出状态码：%s", addrType, execCmd.String(), stderr.String(), err)
+		return ""
+	}
+
+	str := strings.TrimSpace(stdout.String())
+	if strings.HasPrefix(str, "{") {
+		output, err := parseCmdOutput(str)
+		if err != nil {
+			util.Log("命令输出的JSON不正确! 命令: %s, 错误: %s", execCmd.String(), err)
+			return ""
+		}
+		if output.TTL > 0 {
+			conf.TTL = strconv.Itoa(output.TTL)
+		}
+		addrs := output.Ipv4
+		if addrType == "IPv6" {
+			addrs = output.Ipv6
+		}
+		if len(addrs) == 0 {
+			util.Log("获取%s结果失败! 命令: %s, 标准输出: %q", addrType, execCmd.String(), str)
+			return ""
+		}
+		if len(addrs) > 1 {
+			util.Log("命令返回了多个%s地址, 每个域名只能使用一个! 命令: %s, 地址: %s", addrType, execCmd.String(), strings.Join(addrs, ", "))
+			return ""
+		}
+		conf.source = cmd
+		return addrs[0]
+	}
+
+	// get result
+	result := comp.FindString(str)
+	if result == "" {
+		// 兼容将地址输出到标准错误的命令
+		result = comp.FindString(stderr.String())
+		if result == "" {
+			util.Log("获取%s结果失败! 标准输出及标准错误中都没有匹配的地址, 命令: %s, 标准输出: %q, 标准错误: %q", addrType, execCmd.String(), str, stderr.String())
+			return ""
+		}
+		util.Log("命令的标准输出中没有%s地址, 使用标准错误中的 %s", addrType, result)
+	}
+	conf.source = cmd
+	return result
+}
+
+// parseCmdOutput 严格解析命令输出的JSON, 不允许未知字段及不正确的地址
+func parseCmdOutput(str string) (output cmdOutput, err error) {
+	decoder := json.NewDecoder(strings.NewReader(str))
+	decoder.DisallowUnknownFields()
+	if err = decoder.Decode(&output); err != nil {
+		return
+	}
+	if decoder.More() {
+		return output, errors.New("unexpected data after JSON")
+	}
+	if output.TTL < 0 {
+		return output, fmt.Errorf("invalid ttl: %d", output.TTL)
+	}
+	for _, addr := range output.Ipv4 {
+		if ip := net.ParseIP(addr); ip == nil || ip.To4() == nil {
+			return output, fmt.Errorf("invalid IPv4 address: %q", addr)
+		}
+	}
+	for _, addr := range output.Ipv6 {
+		if ip := net.ParseIP(addr); ip == nil || ip.To4() != nil {
+			return output, fmt.Errorf("invalid IPv6 address: %q", addr)
+		}
+	}
+	return
+}
+
+// cmdEnv 传递给命令的环境变量, 描述当前配置, 不包含ID/Secret
+func (conf *DnsConfig) cmdEnv(addrType string) []string {
+	return []string{
+		"DDNS_GO_ADDR_TYPE=" + addrType,
+		"DDNS_GO_NAME=" + conf.Name,
+		"DDNS_GO_DNS=" + conf.DNS.Name,
+		"DDNS_GO_TTL=" + conf.TTL,
+		"DDNS_GO_IPV4_ENABLE=" + strconv.FormatBool(conf.Ipv4.Enable),
+		"DDNS_GO_IPV4_DOMAINS=" + strings.Join(conf.Ipv4.Domains, ","),
+		"DDNS_GO_IPV6_ENABLE=" + strconv.FormatBool(conf.Ipv6.Enable),
+		"DDNS_GO_IPV6_DOMAINS=" + strings.Join(conf.Ipv6.Domains, ","),
+	}
+}
+
+// shellCommand creates a command that runs cmd with proper shell
+func shellCommand(cmd string) *exec.Cmd {
+	return shellCommandContext(context.Background(), cmd)
+}
+
+// shellCommandContext is like shellCommand but kills the shell when ctx is done
+func shellCommandContext(ctx context.Context, cmd string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.CommandContext(ctx, "powershell", "-Command", cmd)
+	}
+	// If Bash does not exist, use sh
+	_, err := exec.LookPath("bash")
+	if err != nil {
+		return exec.CommandContext(ctx, "sh", "-c", cmd)
+	}
+	return exec.CommandContext(ctx, "bash", "-c", cmd)
+}
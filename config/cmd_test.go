@@ -0,0 +1,78 @@
+package config
+
+import (
+	"context"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestParseCmdOutput(t *testing.T) {
+	tests := []struct {
+		name    string
+		output  string
+		wantErr bool
+	}{
+		{"Valid", `{"ipv4":["203.0.113.1"],"ipv6":["2001:db8::1"],"ttl":60}`, false},
+		{"Only IPv6", `{"ipv6":["2001:db8::1","2001:db8::2"]}`, false},
+		{"Unknown field", `{"ipv4":["203.0.113.1"],"ip":"203.0.113.1"}`, true},
+		{"IPv6 in ipv4", `{"ipv4":["2001:db8::1"]}`, true},
+		{"IPv4 in ipv6", `{"ipv6":["203.0.113.1"]}`, true},
+		{"Invalid address", `{"ipv4":["203.0.113"]}`, true},
+		{"Negative ttl", `{"ttl":-1}`, true},
+		{"Trailing data", `{"ipv4":["203.0.113.1"]} {}`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseCmdOutput(tt.output)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestGetAddrFromCmd(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires a POSIX shell")
+	}
+
+	conf := &DnsConfig{Name: "home", TTL: "600"}
+	conf.Ipv4.Cmd = `echo "diagnostics 198.51.100.9" >&2; echo '{"ipv4":["203.0.113.1"],"ipv6":["2001:db8::1","2001:db8::2"],"ttl":60}'`
+	if addr := conf.getAddrFromCmd(context.Background(), "IPv4"); addr != "203.0.113.1" {
+		t.Errorf("Expected 203.0.113.1, got %s", addr)
+	}
+	if conf.TTL != "60" {
+		t.Errorf("Expected TTL 60, got %s", conf.TTL)
+	}
+
+	// 多个地址时不使用
+	conf.Ipv6.Cmd = conf.Ipv4.Cmd
+	if addr := conf.getAddrFromCmd(context.Background(), "IPv6"); addr != "" {
+		t.Errorf("Expected no address, got %s", addr)
+	}
+
+	// 标准输出中没有地址时使用标准错误中的
+	conf.Ipv4.Cmd = `echo "no address"; echo 198.51.100.9 >&2`
+	if addr := conf.getAddrFromCmd(context.Background(), "IPv4"); addr != "198.51.100.9" {
+		t.Errorf("Expected 198.51.100.9, got %s", addr)
+	}
+
+	conf.Ipv6.Cmd = `echo "$DDNS_GO_ADDR_TYPE $DDNS_GO_NAME" >&2; [ "$DDNS_GO_NAME" = home ] && echo 2001:db8::1`
+	if addr := conf.getAddrFromCmd(context.Background(), "IPv6"); addr != "2001:db8::1" {
+		t.Errorf("Expected 2001:db8::1, got %s", addr)
+	}
+
+	// ctx 取消后结束命令
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	conf.Ipv4.Cmd = "sleep 10; echo 203.0.113.1"
+	if addr := conf.getAddrFromCmd(ctx, "IPv4"); addr != "" {
+		t.Errorf("Expected no address, got %s", addr)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Expected the command to be killed, took %s", elapsed)
+	}
+}
@@ -1,14 +1,14 @@
 package config
 
 import (
+	"context"
 	"errors"
 	"io"
 	"log"
+	"net"
 	"net/http"
 	"os"
-	"os/exec"
 	"regexp"
-	"runtime"
 	"strconv"
 	"strings"
 	"sync"
@@ -29,27 +29,78 @@ type DnsConfig struct {
 	Name string
 	Ipv4 struct {
 		Enable bool
-		// 获取IP类型 url/netInterface
-		GetType      string
-		URL          string
+		// 获取IP类型 url/netInterface/cmd/stun/dns/router
+		GetType string
+		URL     string
+		// 共识数量, 至少有该数量的接口返回相同的地址时才更新, 为空则使用第一个成功的接口
+		UrlQuorum    string
 		NetInterface string
 		Cmd          string
-		Domains      []string
+		// STUN 服务器, 多个以逗号分割
+		Stun string
+		// 需要两个 STUN 服务器返回相同的地址
+		StunConsensus bool
+		// DNS查询, 格式: DNS服务器 域名 [类型], 多个以逗号分割
+		DnsQuery string
+		// 备用来源, 获取失败时按顺序尝试, 每行格式: 类型[:超时秒数] [参数]
+		Fallback []string
+		// 允许发布私有地址, 默认仅允许公网地址
+		AllowPrivate bool
+		// 仅允许发布的地址段, 多个以逗号分割
+		AllowCIDR string
+		// 不允许发布的地址段, 多个以逗号分割
+		DenyCIDR string
+		Domains  []string
 	}
 	Ipv6 struct {
 		Enable bool
-		// 获取IP类型 url/netInterface
-		GetType      string
-		URL          string
+		// 获取IP类型 url/netInterface/cmd/stun/dns
+		GetType string
+		URL     string
+		// 共识数量, 至少有该数量的接口返回相同的地址时才更新, 为空则使用第一个成功的接口
+		UrlQuorum    string
 		NetInterface string
 		Cmd          string
-		Ipv6Reg      string // ipv6匹配正则表达式
-		Domains      []string
+		// STUN 服务器, 多个以逗号分割
+		Stun string
+		// 需要两个 STUN 服务器返回相同的地址
+		StunConsensus bool
+		// DNS查询, 格式: DNS服务器 域名 [类型], 多个以逗号分割
+		DnsQuery string
+		// 备用来源, 获取失败时按顺序尝试, 每行格式: 类型[:超时秒数] [参数]
+		Fallback []string
+		// 允许发布私有地址, 默认仅允许公网地址
+		AllowPrivate bool
+		// 仅允许发布的地址段, 多个以逗号分割
+		AllowCIDR string
+		// 不允许发布的地址段, 多个以逗号分割
+		DenyCIDR string
+		Ipv6Reg  string // ipv6匹配正则表达式
+		// 地址选择策略 stable/lifetime, 为空则不筛选
+		AddrPolicy string
+		Domains    []string
 	}
 	DNS DNS
 	TTL string
 	// 发送HTTP请求时使用的网卡名称，为空则使用默认网卡
 	HttpInterface string
+	// 更新成功后向权威DNS服务器校验解析结果
+	Verify bool
+	// 校验超时时间(秒)
+	VerifyTimeout string
+	// 漂移检测方式 resolver/authoritative, 为空则不检测
+	DriftDetect string
+	// HTTPS/SVCB 记录, 地址提示随IP更新
+	Svcb []string
+	// SRV 记录, 端口可来自命令或网关的端口映射
+	Srv []string
+	// 心跳TXT记录的域名, 每次成功后写入, 为空则不写入
+	TxtRecord string
+	// 心跳TXT记录的内容模板, 支持Webhook中的变量
+	TxtTemplate string
+
+	// 本次获取到地址的接口、命令或网卡, 用于显示来源
+	source string
 }
 
 // DNS DNS配置
@@ -66,6 +117,9 @@ type Config struct {
 	DnsConf []DnsConfig
 	User
 	Webhook
+	Mqtt Mqtt `yaml:"mqtt,omitempty"`
+	// 更新历史的保留设置
+	History History `yaml:"history,omitempty"`
 	// 禁止公网访问
 	NotAllowWanAccess bool
 	// 语言
@@ -114,6 +168,9 @@ func GetConfigCached() (conf Config, err error) {
 		return *cache.ConfigSingle, err
 	}
 
+	// 单个Webhook迁移到Webhooks, 保存时写入
+	cache.ConfigSingle.migrateWebhook()
+
 	// 未填写登录信息, 确保不能从公网访问
 	if cache.ConfigSingle.Username == "" && cache.ConfigSingle.Password == "" {
 		cache.ConfigSingle.NotAllowWanAccess = true
@@ -124,6 +181,44 @@ func GetConfigCached() (conf Config, err error) {
 	return *cache.ConfigSingle, err
 }
 
+// migrateAllowPrivate 配置文件中没有 AllowPrivate 的配置来自不过滤地址的版本, 将其设为允许以保持之前的行为。
+// 返回是否有修改
+func (conf *Config) migrateAllowPrivate(byt []byte) (migrated bool) {
+	var old struct {
+		DnsConf []struct {
+			Ipv4 struct{ AllowPrivate *bool }
+			Ipv6 struct{ AllowPrivate *bool }
+		}
+	}
+	if yaml.Unmarshal(byt, &old) != nil {
+		return false
+	}
+	for i := range min(len(old.DnsConf), len(conf.DnsConf)) {
+		if old.DnsConf[i].Ipv4.AllowPrivate == nil {
+			conf.DnsConf[i].Ipv4.AllowPrivate = true
+			migrated = true
+		}
+		if old.DnsConf[i].Ipv6.AllowPrivate == nil {
+			conf.DnsConf[i].Ipv6.AllowPrivate = true
+			migrated = true
+		}
+	}
+	return
+}
+
+// migrateWebhook 将之前的单个Webhook迁移到Webhooks
+func (conf *Config) migrateWebhook() {
+	if conf.WebhookURL == "" || len(conf.Webhooks) > 0 {
+		return
+	}
+	conf.Webhooks = []WebhookConfig{{
+		URL:         conf.WebhookURL,
+		RequestBody: conf.WebhookRequestBody,
+		Headers:     conf.WebhookHeaders,
+	}}
+	conf.WebhookURL, conf.WebhookRequestBody, conf.WebhookHeaders = "", "", ""
+}
+
 // CompatibleConfig 兼容之前的配置文件
 func (conf *Config) CompatibleConfig() {
 
@@ -138,6 +233,10 @@ func (conf *Config) CompatibleConfig() {
 
 	// 兼容v5.0.0之前的配置文件
 	if len(conf.DnsConf) > 0 {
+		// 之前的版本不过滤地址, 保持允许发布私有地址
+		if byt, err := os.ReadFile(util.GetConfigFilePath()); err == nil && conf.migrateAllowPrivate(byt) {
+			conf.SaveConfig()
+		}
 		return
 	}
 
@@ -157,6 +256,7 @@ func (conf *Config) CompatibleConfig() {
 		return
 	}
 	if len(dnsConf.DNS.Name) > 0 {
+		dnsConf.Ipv4.AllowPrivate, dnsConf.Ipv6.AllowPrivate = true, true
 		cache.Lock.Lock()
 		defer cache.Lock.Unlock()
 		conf.DnsConf = append(conf.DnsConf, *dnsConf)
@@ -227,7 +327,7 @@ func (conf *Config) CheckPassword(newPassword string) (hashedPwd string, err err
 	return
 }
 
-func (conf *DnsConfig) getIpv4AddrFromInterface() string {
+func (conf *DnsConfig) getIpv4AddrFromInterface(ctx context.Context) string {
 	ipv4, _, err := GetNetInterface()
 	if err != nil {
 		util.Log("从网卡获得IPv4失败")
@@ -236,6 +336,10 @@ func (conf *DnsConfig) getIpv4AddrFromInterface() string {
 
 	for _, netInterface := range ipv4 {
 		if netInterface.Name == conf.Ipv4.NetInterface && len(netInterface.Address) > 0 {
+			if ctx.Err() != nil {
+				return ""
+			}
+			conf.source = netInterface.Name
 			return netInterface.Address[0]
 		}
 	}
@@ -244,12 +348,17 @@ func (conf *DnsConfig) getIpv4AddrFromInterface() string {
 	return ""
 }
 
-func (conf *DnsConfig) getIpv4AddrFromUrl() string {
+func (conf *DnsConfig) getIpv4AddrFromUrl(ctx context.Context) string {
 	client := util.CreateNoProxyHTTPClient("tcp4")
 	urls := strings.Split(conf.Ipv4.URL, ",")
+	if quorum := parseUrlQuorum(conf.Ipv4.UrlQuorum); quorum > 0 {
+		result, sources := getAddrFromUrlQuorum(ctx, "IPv4", client, urls, Ipv4Reg, quorum)
+		conf.source = strings.Join(sources, ", ")
+		return result
+	}
 	for _, url := range urls {
 		url = strings.TrimSpace(url)
-		resp, err := client.Get(url)
+		resp, err := getWithContext(ctx, client, url)
 		if err != nil {
 			util.Log("通过接口获取IPv4失败! 接口地址: %s", url)
 			util.Log("异常信息: %s", err)
@@ -266,73 +375,41 @@ func (conf *DnsConfig) getIpv4AddrFromUrl() string {
 		if result == "" {
 			util.Log("获取IPv4结果失败! 接口: %s ,返回值: %s", url, string(body))
 		}
+		conf.source = url
 		return result
 	}
 	return ""
 }
 
-func (conf *DnsConfig) getAddrFromCmd(addrType string) string {
-	var cmd string
-	var comp *regexp.Regexp
-	if addrType == "IPv4" {
-		cmd = conf.Ipv4.Cmd
-		comp = Ipv4Reg
-	} else {
-		cmd = conf.Ipv6.Cmd
-		comp = Ipv6Reg
-	}
-	// cmd is empty
-	if cmd == "" {
-		return ""
-	}
-	// run cmd with proper shell
-	var execCmd *exec.Cmd
-	if runtime.GOOS == "windows" {
-		execCmd = exec.Command("powershell", "-Command", cmd)
-	} else {
-		// If Bash does not exist, use sh
-		_, err := exec.LookPath("bash")
-		if err != nil {
-			execCmd = exec.Command("sh", "-c", cmd)
-		} else {
-			execCmd = exec.Command("bash", "-c", cmd)
-		}
-	}
-	// run cmd
-	out, err := execCmd.CombinedOutput()
-	if err != nil {
-		util.Log("获取%s结果失败! 未能成功执行命令：%s, 错误：%q, 退出状态码：%s", addrType, execCmd.String(), out, err)
-		return ""
-	}
-	str := string(out)
-	// get result
-	result := comp.FindString(str)
-	if result == "" {
-		util.Log("获取%s结果失败! 命令: %s, 标准输出: %q", addrType, execCmd.String(), str)
-	}
-	return result
-}
-
-// GetIpv4Addr 获得IPv4地址
-func (conf *DnsConfig) GetIpv4Addr() string {
+// GetIpv4Addr 获得IPv4地址, ctx 取消时停止从接口、命令或网卡获取
+func (conf *DnsConfig) GetIpv4Addr(ctx context.Context) string {
 	// 判断从哪里获取IP
 	switch conf.Ipv4.GetType {
 	case "netInterface":
 		// 从网卡获取 IP
-		return conf.getIpv4AddrFromInterface()
+		return conf.getIpv4AddrFromInterface(ctx)
 	case "url":
 		// 从 URL 获取 IP
-		return conf.getIpv4AddrFromUrl()
+		return conf.getIpv4AddrFromUrl(ctx)
 	case "cmd":
 		// 从命令行获取 IP
-		return conf.getAddrFromCmd("IPv4")
+		return conf.getAddrFromCmd(ctx, "IPv4")
+	case "stun":
+		// 从 STUN 服务器获取 IP
+		return conf.getAddrFromStun("IPv4")
+	case "dns":
+		// 通过 DNS 查询获取 IP
+		return conf.getAddrFromDNS("IPv4")
+	case "router":
+		// 从上游路由器获取 IP
+		return conf.getIpv4AddrFromRouter()
 	default:
 		log.Println("IPv4's get IP method is unknown")
 		return "" // unknown type
 	}
 }
 
-func (conf *DnsConfig) getIpv6AddrFromInterface() string {
+func (conf *DnsConfig) getIpv6AddrFromInterface(ctx context.Context) (result string) {
 	_, ipv6, err := GetNetInterface()
 	if err != nil {
 		util.Log("从网卡获得IPv6失败")
@@ -341,34 +418,57 @@ func (conf *DnsConfig) getIpv6AddrFromInterface() string {
 
 	for _, netInterface := range ipv6 {
 		if netInterface.Name == conf.Ipv6.NetInterface && len(netInterface.Address) > 0 {
+			if ctx.Err() != nil {
+				return ""
+			}
+			conf.source = netInterface.Name
+			addresses := netInterface.Address
+			if conf.Ipv6.AddrPolicy != "" {
+				addresses = selectIpv6Addrs(conf.Ipv6.AddrPolicy, netInterface.Name, addresses)
+				if len(addresses) == 0 {
+					util.Log("网卡 %s 没有符合地址选择策略的IPv6地址", netInterface.Name)
+					return ""
+				}
+			}
 			if conf.Ipv6.Ipv6Reg != "" {
 				// 匹配第几个IPv6
 				if match, err := regexp.MatchString("@\\d", conf.Ipv6.Ipv6Reg); err == nil && match {
 					num, err := strconv.Atoi(conf.Ipv6.Ipv6Reg[1:])
 					if err == nil {
 						if num > 0 {
-							if num <= len(netInterface.Address) {
-								return netInterface.Address[num-1]
+							if num <= len(addresses) {
+								return addresses[num-1]
 							}
 							util.Log("未找到第 %d 个IPv6地址! 将使用第一个IPv6地址", num)
-							return netInterface.Address[0]
+							return addresses[0]
 						}
 						util.Log("IPv6匹配表达式 %s 不正确! 最小从1开始", conf.Ipv6.Ipv6Reg)
 						return ""
 					}
 				}
+				// CIDR匹配
+				if _, cidr, err := net.ParseCIDR(conf.Ipv6.Ipv6Reg); err == nil {
+					for _, addr := range addresses {
+						if cidr.Contains(net.ParseIP(addr)) {
+							util.Log("匹配成功! 匹配到地址: %s", addr)
+							return addr
+						}
+					}
+					util.Log("没有匹配到任何一个IPv6地址, 将使用第一个地址")
+					return addresses[0]
+				}
 				// 正则表达式匹配
 				util.Log("IPv6将使用正则表达式 %s 进行匹配", conf.Ipv6.Ipv6Reg)
-				for i := 0; i < len(netInterface.Address); i++ {
-					matched, err := regexp.MatchString(conf.Ipv6.Ipv6Reg, netInterface.Address[i])
+				for i := 0; i < len(addresses); i++ {
+					matched, err := regexp.MatchString(conf.Ipv6.Ipv6Reg, addresses[i])
 					if matched && err == nil {
-						util.Log("匹配成功! 匹配到地址: %s", netInterface.Address[i])
-						return netInterface.Address[i]
+						util.Log("匹配成功! 匹配到地址: %s", addresses[i])
+						return addresses[i]
 					}
 				}
 				util.Log("没有匹配到任何一个IPv6地址, 将使用第一个地址")
 			}
-			return netInterface.Address[0]
+			return addresses[0]
 		}
 	}
 
@@ -376,12 +476,17 @@ func (conf *DnsConfig) getIpv6AddrFromInterface() string {
 	return ""
 }
 
-func (conf *DnsConfig) getIpv6AddrFromUrl() string {
+func (conf *DnsConfig) getIpv6AddrFromUrl(ctx context.Context) string {
 	client := util.CreateNoProxyHTTPClient("tcp6")
 	urls := strings.Split(conf.Ipv6.URL, ",")
+	if quorum := parseUrlQuorum(conf.Ipv6.UrlQuorum); quorum > 0 {
+		result, sources := getAddrFromUrlQuorum(ctx, "IPv6", client, urls, Ipv6Reg, quorum)
+		conf.source = strings.Join(sources, ", ")
+		return result
+	}
 	for _, url := range urls {
 		url = strings.TrimSpace(url)
-		resp, err := client.Get(url)
+		resp, err := getWithContext(ctx, client, url)
 		if err != nil {
 			util.Log("通过接口获取IPv6失败! 接口地址: %s", url)
 			util.Log("异常信息: %s", err)
@@ -399,30 +504,46 @@ func (conf *DnsConfig) getIpv6AddrFromUrl() string {
 		if result == "" {
 			util.Log("获取IPv6结果失败! 接口: %s ,返回值: %s", url, result)
 		}
+		conf.source = url
 		return result
 	}
 	return ""
 }
 
-// GetIpv6Addr 获得IPv6地址
-func (conf *DnsConfig) GetIpv6Addr() (result string) {
+// GetIpv6Addr 获得IPv6地址, ctx 取消时停止从接口、命令或网卡获取
+func (conf *DnsConfig) GetIpv6Addr(ctx context.Context) (result string) {
 	// 判断从哪里获取IP
 	switch conf.Ipv6.GetType {
 	case "netInterface":
 		// 从网卡获取 IP
-		return conf.getIpv6AddrFromInterface()
+		return conf.getIpv6AddrFromInterface(ctx)
 	case "url":
 		// 从 URL 获取 IP
-		return conf.getIpv6AddrFromUrl()
+		return conf.getIpv6AddrFromUrl(ctx)
 	case "cmd":
 		// 从命令行获取 IP
-		return conf.getAddrFromCmd("IPv6")
+		return conf.getAddrFromCmd(ctx, "IPv6")
+	case "stun":
+		// 从 STUN 服务器获取 IP
+		return conf.getAddrFromStun("IPv6")
+	case "dns":
+		// 通过 DNS 查询获取 IP
+		return conf.getAddrFromDNS("IPv6")
 	default:
 		log.Println("IPv6's get IP method is unknown")
 		return "" // unknown type
 	}
 }
 
+// getWithContext 发送GET请求, ctx 取消时中断
+func getWithContext(ctx context.Context, client *http.Client, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return client.Do(req)
+}
+
 // GetHTTPClient 获得HTTP客户端，如果配置了HttpInterface则绑定到指定网卡
 func (conf *DnsConfig) GetHTTPClient() *http.Client {
 	return util.CreateHTTPClientWithInterface(conf.HttpInterface)
@@ -0,0 +1,68 @@
+package config
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/jeessy2/ddns-go/v6/util"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// dnsQueryTypes 支持的查询类型
+var dnsQueryTypes = map[string]dnsmessage.Type{
+	"A":    dnsmessage.TypeA,
+	"AAAA": dnsmessage.TypeAAAA,
+	"TXT":  dnsmessage.TypeTXT,
+}
+
+// getAddrFromDNS 通过DNS查询获得公网地址, 如向 resolver1.opendns.com 查询 myip.opendns.com。
+// 每个查询的格式: DNS服务器 域名 [类型], 类型为 A/AAAA/TXT, 默认为 A(IPv4) 或 AAAA(IPv6)。
+// 多个查询以逗号分割, 依次查询直到成功
+func (conf *DnsConfig) getAddrFromDNS(addrType string) string {
+	queries, network, comp, defaultType := conf.Ipv4.DnsQuery, "udp4", Ipv4Reg, "A"
+	if addrType == "IPv6" {
+		queries, network, comp, defaultType = conf.Ipv6.DnsQuery, "udp6", Ipv6Reg, "AAAA"
+	}
+
+	for _, query := range strings.Split(queries, ",") {
+		fields := strings.Fields(query)
+		if len(fields) == 0 {
+			continue
+		}
+		result, err := queryAddr(network, fields, defaultType, comp.FindString)
+		if err != nil {
+			util.Log("通过DNS查询获取%s失败! 查询: %s", addrType, strings.Join(fields, " "))
+			util.Log("异常信息: %s", err)
+			continue
+		}
+		return result
+	}
+	return ""
+}
+
+// queryAddr 执行一个查询, 使用 find 从应答中提取地址
+func queryAddr(network string, fields []string, defaultType string, find func(string) string) (string, error) {
+	if len(fields) < 2 {
+		return "", errors.New("format: server name [type]")
+	}
+	typeName := defaultType
+	if len(fields) > 2 {
+		typeName = strings.ToUpper(fields[2])
+	}
+	qtype, ok := dnsQueryTypes[typeName]
+	if !ok {
+		return "", errors.New("unsupported type: " + typeName)
+	}
+
+	// 通过 network 限制地址族, 服务器返回的是对应地址族的地址
+	values, err := util.QueryDNS(network, fields[0], fields[1], qtype)
+	if err != nil {
+		return "", err
+	}
+	for _, value := range values {
+		if result := find(value); result != "" {
+			return result, nil
+		}
+	}
+	return "", errors.New("no address in answer: " + strings.Join(values, ", "))
+}
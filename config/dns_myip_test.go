@@ -0,0 +1,70 @@
+package config
+
+import (
+	"net"
+	"testing"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// startTestMyIPServer 启动一个仅用于测试的DNS服务器, 模拟 myip.opendns.com 及 o-o.myaddr.l.google.com
+func startTestMyIPServer(t *testing.T) string {
+	conn, err := net.ListenPacket("udp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	go func() {
+		buf := make([]byte, 512)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			var req dnsmessage.Message
+			if err := req.Unpack(buf[:n]); err != nil || len(req.Questions) == 0 {
+				continue
+			}
+			q := req.Questions[0]
+			header := dnsmessage.ResourceHeader{Name: q.Name, Type: q.Type, Class: dnsmessage.ClassINET}
+			resp := dnsmessage.Message{
+				Header:    dnsmessage.Header{ID: req.Header.ID, Response: true},
+				Questions: req.Questions,
+			}
+			switch q.Type {
+			case dnsmessage.TypeA:
+				resp.Answers = []dnsmessage.Resource{{Header: header, Body: &dnsmessage.AResource{A: [4]byte{203, 0, 113, 7}}}}
+			case dnsmessage.TypeTXT:
+				resp.Answers = []dnsmessage.Resource{{Header: header, Body: &dnsmessage.TXTResource{TXT: []string{"203.0.113.8"}}}}
+			}
+			packed, _ := resp.Pack()
+			conn.WriteTo(packed, addr)
+		}
+	}()
+
+	return conn.LocalAddr().String()
+}
+
+func TestGetAddrFromDNS(t *testing.T) {
+	server := startTestMyIPServer(t)
+
+	tests := []struct {
+		query    string
+		expected string
+	}{
+		{server + " myip.opendns.com", "203.0.113.7"},
+		{server + " o-o.myaddr.l.google.com txt", "203.0.113.8"},
+		// 第一个查询失败时使用下一个
+		{server + " myip.opendns.com MX, " + server + " myip.opendns.com A", "203.0.113.7"},
+		{server + " myip.opendns.com AAAA", ""},
+	}
+
+	for _, tt := range tests {
+		conf := &DnsConfig{}
+		conf.Ipv4.DnsQuery = tt.query
+		if result := conf.getAddrFromDNS("IPv4"); result != tt.expected {
+			t.Errorf("%s: 期待 %s, 得到 %s", tt.query, tt.expected, result)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"log/slog"
 	"net/url"
 	"strings"
 
@@ -12,11 +13,28 @@ import (
 // Domains Ipv4/Ipv6 domains
 type Domains struct {
 	Ipv4Addr    string
+	Ipv4Source  string // 获取到IPv4地址的来源
+	Ipv4OldAddr string // 上次获取到的IPv4地址
 	Ipv4Cache   *util.IpCache
 	Ipv4Domains []*Domain
 	Ipv6Addr    string
+	Ipv6Source  string // 获取到IPv6地址的来源
+	Ipv6OldAddr string // 上次获取到的IPv6地址
 	Ipv6Cache   *util.IpCache
 	Ipv6Domains []*Domain
+	// 本次是否将与DNS服务商比对
+	ipv4Compared bool
+	ipv6Compared bool
+	// 本次获取IP失败且达到通知次数
+	ipv4Failed bool
+	ipv6Failed bool
+	// 本次获取IP失败, Webhook 按失败计算连续失败次数
+	ipv4Failing bool
+	ipv6Failing bool
+	// 本次 A/AAAA 以外的记录更新失败, Webhook 按失败通知
+	recordsFailed bool
+	// 地址由IPv6前缀与固定后缀组成的局域网主机
+	ipv6Hosts []ipv6Host
 }
 
 // Domain 域名实体
@@ -27,6 +45,10 @@ type Domain struct {
 	SubDomain    string
 	CustomParams string
 	UpdateStatus updateStatusType // 更新状态
+	Drift        string           // 检测到漂移时DNS中的实际记录值
+	recordType   string           // 记录类型, 用于日志
+	addr         string           // 与本次获取的地址不同的记录值, 如局域网主机的地址
+	dnsConf      *DnsConfig       // 所属的DNS配置, 用于日志
 }
 
 // DomainTuples 域名元组映射 key: Domain.String()
@@ -60,6 +82,23 @@ func (d Domain) String() string {
 	return d.DomainName
 }
 
+// LogAttrs 日志的属性: 域名、记录类型、操作及所属的配置, 操作如 create/update/unchanged/query
+func (d *Domain) LogAttrs(action string) []slog.Attr {
+	attrs := []slog.Attr{slog.String("domain", d.String()), slog.String("action", action)}
+	if d.recordType != "" {
+		attrs = append(attrs, slog.String("recordType", d.recordType))
+	}
+	if d.dnsConf != nil {
+		attrs = append(attrs, d.dnsConf.LogAttrs()...)
+	}
+	return attrs
+}
+
+// LogAttrs 日志的属性: 配置名称及DNS服务商
+func (conf *DnsConfig) LogAttrs() []slog.Attr {
+	return []slog.Attr{slog.String("config", conf.Name), slog.String("provider", conf.DNS.Name)}
+}
+
 // GetFullDomain 获得全部的，子域名
 func (d Domain) GetFullDomain() string {
 	if d.SubDomain != "" {
@@ -101,37 +140,59 @@ func (d Domain) ToASCII() string {
 // GetNewIp 接口/网卡/命令获得 ip 并校验用户输入的域名
 func (domains *Domains) GetNewIp(dnsConf *DnsConfig) {
 	domains.Ipv4Domains = checkParseDomains(dnsConf.Ipv4.Domains)
-	domains.Ipv6Domains = checkParseDomains(dnsConf.Ipv6.Domains)
+	domains.Ipv6Domains, domains.ipv6Hosts = splitIpv6Hosts(checkParseDomains(dnsConf.Ipv6.Domains))
+	for _, domain := range domains.Ipv4Domains {
+		domain.recordType = "A"
+		domain.dnsConf = dnsConf
+	}
+	for _, domain := range domains.ipv6AllDomains() {
+		domain.recordType = "AAAA"
+		domain.dnsConf = dnsConf
+	}
 
 	// IPv4
 	if dnsConf.Ipv4.Enable && len(domains.Ipv4Domains) > 0 {
-		ipv4Addr := dnsConf.GetIpv4Addr()
+		ipv4Addr, source := dnsConf.getAddrWithFallback("IPv4")
 		if ipv4Addr != "" {
 			domains.Ipv4Addr = ipv4Addr
+			domains.Ipv4OldAddr = domains.Ipv4Cache.Addr
+			domains.Ipv4Source = source
 			domains.Ipv4Cache.TimesFailedIP = 0
+			detectDrift(dnsConf, domains.Ipv4Cache, domains.Ipv4Domains, ipv4Addr, "A")
+			domains.ipv4Compared = domains.Ipv4Cache.Addr != ipv4Addr || domains.Ipv4Cache.Times <= 1
 		} else {
 			// 启用IPv4 & 未获取到IP & 填写了域名 & 失败刚好3次，防止偶尔的网络连接失败，并且只发一次
 			domains.Ipv4Cache.TimesFailedIP++
+			domains.ipv4Failing = true
 			if domains.Ipv4Cache.TimesFailedIP == 3 {
 				domains.Ipv4Domains[0].UpdateStatus = UpdatedFailed
+				domains.ipv4Failed = true
 			}
-			util.Log("未能获取IPv4地址, 将不会更新")
+			util.LogAttrs(slog.LevelInfo, dnsConf.LogAttrs(), "未能获取IPv4地址, 将不会更新")
 		}
 	}
 
 	// IPv6
-	if dnsConf.Ipv6.Enable && len(domains.Ipv6Domains) > 0 {
-		ipv6Addr := dnsConf.GetIpv6Addr()
+	if dnsConf.Ipv6.Enable && (len(domains.Ipv6Domains) > 0 || len(domains.ipv6Hosts) > 0) {
+		ipv6Addr, source := dnsConf.getAddrWithFallback("IPv6")
 		if ipv6Addr != "" {
 			domains.Ipv6Addr = ipv6Addr
+			domains.Ipv6OldAddr = domains.Ipv6Cache.Addr
+			domains.Ipv6Source = source
 			domains.Ipv6Cache.TimesFailedIP = 0
+			detectDrift(dnsConf, domains.Ipv6Cache, domains.Ipv6Domains, ipv6Addr, "AAAA")
+			domains.ipv6Compared = domains.Ipv6Cache.Addr != ipv6Addr || domains.Ipv6Cache.Times <= 1
 		} else {
 			// 启用IPv6 & 未获取到IP & 填写了域名 & 失败刚好3次，防止偶尔的网络连接失败，并且只发一次
 			domains.Ipv6Cache.TimesFailedIP++
+			domains.ipv6Failing = true
 			if domains.Ipv6Cache.TimesFailedIP == 3 {
-				domains.Ipv6Domains[0].UpdateStatus = UpdatedFailed
+				domains.ipv6Failed = true
+				if all := domains.ipv6AllDomains(); len(all) > 0 {
+					all[0].UpdateStatus = UpdatedFailed
+				}
 			}
-			util.Log("未能获取IPv6地址, 将不会更新")
+			util.LogAttrs(slog.LevelInfo, dnsConf.LogAttrs(), "未能获取IPv6地址, 将不会更新")
 		}
 	}
 
@@ -214,6 +275,33 @@ func (domains *Domains) GetNewIpResult(recordType string) (ipAddr string, retDom
 	}
 }
 
+// RecordStatus 将每个域名的更新结果写入日志输出(syslog/journald/JSON)
+func (domains *Domains) RecordStatus() {
+	for _, family := range []struct {
+		recordType, addr string
+		domains          []*Domain
+	}{
+		{"A", domains.Ipv4Addr, domains.Ipv4Domains},
+		{"AAAA", domains.Ipv6Addr, domains.ipv6AllDomains()},
+	} {
+		for _, domain := range family.domains {
+			if domain.UpdateStatus == "" {
+				continue
+			}
+			fields := map[string]string{
+				"domain":     domain.String(),
+				"recordType": family.recordType,
+				"addr":       domain.addrOr(family.addr),
+				"status":     domain.UpdateStatus.Code(),
+			}
+			if domain.dnsConf != nil {
+				fields["config"], fields["provider"] = domain.dnsConf.Name, domain.dnsConf.DNS.Name
+			}
+			util.Record(fields, "域名 %s 的更新结果: %s", domain, util.LogStr(string(domain.UpdateStatus)))
+		}
+	}
+}
+
 // GetAllNewIpResult 获得getNewIp结果
 func (domains *Domains) GetAllNewIpResult(multiRecordType string) (results DomainTuples) {
 	ipv4Addr, ipv4Domains := domains.GetNewIpResult("A")
@@ -271,6 +359,11 @@ func (d *DomainTuple) SetUpdateStatus(status updateStatusType) {
 	}
 }
 
+// LogAttrs 日志的属性, 记录类型可能为多个, 如 A/AAAA
+func (d *DomainTuple) LogAttrs(action string) []slog.Attr {
+	return []slog.Attr{slog.String("domain", d.Primary.String()), slog.String("action", action), slog.String("recordType", d.RecordType)}
+}
+
 // GetIpAddrPool 设置更新状态
 func (d *DomainTuple) GetIpAddrPool(separator string) (result string) {
 	s := d.Primary.GetCustomParams().Get("IpAddrPool")
@@ -0,0 +1,114 @@
+package config
+
+import (
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jeessy2/ddns-go/v6/util"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+const (
+	// DriftByResolver 使用 -dns 指定的DNS服务器(或系统DNS)检测漂移
+	DriftByResolver = "resolver"
+	// DriftByAuthoritative 使用域名的权威DNS服务器检测漂移
+	DriftByAuthoritative = "authoritative"
+	// defaultDriftTTL 未设置TTL时, 使用DNS服务器缓存的最长时间(秒)
+	defaultDriftTTL = 600
+)
+
+// lookupNS 查询域名的权威DNS服务器, 测试时可替换
+var lookupNS = util.LookupNS
+
+// detectDrift IP未改变且将跳过与DNS服务商比对时, 通过DNS查询域名的实际解析。
+// 如发现与期望的IP不一致(如在DNS服务商处被手动修改), 记录到 Domain.Drift 并强制与DNS服务商比对
+func detectDrift(dnsConf *DnsConfig, cache *util.IpCache, domains []*Domain, ipAddr string, recordType string) {
+	if dnsConf.DriftDetect == "" || dnsConf.DNS.Name == "callback" {
+		return
+	}
+	// 将会与DNS服务商比对, 无需检测
+	if cache.Addr != ipAddr || cache.Times <= 1 {
+		return
+	}
+	// DNS服务器可能仍缓存着比对前的记录, TTL过后再检测
+	if dnsConf.DriftDetect != DriftByAuthoritative && time.Since(cache.ComparedAt) < dnsConf.driftTTL() {
+		return
+	}
+
+	if ip := net.ParseIP(ipAddr); ip != nil {
+		ipAddr = ip.String()
+	}
+
+	drifted := false
+	for _, domain := range domains {
+		// 自定义了 IpAddrPool 的域名值不确定, 跳过
+		if domain.GetCustomParams().Has("IpAddrPool") {
+			continue
+		}
+
+		values, err := lookupLive(dnsConf.DriftDetect, domain, recordType)
+		if err != nil {
+			util.Log("漂移检测查询域名 %s 失败! 异常信息: %s", domain, err)
+			continue
+		}
+		if slices.Contains(values, ipAddr) {
+			continue
+		}
+
+		domain.Drift = strings.Join(values, ",")
+		if domain.Drift == "" {
+			domain.Drift = util.LogStr("无记录")
+		}
+		drifted = true
+		util.Log("检测到域名 %s 的解析被修改! 期望: %s, 实际: %s", domain, ipAddr, domain.Drift)
+	}
+
+	if drifted {
+		// 下次 Check 时强制与DNS服务商比对
+		cache.Times = 0
+	}
+}
+
+// driftTTL 记录的TTL, 未设置或为自动时使用 defaultDriftTTL
+func (dnsConf *DnsConfig) driftTTL() time.Duration {
+	ttl, err := strconv.Atoi(dnsConf.TTL)
+	if err != nil || ttl <= 1 {
+		ttl = defaultDriftTTL
+	}
+	return time.Duration(ttl) * time.Second
+}
+
+// lookupLive 查询域名当前生效的记录值
+func lookupLive(mode string, domain *Domain, recordType string) ([]string, error) {
+	if mode == DriftByAuthoritative {
+		nameServers, err := lookupNS(domain.DomainName)
+		if err != nil {
+			return nil, err
+		}
+		qtype := dnsmessage.TypeA
+		if recordType == "AAAA" {
+			qtype = dnsmessage.TypeAAAA
+		}
+		for _, ns := range nameServers {
+			values, err := util.QueryDNS("udp", ns, domain.ToASCII(), qtype)
+			if err == nil {
+				return values, nil
+			}
+		}
+		return nil, &net.DNSError{Err: "no authoritative name server responded", Name: domain.String()}
+	}
+
+	network := "ip4"
+	if recordType == "AAAA" {
+		network = "ip6"
+	}
+	values, err := util.LookupIP(network, domain.ToASCII())
+	if dnsErr, ok := err.(*net.DNSError); ok && dnsErr.IsNotFound {
+		// 记录不存在也是漂移
+		return nil, nil
+	}
+	return values, err
+}
@@ -0,0 +1,128 @@
+package config
+
+import (
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jeessy2/ddns-go/v6/util"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// startTestDriftServer 启动一个仅用于测试的DNS服务器, 返回 records 中域名的A记录, 不存在时无应答
+func startTestDriftServer(t *testing.T, records map[string]string) string {
+	conn, err := net.ListenPacket("udp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	go func() {
+		buf := make([]byte, 512)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			var req dnsmessage.Message
+			if err := req.Unpack(buf[:n]); err != nil || len(req.Questions) == 0 {
+				continue
+			}
+			q := req.Questions[0]
+			resp := dnsmessage.Message{
+				Header:    dnsmessage.Header{ID: req.Header.ID, Response: true, Authoritative: true},
+				Questions: req.Questions,
+			}
+			value, ok := records[strings.TrimSuffix(q.Name.String(), ".")]
+			if ip := net.ParseIP(value).To4(); ok && ip != nil && q.Type == dnsmessage.TypeA {
+				header := dnsmessage.ResourceHeader{Name: q.Name, Type: q.Type, Class: dnsmessage.ClassINET, TTL: 60}
+				resp.Answers = []dnsmessage.Resource{{Header: header, Body: &dnsmessage.AResource{A: [4]byte(ip)}}}
+			}
+			packed, _ := resp.Pack()
+			conn.WriteTo(packed, addr)
+		}
+	}()
+
+	return conn.LocalAddr().String()
+}
+
+// TestDetectDrift 测试解析被修改或删除时记录实际值, 并强制下次与DNS服务商比对
+func TestDetectDrift(t *testing.T) {
+	server := startTestDriftServer(t, map[string]string{
+		"same.example.com":    "192.0.2.1",
+		"drifted.example.com": "192.0.2.9",
+	})
+	util.SetDNS("udp://" + server)
+	defer func(f func(string) ([]string, error)) { lookupNS = f }(lookupNS)
+	lookupNS = func(string) ([]string, error) { return []string{server}, nil }
+
+	tests := []struct {
+		name      string
+		mode      string
+		subDomain string
+		drift     string
+	}{
+		{"解析未修改", DriftByResolver, "same", ""},
+		{"解析被修改", DriftByResolver, "drifted", "192.0.2.9"},
+		{"记录不存在", DriftByResolver, "missing", util.LogStr("无记录")},
+		{"权威DNS解析未修改", DriftByAuthoritative, "same", ""},
+		{"权威DNS解析被修改", DriftByAuthoritative, "drifted", "192.0.2.9"},
+		{"权威DNS记录不存在", DriftByAuthoritative, "missing", util.LogStr("无记录")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dnsConf := &DnsConfig{DriftDetect: tt.mode, TTL: "60"}
+			cache := &util.IpCache{Addr: "192.0.2.1", Times: 3, ComparedAt: time.Now().Add(-2 * time.Minute)}
+			domain := &Domain{DomainName: "example.com", SubDomain: tt.subDomain}
+
+			detectDrift(dnsConf, cache, []*Domain{domain}, "192.0.2.1", "A")
+			if domain.Drift != tt.drift {
+				t.Errorf("期待 %q, 得到 %q", tt.drift, domain.Drift)
+			}
+			// 检测到漂移时重置剩余次数, 下次 Check 时与DNS服务商比对
+			if tt.drift == "" {
+				if cache.Times != 3 || cache.Check("192.0.2.1") {
+					t.Errorf("期待不强制比对, 剩余次数 %d", cache.Times)
+				}
+			} else if cache.Times != 0 || !cache.Check("192.0.2.1") {
+				t.Errorf("期待强制比对, 剩余次数 %d", cache.Times)
+			}
+		})
+	}
+}
+
+// TestDetectDriftSkip 测试无需检测的情况
+func TestDetectDriftSkip(t *testing.T) {
+	server := startTestDriftServer(t, map[string]string{"drifted.example.com": "192.0.2.9"})
+	util.SetDNS("udp://" + server)
+	defer func(f func(string) ([]string, error)) { lookupNS = f }(lookupNS)
+	lookupNS = func(string) ([]string, error) { return []string{server}, nil }
+
+	tests := []struct {
+		name         string
+		mode         string
+		cache        util.IpCache
+		customParams string
+		drift        bool
+	}{
+		{"未开启", "", util.IpCache{Addr: "192.0.2.1", Times: 3}, "", false},
+		{"IP已改变", DriftByResolver, util.IpCache{Addr: "192.0.2.2", Times: 3}, "", false},
+		{"将与DNS服务商比对", DriftByResolver, util.IpCache{Addr: "192.0.2.1", Times: 1}, "", false},
+		{"TTL内", DriftByResolver, util.IpCache{Addr: "192.0.2.1", Times: 3, ComparedAt: time.Now()}, "", false},
+		{"TTL后", DriftByResolver, util.IpCache{Addr: "192.0.2.1", Times: 3, ComparedAt: time.Now().Add(-2 * time.Minute)}, "", true},
+		{"权威DNS无需等待TTL", DriftByAuthoritative, util.IpCache{Addr: "192.0.2.1", Times: 3, ComparedAt: time.Now()}, "", true},
+		{"自定义IpAddrPool", DriftByAuthoritative, util.IpCache{Addr: "192.0.2.1", Times: 3}, "IpAddrPool=192.0.2.1", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dnsConf := &DnsConfig{DriftDetect: tt.mode, TTL: "60"}
+			domain := &Domain{DomainName: "example.com", SubDomain: "drifted", CustomParams: tt.customParams}
+
+			detectDrift(dnsConf, &tt.cache, []*Domain{domain}, "192.0.2.1", "A")
+			if (domain.Drift != "") != tt.drift {
+				t.Errorf("期待检测到漂移 %v, 得到 %q", tt.drift, domain.Drift)
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package config
+
+// DefaultTxtTemplate 心跳TXT记录的默认内容模板
+const DefaultTxtTemplate = "ts=#{timestamp};v4=#{ipv4Addr};v6=#{ipv6Addr};ver=#{version}"
+
+// GetHeartbeat 获得心跳TXT记录, 外部监控可通过记录中的时间戳判断ddns-go是否正常运行。
+// 未配置, 或本周期有更新失败、未获取到IP时返回 nil
+func (domains *Domains) GetHeartbeat(dnsConf *DnsConfig) *Record {
+	if dnsConf.TxtRecord == "" {
+		return nil
+	}
+
+	v4Status := getDomainsStatus(domains.Ipv4Domains)
+	v6Status := getDomainsStatus(domains.ipv6AllDomains())
+	if v4Status == UpdatedFailed || v6Status == UpdatedFailed {
+		return nil
+	}
+	if dnsConf.Ipv4.Enable && len(domains.Ipv4Domains) > 0 && domains.Ipv4Addr == "" {
+		return nil
+	}
+	if dnsConf.Ipv6.Enable && len(domains.ipv6AllDomains()) > 0 && domains.Ipv6Addr == "" {
+		return nil
+	}
+
+	parsed := checkParseDomains([]string{dnsConf.TxtRecord})
+	if len(parsed) == 0 {
+		return nil
+	}
+
+	parsed[0].recordType = "TXT"
+	parsed[0].dnsConf = dnsConf
+
+	template := dnsConf.TxtTemplate
+	if template == "" {
+		template = DefaultTxtTemplate
+	}
+	return &Record{
+		Type:   "TXT",
+		Domain: parsed[0],
+		Value:  replacePara(domains, template, v4Status, v6Status),
+	}
+}
@@ -0,0 +1,42 @@
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jeessy2/ddns-go/v6/util"
+)
+
+// TestGetHeartbeat 测试心跳TXT记录的生成
+func TestGetHeartbeat(t *testing.T) {
+	t.Setenv(util.VersionEnv, "v6.0.0")
+
+	dnsConf := &DnsConfig{TxtRecord: "_ddns.site1.example.com"}
+	dnsConf.Ipv4.Enable = true
+	domains := &Domains{
+		Ipv4Addr:    "1.1.1.1",
+		Ipv4Domains: []*Domain{{DomainName: "example.com", SubDomain: "www", UpdateStatus: UpdatedSuccess}},
+	}
+
+	record := domains.GetHeartbeat(dnsConf)
+	if record == nil {
+		t.Fatal("期待生成心跳记录")
+	}
+	if record.Type != "TXT" || record.Domain.String() != "_ddns.site1.example.com" {
+		t.Errorf("心跳记录不正确: %s", record)
+	}
+	if !strings.HasPrefix(record.Value, "ts=") || !strings.HasSuffix(record.Value, ";v4=1.1.1.1;v6=;ver=v6.0.0") {
+		t.Errorf("心跳记录值不正确: %s", record.Value)
+	}
+
+	domains.Ipv4Domains[0].UpdateStatus = UpdatedFailed
+	if domains.GetHeartbeat(dnsConf) != nil {
+		t.Error("期待更新失败时不生成心跳记录")
+	}
+
+	domains.Ipv4Domains[0].UpdateStatus = UpdatedNothing
+	domains.Ipv4Addr = ""
+	if domains.GetHeartbeat(dnsConf) != nil {
+		t.Error("期待未获取到IP时不生成心跳记录")
+	}
+}
@@ -0,0 +1,271 @@
+package config
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jeessy2/ddns-go/v6/util"
+)
+
+const (
+	// historyFileName 更新历史的文件名, 与配置文件在同一目录
+	historyFileName = ".ddns_go_history.jsonl"
+	// defaultHistoryKeepDays 默认保留天数
+	defaultHistoryKeepDays = 90
+	// defaultHistoryMaxEntries 默认最多保留的条数
+	defaultHistoryMaxEntries = 10000
+	// historyPruneInterval 清理过期历史的间隔
+	historyPruneInterval = time.Hour
+)
+
+// History 更新历史的保留设置
+type History struct {
+	// 保留天数, 为0则为90天
+	KeepDays int
+	// 最多保留的条数, 为0则为10000条
+	MaxEntries int
+}
+
+// HistoryEntry 一个域名的一次更新
+type HistoryEntry struct {
+	Time time.Time
+	// DNS配置的名称
+	Config     string `json:",omitempty"`
+	Provider   string
+	Domain     string
+	RecordType string
+	// 上次的值, 检测到漂移时为DNS中的实际记录值
+	OldValue string `json:",omitempty"`
+	NewValue string
+	// success/failed/unverified
+	Status string
+	// 本次更新的耗时(毫秒), 包括获取IP
+	Duration int64
+}
+
+// HistoryPage 分页查询的结果, 最新的在前
+type HistoryPage struct {
+	Total   int
+	Page    int
+	Size    int
+	Entries []HistoryEntry
+}
+
+// historyStore 追加写入的 JSON Lines 文件
+var historyStore struct {
+	sync.Mutex
+	lastPrune time.Time
+}
+
+func (h History) keepDays() int {
+	if h.KeepDays > 0 {
+		return h.KeepDays
+	}
+	return defaultHistoryKeepDays
+}
+
+func (h History) maxEntries() int {
+	if h.MaxEntries > 0 {
+		return h.MaxEntries
+	}
+	return defaultHistoryMaxEntries
+}
+
+// historyFilePath 更新历史的文件路径
+func historyFilePath() string {
+	return filepath.Join(filepath.Dir(util.GetConfigFilePath()), historyFileName)
+}
+
+// SaveHistory 保存本次成功、失败或未验证的域名及其它记录(SRV/SVCB/HTTPS/TXT/局域网主机), 未改变的不保存
+func (domains *Domains) SaveHistory(dnsConf *DnsConfig, history History, duration time.Duration, records []*Record) {
+	now := time.Now()
+	var entries []HistoryEntry
+	add := func(domain *Domain, recordType string, oldValue string, newValue string) {
+		if domain.UpdateStatus == "" || domain.UpdateStatus == UpdatedNothing {
+			return
+		}
+		if domain.Drift != "" {
+			oldValue = domain.Drift
+		}
+		entries = append(entries, HistoryEntry{
+			Time:       now,
+			Config:     dnsConf.Name,
+			Provider:   dnsConf.DNS.Name,
+			Domain:     domain.String(),
+			RecordType: recordType,
+			OldValue:   oldValue,
+			NewValue:   newValue,
+			Status:     domain.UpdateStatus.Code(),
+			Duration:   duration.Milliseconds(),
+		})
+	}
+	for _, domain := range domains.Ipv4Domains {
+		add(domain, "A", domains.Ipv4OldAddr, domains.Ipv4Addr)
+	}
+	for _, domain := range domains.Ipv6Domains {
+		add(domain, "AAAA", domains.Ipv6OldAddr, domains.Ipv6Addr)
+	}
+	for _, record := range records {
+		add(record.Domain, record.Type, "", record.Value)
+	}
+	if len(entries) == 0 {
+		return
+	}
+	fillOldValues(entries)
+	if err := AppendHistory(history, entries...); err != nil {
+		util.Log("更新历史保存失败! 异常信息: %s", err)
+	}
+}
+
+// fillOldValues 没有上次的值时, 如重启后、上次获取IP失败或其它记录, 使用历史中该记录最后一次更新成功的值
+func fillOldValues(entries []HistoryEntry) {
+	if !slices.ContainsFunc(entries, func(entry HistoryEntry) bool { return entry.OldValue == "" }) {
+		return
+	}
+	historyStore.Lock()
+	stored, err := readHistory()
+	historyStore.Unlock()
+	if err != nil {
+		return
+	}
+
+	last := map[string]string{}
+	for _, entry := range stored {
+		if entry.NewValue != "" && entry.Status != "failed" {
+			last[entry.Config+"|"+entry.Domain+"|"+entry.RecordType] = entry.NewValue
+		}
+	}
+	for i, entry := range entries {
+		if entry.OldValue == "" {
+			entries[i].OldValue = last[entry.Config+"|"+entry.Domain+"|"+entry.RecordType]
+		}
+	}
+}
+
+// AppendHistory 追加写入, 每小时清理一次超过保留天数或条数的历史
+func AppendHistory(history History, entries ...HistoryEntry) error {
+	historyStore.Lock()
+	defer historyStore.Unlock()
+
+	file, err := os.OpenFile(historyFilePath(), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+	err = writeHistory(file, entries)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	if time.Since(historyStore.lastPrune) < historyPruneInterval {
+		return nil
+	}
+	historyStore.lastPrune = time.Now()
+	return pruneHistory(history, time.Now())
+}
+
+// pruneHistory 删除超过保留天数的历史, 并只保留最新的 maxEntries 条
+func pruneHistory(history History, now time.Time) error {
+	entries, err := readHistory()
+	if err != nil {
+		return err
+	}
+	cutoff := now.AddDate(0, 0, -history.keepDays())
+	kept := slices.DeleteFunc(slices.Clone(entries), func(entry HistoryEntry) bool {
+		return entry.Time.Before(cutoff)
+	})
+	if len(kept) > history.maxEntries() {
+		kept = kept[len(kept)-history.maxEntries():]
+	}
+	if len(kept) == len(entries) {
+		return nil
+	}
+
+	path := historyFilePath()
+	file, err := os.CreateTemp(filepath.Dir(path), historyFileName+".*")
+	if err != nil {
+		return err
+	}
+	err = writeHistory(file, kept)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(file.Name(), path)
+	}
+	if err != nil {
+		os.Remove(file.Name())
+	}
+	return err
+}
+
+// writeHistory 每行一条
+func writeHistory(w io.Writer, entries []HistoryEntry) error {
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(bw)
+	for _, entry := range entries {
+		if err := enc.Encode(entry); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// readHistory 读取所有历史, 按写入顺序, 忽略不正确的行
+func readHistory() (entries []HistoryEntry, err error) {
+	file, err := os.Open(historyFilePath())
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var entry HistoryEntry
+		if json.Unmarshal(scanner.Bytes(), &entry) == nil {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, scanner.Err()
+}
+
+// GetHistory 分页查询更新历史, domain 不为空时只返回包含该字符串的域名, page 从1开始
+func GetHistory(domain string, page int, size int) (HistoryPage, error) {
+	historyStore.Lock()
+	entries, err := readHistory()
+	historyStore.Unlock()
+	if err != nil {
+		return HistoryPage{}, err
+	}
+
+	if domain != "" {
+		entries = slices.DeleteFunc(entries, func(entry HistoryEntry) bool {
+			return !strings.Contains(entry.Domain, domain)
+		})
+	}
+	slices.Reverse(entries)
+
+	result := HistoryPage{Total: len(entries), Page: max(page, 1), Size: size, Entries: []HistoryEntry{}}
+	// 页数过大时 (page-1)*size 可能溢出
+	start := len(entries)
+	if size > 0 && result.Page-1 < len(entries)/size+1 {
+		start = min((result.Page-1)*size, len(entries))
+	}
+	end := min(start+max(size, 0), len(entries))
+	result.Entries = append(result.Entries, entries[start:end]...)
+	return result, nil
+}
@@ -0,0 +1,152 @@
+package config
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jeessy2/ddns-go/v6/util"
+)
+
+// useTempHistory 将更新历史保存到临时目录
+func useTempHistory(t *testing.T) {
+	t.Setenv(util.ConfigFilePathENV, filepath.Join(t.TempDir(), ".ddns_go_config.yaml"))
+	historyStore.lastPrune = time.Time{}
+	t.Cleanup(func() { historyStore.lastPrune = time.Time{} })
+}
+
+func TestSaveHistory(t *testing.T) {
+	useTempHistory(t)
+
+	domains := &Domains{
+		Ipv4Addr:    "203.0.113.2",
+		Ipv4OldAddr: "203.0.113.1",
+		Ipv4Domains: []*Domain{
+			{DomainName: "example.com", SubDomain: "www", UpdateStatus: UpdatedSuccess},
+			{DomainName: "example.com", SubDomain: "nas", UpdateStatus: UpdatedNothing},
+		},
+		Ipv6Addr:    "2001:db8::2",
+		Ipv6Domains: []*Domain{{DomainName: "example.com", UpdateStatus: UpdatedFailed, Drift: "2001:db8::9"}},
+	}
+	dnsConf := &DnsConfig{Name: "home"}
+	dnsConf.DNS.Name = "cloudflare"
+	domains.SaveHistory(dnsConf, History{}, 1500*time.Millisecond, nil)
+
+	page, err := GetHistory("", 1, 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if page.Total != 2 || len(page.Entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %+v", page)
+	}
+	// 最新的在前, 同一次写入时按写入顺序倒序
+	v6, v4 := page.Entries[0], page.Entries[1]
+	if v4.Domain != "www.example.com" || v4.RecordType != "A" || v4.OldValue != "203.0.113.1" || v4.NewValue != "203.0.113.2" ||
+		v4.Status != "success" || v4.Provider != "cloudflare" || v4.Config != "home" || v4.Duration != 1500 {
+		t.Errorf("Unexpected entry %+v", v4)
+	}
+	if v6.Domain != "example.com" || v6.OldValue != "2001:db8::9" || v6.Status != "failed" {
+		t.Errorf("Unexpected entry %+v", v6)
+	}
+}
+
+// TestSaveHistoryRecords 测试保存其它记录, 没有上次的值时使用历史中最后一次成功的值
+func TestSaveHistoryRecords(t *testing.T) {
+	useTempHistory(t)
+	dnsConf := &DnsConfig{Name: "home"}
+
+	srv := &Domain{DomainName: "example.com", SubDomain: "_minecraft._tcp", UpdateStatus: UpdatedSuccess}
+	host := &Domain{DomainName: "example.com", SubDomain: "nas", UpdateStatus: UpdatedSuccess}
+	records := []*Record{
+		{Type: "SRV", Domain: srv, Value: "0 5 25565 mc.example.com"},
+		{Type: "AAAA", Domain: host, Value: "2001:db8::10"},
+	}
+	(&Domains{}).SaveHistory(dnsConf, History{}, 0, records)
+
+	// 重启后 Ipv4OldAddr 为空
+	srv.UpdateStatus, host.UpdateStatus = UpdatedFailed, UpdatedSuccess
+	records[0].Value, records[1].Value = "0 5 25566 mc.example.com", "2001:db8:1::10"
+	www := &Domain{DomainName: "example.com", SubDomain: "www", UpdateStatus: UpdatedSuccess}
+	(&Domains{Ipv4Addr: "203.0.113.1", Ipv4Domains: []*Domain{www}}).SaveHistory(dnsConf, History{}, 0, records)
+	records[0].Value = "0 5 25567 mc.example.com"
+	(&Domains{}).SaveHistory(dnsConf, History{}, 0, records[:1])
+
+	page, _ := GetHistory("", 1, 20)
+	if page.Total != 6 {
+		t.Fatalf("Expected 6 entries, got %+v", page)
+	}
+	expected := []struct{ recordType, oldValue, newValue string }{
+		{"SRV", "0 5 25565 mc.example.com", "0 5 25567 mc.example.com"},
+		{"AAAA", "2001:db8::10", "2001:db8:1::10"},
+		{"SRV", "0 5 25565 mc.example.com", "0 5 25566 mc.example.com"},
+		{"A", "", "203.0.113.1"},
+		{"AAAA", "", "2001:db8::10"},
+		{"SRV", "", "0 5 25565 mc.example.com"},
+	}
+	for i, e := range expected {
+		entry := page.Entries[i]
+		if entry.RecordType != e.recordType || entry.OldValue != e.oldValue || entry.NewValue != e.newValue {
+			t.Errorf("第 %d 条: 期待 %+v, 得到 %+v", i+1, e, entry)
+		}
+	}
+}
+
+func TestGetHistoryPage(t *testing.T) {
+	useTempHistory(t)
+
+	now := time.Now()
+	for i := 0; i < 5; i++ {
+		domain := "www.example.com"
+		if i%2 == 1 {
+			domain = "nas.example.com"
+		}
+		err := AppendHistory(History{}, HistoryEntry{Time: now.Add(time.Duration(i) * time.Minute), Domain: domain, NewValue: string(rune('a' + i))})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	page, _ := GetHistory("", 2, 2)
+	if page.Total != 5 || page.Page != 2 || len(page.Entries) != 2 || page.Entries[0].NewValue != "c" || page.Entries[1].NewValue != "b" {
+		t.Errorf("Unexpected page %+v", page)
+	}
+	page, _ = GetHistory("nas", 1, 20)
+	if page.Total != 2 || page.Entries[0].NewValue != "d" {
+		t.Errorf("Unexpected filtered page %+v", page)
+	}
+	page, _ = GetHistory("", 9, 2)
+	if page.Total != 5 || page.Entries == nil || len(page.Entries) != 0 {
+		t.Errorf("Expected an empty page, got %+v", page)
+	}
+	// (page-1)*size 溢出
+	page, _ = GetHistory("", math.MaxInt, 100)
+	if page.Total != 5 || len(page.Entries) != 0 {
+		t.Errorf("Expected an empty page, got %+v", page)
+	}
+}
+
+func TestPruneHistory(t *testing.T) {
+	useTempHistory(t)
+
+	now := time.Now()
+	entries := []HistoryEntry{
+		{Time: now.AddDate(0, 0, -10), NewValue: "old"},
+		{Time: now.AddDate(0, 0, -2), NewValue: "a"},
+		{Time: now.AddDate(0, 0, -1), NewValue: "b"},
+		{Time: now, NewValue: "c"},
+	}
+	if err := AppendHistory(History{KeepDays: 7, MaxEntries: 2}, entries...); err != nil {
+		t.Fatal(err)
+	}
+
+	page, _ := GetHistory("", 1, 20)
+	if page.Total != 2 || page.Entries[0].NewValue != "c" || page.Entries[1].NewValue != "b" {
+		t.Errorf("Unexpected entries after pruning %+v", page.Entries)
+	}
+	files, _ := os.ReadDir(filepath.Dir(historyFilePath()))
+	if len(files) != 1 {
+		t.Errorf("Expected the temporary file to be renamed, got %d files", len(files))
+	}
+}
@@ -0,0 +1,204 @@
+package config
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jeessy2/ddns-go/v6/util"
+)
+
+// defaultIpSourceTimeout 获取方式及未指定超时时间的备用来源的超时时间
+const defaultIpSourceTimeout = time.Minute
+
+// ipSource 获取IP的备用来源
+type ipSource struct {
+	GetType string
+	// 超时时间, 为0则使用 defaultIpSourceTimeout
+	Timeout time.Duration
+	// 参数, 为空则使用该获取方式在配置中的参数
+	Param string
+}
+
+// parseIpSources 解析备用来源, 每行格式: 类型[:超时秒数] [参数]。
+// 如 url:10 https://api.ipify.org
+func parseIpSources(addrType string, lines []string) (sources []ipSource) {
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		source, ok := parseIpSource(addrType, line)
+		if !ok {
+			util.Log("备用来源不正确: %s", line)
+			continue
+		}
+		sources = append(sources, source)
+	}
+	return
+}
+
+// parseIpSource 解析一行备用来源, 不正确时返回 false
+func parseIpSource(addrType string, line string) (ipSource, bool) {
+	head, param, _ := strings.Cut(line, " ")
+	getType, timeout, hasTimeout := strings.Cut(head, ":")
+	source := ipSource{GetType: getType, Param: strings.TrimSpace(param)}
+	if !isValidGetType(addrType, getType) {
+		return source, false
+	}
+	if hasTimeout {
+		seconds, err := strconv.Atoi(timeout)
+		if err != nil || seconds < 0 {
+			return source, false
+		}
+		source.Timeout = time.Duration(seconds) * time.Second
+	}
+	return source, true
+}
+
+// UsesNetInterface 获取方式或任一备用来源是否从网卡 ifName 获取地址
+func (conf *DnsConfig) UsesNetInterface(addrType string, ifName string) bool {
+	enable, getType, fallback := conf.Ipv4.Enable, conf.Ipv4.GetType, conf.Ipv4.Fallback
+	if addrType == "IPv6" {
+		enable, getType, fallback = conf.Ipv6.Enable, conf.Ipv6.GetType, conf.Ipv6.Fallback
+	}
+	if !enable {
+		return false
+	}
+
+	sources := []ipSource{{GetType: getType}}
+	for _, line := range fallback {
+		// 不正确的备用来源在获取IP时记录日志
+		if source, ok := parseIpSource(addrType, strings.TrimSpace(line)); ok {
+			sources = append(sources, source)
+		}
+	}
+	for _, s := range sources {
+		if s.GetType != "netInterface" {
+			continue
+		}
+		c := conf.withSource(addrType, s)
+		if addrType == "IPv6" && c.Ipv6.NetInterface == ifName || addrType == "IPv4" && c.Ipv4.NetInterface == ifName {
+			return true
+		}
+	}
+	return false
+}
+
+// isValidGetType 是否为支持的获取方式, router 仅支持IPv4
+func isValidGetType(addrType string, getType string) bool {
+	switch getType {
+	case "url", "netInterface", "cmd", "stun", "dns":
+		return true
+	case "router":
+		return addrType == "IPv4"
+	}
+	return false
+}
+
+// withSource 返回使用该来源获取地址的配置副本
+func (conf *DnsConfig) withSource(addrType string, source ipSource) *DnsConfig {
+	c := *conf
+	if addrType == "IPv4" {
+		c.Ipv4.GetType = source.GetType
+		if source.Param != "" {
+			switch source.GetType {
+			case "url":
+				c.Ipv4.URL = source.Param
+			case "netInterface":
+				c.Ipv4.NetInterface = source.Param
+			case "cmd":
+				c.Ipv4.Cmd = source.Param
+			case "stun":
+				c.Ipv4.Stun = source.Param
+			case "dns":
+				c.Ipv4.DnsQuery = source.Param
+			}
+		}
+		return &c
+	}
+
+	c.Ipv6.GetType = source.GetType
+	if source.Param != "" {
+		switch source.GetType {
+		case "url":
+			c.Ipv6.URL = source.Param
+		case "netInterface":
+			c.Ipv6.NetInterface = source.Param
+		case "cmd":
+			c.Ipv6.Cmd = source.Param
+		case "stun":
+			c.Ipv6.Stun = source.Param
+		case "dns":
+			c.Ipv6.DnsQuery = source.Param
+		}
+	}
+	return &c
+}
+
+// getAddrWithFallback 先使用获取方式, 失败或地址不允许发布时依次尝试备用来源。
+// 返回地址及其来源
+func (conf *DnsConfig) getAddrWithFallback(addrType string) (string, string) {
+	getType, fallback, get := conf.Ipv4.GetType, conf.Ipv4.Fallback, (*DnsConfig).GetIpv4Addr
+	allowPrivate, allow, deny := conf.Ipv4.AllowPrivate, conf.Ipv4.AllowCIDR, conf.Ipv4.DenyCIDR
+	if addrType == "IPv6" {
+		getType, fallback, get = conf.Ipv6.GetType, conf.Ipv6.Fallback, (*DnsConfig).GetIpv6Addr
+		allowPrivate, allow, deny = conf.Ipv6.AllowPrivate, conf.Ipv6.AllowCIDR, conf.Ipv6.DenyCIDR
+	}
+
+	sources := append([]ipSource{{GetType: getType}}, parseIpSources(addrType, fallback)...)
+	for i, s := range sources {
+		if i > 0 {
+			util.Log("尝试通过备用来源 %s 获取%s", s.GetType, addrType)
+		}
+
+		c := conf.withSource(addrType, s)
+		addr, ok := getWithTimeout(s.Timeout, func(ctx context.Context) string { return get(c, ctx) })
+		if !ok {
+			util.Log("通过 %s 获取%s超时", s.GetType, addrType)
+			continue
+		}
+		if addr == "" {
+			continue
+		}
+
+		if err := checkAddr(addr, allowPrivate, allow, deny); err != nil {
+			util.Log("获取到的%s地址 %s 不允许发布! 原因: %s", addrType, addr, err)
+			continue
+		}
+		source := s.GetType
+		if c.source != "" {
+			source += " " + c.source
+		}
+		if len(sources) > 1 {
+			util.Log("%s地址 %s 来自: %s", addrType, addr, source)
+		}
+		// 命令可通过输出的JSON修改TTL
+		conf.TTL = c.TTL
+		return addr, source
+	}
+	return "", ""
+}
+
+// getWithTimeout 在超时时间内获取地址, 超时返回 false。
+// 超时后取消 ctx, 中断接口请求并结束命令的进程
+func getWithTimeout(timeout time.Duration, get func(ctx context.Context) string) (string, bool) {
+	if timeout <= 0 {
+		timeout = defaultIpSourceTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	result := make(chan string, 1)
+	go func() {
+		result <- get(ctx)
+	}()
+
+	select {
+	case addr := <-result:
+		return addr, true
+	case <-ctx.Done():
+		return "", false
+	}
+}
@@ -0,0 +1,73 @@
+package config
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestParseIpSources(t *testing.T) {
+	sources := parseIpSources("IPv6", []string{
+		"url:10 https://api6.ipify.org",
+		"",
+		"cmd  ip -6 addr show ppp0 ",
+		"router",
+		"unknown",
+		"stun:abc",
+	})
+	expected := []ipSource{
+		{GetType: "url", Timeout: 10 * time.Second, Param: "https://api6.ipify.org"},
+		{GetType: "cmd", Param: "ip -6 addr show ppp0"},
+	}
+	if len(sources) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, sources)
+	}
+	for i := range expected {
+		if sources[i] != expected[i] {
+			t.Errorf("Expected %v, got %v", expected[i], sources[i])
+		}
+	}
+}
+
+func TestGetAddrWithFallback(t *testing.T) {
+	conf := &DnsConfig{}
+	conf.Ipv4.GetType = "netInterface"
+	conf.Ipv4.NetInterface = "ddns-go-not-exist"
+	conf.Ipv4.AllowCIDR = "203.0.113.0/24"
+	url := startTestIPServer(t, "203.0.113.1")
+	conf.Ipv4.Fallback = []string{
+		"url " + startTestIPServer(t, "198.51.100.9"),
+		"url:1 " + url,
+	}
+
+	addr, source := conf.getAddrWithFallback("IPv4")
+	if addr != "203.0.113.1" || source != "url "+url {
+		t.Errorf("Expected 203.0.113.1 from url, got %s from %s", addr, source)
+	}
+
+	conf.Ipv4.Fallback = nil
+	if addr, source := conf.getAddrWithFallback("IPv4"); addr != "" || source != "" {
+		t.Errorf("Expected no address, got %s from %s", addr, source)
+	}
+}
+
+func TestGetWithTimeout(t *testing.T) {
+	if addr, ok := getWithTimeout(0, func(context.Context) string { return "203.0.113.1" }); !ok || addr != "203.0.113.1" {
+		t.Errorf("Expected 203.0.113.1, got %s", addr)
+	}
+
+	// 超时后取消 ctx
+	canceled := make(chan struct{})
+	if _, ok := getWithTimeout(10*time.Millisecond, func(ctx context.Context) string {
+		<-ctx.Done()
+		close(canceled)
+		return ""
+	}); ok {
+		t.Error("Expected timeout")
+	}
+	select {
+	case <-canceled:
+	case <-time.After(time.Second):
+		t.Error("Expected the context to be canceled")
+	}
+}
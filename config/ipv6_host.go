@@ -0,0 +1,141 @@
+package config
+
+import (
+	"errors"
+	"net"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/jeessy2/ddns-go/v6/util"
+)
+
+// 局域网主机的自定义参数
+const (
+	// ipv6SuffixParam 接口标识(后缀), 如 ::1234:5678:9abc:def0
+	ipv6SuffixParam = "Ipv6Suffix"
+	// ipv6MacParam MAC地址, 使用 EUI-64 生成接口标识
+	ipv6MacParam = "Ipv6Mac"
+	// ipv6PrefixLenParam 前缀长度, 默认 64
+	ipv6PrefixLenParam = "Ipv6PrefixLen"
+)
+
+// ipv6Host 局域网主机, 地址由当前IPv6的前缀与固定的接口标识组成
+type ipv6Host struct {
+	Domain    *Domain
+	suffix    net.IP
+	prefixLen int
+}
+
+// Addr 使用 prefix 的前 prefixLen 位与接口标识的其余位组成地址
+func (h ipv6Host) Addr(prefix net.IP) string {
+	prefix = prefix.To16()
+	addr := make(net.IP, net.IPv6len)
+	for i := range addr {
+		bits := min(max(h.prefixLen-i*8, 0), 8)
+		mask := byte(0xff << (8 - bits))
+		addr[i] = prefix[i]&mask | h.suffix[i]&^mask
+	}
+	return addr.String()
+}
+
+// ipv6AllDomains IPv6域名及局域网主机, 用于状态、通知及更新历史
+func (domains *Domains) ipv6AllDomains() []*Domain {
+	all := slices.Clip(domains.Ipv6Domains)
+	for _, host := range domains.ipv6Hosts {
+		all = append(all, host.Domain)
+	}
+	return all
+}
+
+// addrOr 域名的记录值, 未单独设置时为 addr
+func (d *Domain) addrOr(addr string) string {
+	if d.addr != "" {
+		return d.addr
+	}
+	return addr
+}
+
+// splitIpv6Hosts 分离出指定了 Ipv6Suffix 或 Ipv6Mac 的域名。
+// 这些参数会从自定义参数中移除, 不会传递给DNS服务商
+func splitIpv6Hosts(all []*Domain) (domains []*Domain, hosts []ipv6Host) {
+	for _, domain := range all {
+		params := domain.GetCustomParams()
+		if !params.Has(ipv6SuffixParam) && !params.Has(ipv6MacParam) {
+			domains = append(domains, domain)
+			continue
+		}
+
+		host, err := parseIpv6Host(params)
+		if err != nil {
+			util.Log("域名 %s 的IPv6后缀不正确! 异常信息: %s", domain, err)
+			continue
+		}
+		for _, key := range []string{ipv6SuffixParam, ipv6MacParam, ipv6PrefixLenParam} {
+			params.Del(key)
+		}
+		domain.CustomParams = params.Encode()
+		host.Domain = domain
+		hosts = append(hosts, host)
+	}
+	return
+}
+
+// HasIpv6Hosts 是否有指定了 Ipv6Suffix 或 Ipv6Mac 的局域网主机
+func HasIpv6Hosts(lines []string) bool {
+	for _, line := range lines {
+		_, query, ok := strings.Cut(line, "?")
+		if !ok {
+			continue
+		}
+		params, err := url.ParseQuery(query)
+		if err == nil && (params.Has(ipv6SuffixParam) || params.Has(ipv6MacParam)) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseIpv6Host 从自定义参数中解析接口标识及前缀长度
+func parseIpv6Host(params url.Values) (host ipv6Host, err error) {
+	host.prefixLen = 64
+	if s := params.Get(ipv6PrefixLenParam); s != "" {
+		host.prefixLen, err = strconv.Atoi(s)
+		if err != nil || host.prefixLen < 0 || host.prefixLen > 128 {
+			return host, errors.New("invalid prefix length: " + s)
+		}
+	}
+
+	if s := params.Get(ipv6SuffixParam); s != "" {
+		host.suffix = net.ParseIP(s)
+		if host.suffix == nil || host.suffix.To4() != nil {
+			return host, errors.New("invalid suffix: " + s)
+		}
+		return host, nil
+	}
+
+	mac, err := net.ParseMAC(params.Get(ipv6MacParam))
+	if err != nil {
+		return host, err
+	}
+	if len(mac) != 6 {
+		return host, errors.New("EUI-64 requires a 48-bit MAC address")
+	}
+	if host.prefixLen > 64 {
+		return host, errors.New("EUI-64 requires a prefix length of at most 64")
+	}
+	host.suffix = eui64(mac)
+	return host, nil
+}
+
+// eui64 由48位MAC地址生成修改后的 EUI-64 接口标识, RFC 4291 附录A
+func eui64(mac net.HardwareAddr) net.IP {
+	ip := make(net.IP, net.IPv6len)
+	copy(ip[8:11], mac[0:3])
+	ip[11], ip[12] = 0xff, 0xfe
+	copy(ip[13:16], mac[3:6])
+	// 翻转 U/L 位
+	ip[8] ^= 0x02
+	return ip
+}
@@ -0,0 +1,66 @@
+package config
+
+import (
+	"net"
+	"testing"
+)
+
+func TestIpv6HostAddr(t *testing.T) {
+	prefix := net.ParseIP("2001:db8:1234:5601::1")
+
+	tests := []struct {
+		domain   string
+		expected string
+	}{
+		{"nas.example.com?Ipv6Suffix=::1234:5678:9abc:def0", "2001:db8:1234:5601:1234:5678:9abc:def0"},
+		{"pc.example.com?Ipv6Mac=00:11:22:33:44:55", "2001:db8:1234:5601:211:22ff:fe33:4455"},
+		{"srv.example.com?Ipv6Suffix=::2:0:0:0:10&Ipv6PrefixLen=56", "2001:db8:1234:5602::10"},
+	}
+
+	for _, tt := range tests {
+		domains, hosts := splitIpv6Hosts(checkParseDomains([]string{tt.domain}))
+		if len(domains) != 0 || len(hosts) != 1 {
+			t.Fatalf("%s: 期待解析为局域网主机", tt.domain)
+		}
+		if addr := hosts[0].Addr(prefix); addr != tt.expected {
+			t.Errorf("%s: 期待 %s, 得到 %s", tt.domain, tt.expected, addr)
+		}
+		if hosts[0].Domain.CustomParams != "" {
+			t.Errorf("%s: 期待移除自定义参数, 得到 %s", tt.domain, hosts[0].Domain.CustomParams)
+		}
+	}
+
+	domains, hosts := splitIpv6Hosts(checkParseDomains([]string{
+		"www.example.com?TTL=600",
+		"bad.example.com?Ipv6Mac=00:11:22:33:44:55&Ipv6PrefixLen=80",
+	}))
+	if len(domains) != 1 || len(hosts) != 0 {
+		t.Errorf("期待 1 个普通域名及 0 个局域网主机, 得到 %d, %d", len(domains), len(hosts))
+	}
+}
+
+// TestIpv6HostStatus 测试局域网主机包含在更新状态中, 并使用自己的地址
+func TestIpv6HostStatus(t *testing.T) {
+	if !HasIpv6Hosts([]string{"www.example.com", "nas.example.com?TTL=600&Ipv6Suffix=::10"}) {
+		t.Error("期待包含局域网主机")
+	}
+	if HasIpv6Hosts([]string{"www.example.com?TTL=600"}) {
+		t.Error("期待不包含局域网主机")
+	}
+
+	domains := &Domains{Ipv6Addr: "2001:db8::1"}
+	domains.Ipv6Domains, domains.ipv6Hosts = splitIpv6Hosts(checkParseDomains([]string{"nas.example.com?Ipv6Suffix=::10"}))
+	records := domains.GetRecords(&DnsConfig{})
+	if len(records) != 1 || records[0].Value != "2001:db8::10" {
+		t.Fatalf("期待 1 条 AAAA 记录, 得到 %v", records)
+	}
+	records[0].Domain.UpdateStatus = UpdatedSuccess
+
+	all := domains.ipv6AllDomains()
+	if status := getDomainsStatus(all); status != UpdatedSuccess {
+		t.Errorf("期待 %s, 得到 %s", UpdatedSuccess, status)
+	}
+	if addr := all[0].addrOr(domains.Ipv6Addr); addr != "2001:db8::10" {
+		t.Errorf("期待 2001:db8::10, 得到 %s", addr)
+	}
+}
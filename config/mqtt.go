@@ -0,0 +1,301 @@
+package config
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jeessy2/ddns-go/v6/util"
+	"github.com/jeessy2/ddns-go/v6/util/mqtt"
+)
+
+// Mqtt MQTT配置, 每次运行后发布保留的状态消息及 Home Assistant 的自动发现配置
+type Mqtt struct {
+	// mqtt://host:1883 或 mqtts://host:8883, 为空则不发布
+	URL      string
+	Username string
+	Password string
+	// 主题前缀, 为空则为 ddns-go
+	TopicPrefix string
+	// Home Assistant 自动发现的前缀, 为空则为 homeassistant, 为 - 则不发布
+	DiscoveryPrefix string
+}
+
+// mqttConn 当前的连接, 配置改变或断开后重新连接
+var mqttConn struct {
+	sync.Mutex
+	client *mqtt.Client
+	conf   Mqtt
+}
+
+// mqttSlugReg 主题及 Home Assistant ID 中不允许的字符
+var mqttSlugReg = regexp.MustCompile(`[^a-zA-Z0-9_-]+`)
+
+func mqttSlug(s string) string {
+	return strings.Trim(mqttSlugReg.ReplaceAllString(strings.ToLower(s), "_"), "_")
+}
+
+// topicPrefix 主题前缀
+func (m Mqtt) topicPrefix() string {
+	if m.TopicPrefix == "" {
+		return "ddns-go"
+	}
+	return strings.TrimSuffix(m.TopicPrefix, "/")
+}
+
+// availabilityTopic 在线状态的主题, 值为 online/offline
+func (m Mqtt) availabilityTopic() string {
+	return m.topicPrefix() + "/status"
+}
+
+// mqttJob 等待发布的消息
+type mqttJob struct {
+	conf Mqtt
+	msgs []mqtt.Message
+}
+
+// mqttQueue 每个DNS配置只保留最新的一次等待发布的状态, running 时后台发布协程在运行
+var mqttQueue = struct {
+	sync.Mutex
+	pending map[int]mqttJob
+	// order 等待发布的DNS配置, 按加入的顺序
+	order   []int
+	running bool
+}{pending: map[int]mqttJob{}}
+
+// PublishMqtt 在后台发布第 index 个DNS配置及其域名的状态, 连接服务器不会阻塞DNS的更新。
+// 未发布的旧状态将被本次的状态替换
+func PublishMqtt(index int, domains *Domains, conf *Config, dnsConf *DnsConfig, v4Status updateStatusType, v6Status updateStatusType) {
+	job := mqttJob{conf: conf.Mqtt}
+	if conf.Mqtt.URL != "" {
+		job.msgs = conf.Mqtt.messages(index, domains, dnsConf, v4Status, v6Status)
+	}
+
+	mqttQueue.Lock()
+	defer mqttQueue.Unlock()
+	if _, ok := mqttQueue.pending[index]; !ok {
+		mqttQueue.order = append(mqttQueue.order, index)
+	}
+	mqttQueue.pending[index] = job
+	if !mqttQueue.running {
+		mqttQueue.running = true
+		go runMqttQueue()
+	}
+}
+
+// runMqttQueue 按顺序发布等待发布的状态, 发布完后退出
+func runMqttQueue() {
+	for {
+		mqttQueue.Lock()
+		if len(mqttQueue.order) == 0 {
+			mqttQueue.running = false
+			mqttQueue.Unlock()
+			return
+		}
+		index := mqttQueue.order[0]
+		mqttQueue.order = mqttQueue.order[1:]
+		job := mqttQueue.pending[index]
+		delete(mqttQueue.pending, index)
+		mqttQueue.Unlock()
+
+		job.publish()
+	}
+}
+
+// publish 配置改变或断开后重新连接, 并发布消息
+func (job mqttJob) publish() {
+	mqttConn.Lock()
+	defer mqttConn.Unlock()
+
+	if mqttConn.client != nil && (mqttConn.conf != job.conf || mqttConn.client.Closed()) {
+		closeMqtt()
+	}
+	if job.conf.URL == "" {
+		return
+	}
+	if mqttConn.client == nil {
+		client, err := dialMqtt(job.conf)
+		if err != nil {
+			util.Log("MQTT连接失败! 异常信息：%s", err)
+			return
+		}
+		mqttConn.client, mqttConn.conf = client, job.conf
+	}
+
+	if err := mqttConn.client.Publish(job.msgs...); err != nil {
+		util.Log("MQTT发布失败! 异常信息：%s", err)
+		closeMqtt()
+	}
+}
+
+// dialMqtt 连接服务器, 遗嘱消息为 offline, 连接后发布 online
+func dialMqtt(m Mqtt) (*mqtt.Client, error) {
+	hostname, _ := os.Hostname()
+	availability := m.availabilityTopic()
+	client, err := mqtt.Dial(mqtt.Options{
+		Broker:    m.URL,
+		ClientID:  "ddns-go-" + mqttSlug(hostname+"-"+m.topicPrefix()),
+		Username:  m.Username,
+		Password:  m.Password,
+		Will:      &mqtt.Message{Topic: availability, Payload: []byte("offline"), Retain: true},
+		TLSConfig: &tls.Config{InsecureSkipVerify: util.IsInsecureSkipVerify()},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err = client.Publish(mqtt.Message{Topic: availability, Payload: []byte("online"), Retain: true}); err != nil {
+		client.Close()
+		return nil, err
+	}
+	return client, nil
+}
+
+// closeMqtt 发布 offline 后断开连接
+func closeMqtt() {
+	mqttConn.client.Publish(mqtt.Message{Topic: mqttConn.conf.availabilityTopic(), Payload: []byte("offline"), Retain: true})
+	mqttConn.client.Close()
+	mqttConn.client = nil
+}
+
+// mqttConfState DNS配置的状态
+type mqttConfState struct {
+	Name        string `json:"name"`
+	DNS         string `json:"dns"`
+	Ipv4        string `json:"ipv4"`
+	Ipv4Source  string `json:"ipv4_source"`
+	Ipv4Result  string `json:"ipv4_result"`
+	Ipv6        string `json:"ipv6"`
+	Ipv6Source  string `json:"ipv6_source"`
+	Ipv6Result  string `json:"ipv6_result"`
+	LastUpdated string `json:"last_updated"`
+}
+
+// mqttDomainState 域名的状态
+type mqttDomainState struct {
+	Domain     string `json:"domain"`
+	RecordType string `json:"record_type"`
+	Addr       string `json:"addr"`
+	Status     string `json:"status"`
+	Drift      string `json:"drift"`
+}
+
+// mqttDiscovery Home Assistant 的 sensor 自动发现配置
+type mqttDiscovery struct {
+	Name                string         `json:"name"`
+	UniqueID            string         `json:"unique_id"`
+	ObjectID            string         `json:"object_id"`
+	StateTopic          string         `json:"state_topic"`
+	ValueTemplate       string         `json:"value_template"`
+	JSONAttributesTopic string         `json:"json_attributes_topic,omitempty"`
+	AvailabilityTopic   string         `json:"availability_topic"`
+	Icon                string         `json:"icon,omitempty"`
+	Device              map[string]any `json:"device"`
+}
+
+// messages 第 index 个DNS配置的状态及自动发现配置。
+// 主题: 前缀/配置ID/state, 前缀/配置ID/ipv4|ipv6/域名/state
+func (m Mqtt) messages(index int, domains *Domains, dnsConf *DnsConfig, v4Status updateStatusType, v6Status updateStatusType) (msgs []mqtt.Message) {
+	confID := mqttSlug(dnsConf.Name)
+	if confID == "" {
+		confID = "config" + strconv.Itoa(index+1)
+	}
+	prefix := m.topicPrefix()
+	confTopic := prefix + "/" + confID
+
+	state := mqttConfState{
+		Name:        dnsConf.Name,
+		DNS:         dnsConf.DNS.Name,
+		Ipv4:        domains.Ipv4Addr,
+		Ipv4Source:  domains.Ipv4Source,
+		Ipv4Result:  v4Status.Code(),
+		Ipv6:        domains.Ipv6Addr,
+		Ipv6Source:  domains.Ipv6Source,
+		Ipv6Result:  v6Status.Code(),
+		LastUpdated: time.Now().Format(time.RFC3339),
+	}
+	type sensor struct {
+		objectID, name, stateTopic, valueTemplate, attributesTopic, icon string
+	}
+	sensors := []sensor{}
+	if dnsConf.Ipv4.Enable {
+		sensors = append(sensors,
+			sensor{"ipv4", "IPv4", confTopic + "/state", "{{ value_json.ipv4 }}", "", "mdi:ip-network"},
+			sensor{"ipv4_result", "IPv4 result", confTopic + "/state", "{{ value_json.ipv4_result }}", "", ""},
+		)
+	}
+	if dnsConf.Ipv6.Enable {
+		sensors = append(sensors,
+			sensor{"ipv6", "IPv6", confTopic + "/state", "{{ value_json.ipv6 }}", "", "mdi:ip-network"},
+			sensor{"ipv6_result", "IPv6 result", confTopic + "/state", "{{ value_json.ipv6_result }}", "", ""},
+		)
+	}
+	msgs = append(msgs, mqttJSON(confTopic+"/state", state))
+
+	for _, family := range []struct {
+		name, recordType, addr string
+		domains                []*Domain
+	}{
+		{"ipv4", "A", domains.Ipv4Addr, domains.Ipv4Domains},
+		{"ipv6", "AAAA", domains.Ipv6Addr, domains.ipv6AllDomains()},
+	} {
+		for _, domain := range family.domains {
+			topic := confTopic + "/" + family.name + "/" + strings.NewReplacer("/", "_", "+", "_", "#", "_").Replace(domain.String()) + "/state"
+			msgs = append(msgs, mqttJSON(topic, mqttDomainState{
+				Domain:     domain.String(),
+				RecordType: family.recordType,
+				Addr:       domain.addrOr(family.addr),
+				Status:     domain.UpdateStatus.Code(),
+				Drift:      domain.Drift,
+			}))
+			sensors = append(sensors, sensor{
+				family.name + "_" + mqttSlug(domain.String()), domain.String() + " " + family.recordType,
+				topic, "{{ value_json.status }}", topic, "mdi:dns",
+			})
+		}
+	}
+
+	if m.DiscoveryPrefix == "-" {
+		return
+	}
+	discoveryPrefix := m.DiscoveryPrefix
+	if discoveryPrefix == "" {
+		discoveryPrefix = "homeassistant"
+	}
+	nodeID := mqttSlug(prefix) + "_" + confID
+	deviceName := "ddns-go"
+	if dnsConf.Name != "" {
+		deviceName += " " + dnsConf.Name
+	}
+	device := map[string]any{
+		"identifiers":  []string{nodeID},
+		"name":         deviceName,
+		"manufacturer": "ddns-go",
+		"model":        dnsConf.DNS.Name,
+		"sw_version":   os.Getenv(util.VersionEnv),
+	}
+	for _, s := range sensors {
+		msgs = append(msgs, mqttJSON(discoveryPrefix+"/sensor/"+nodeID+"/"+s.objectID+"/config", mqttDiscovery{
+			Name:                s.name,
+			UniqueID:            nodeID + "_" + s.objectID,
+			ObjectID:            nodeID + "_" + s.objectID,
+			StateTopic:          s.stateTopic,
+			ValueTemplate:       s.valueTemplate,
+			JSONAttributesTopic: s.attributesTopic,
+			AvailabilityTopic:   m.availabilityTopic(),
+			Icon:                s.icon,
+			Device:              device,
+		}))
+	}
+	return
+}
+
+// mqttJSON 保留的JSON消息
+func mqttJSON(topic string, v any) mqtt.Message {
+	payload, _ := json.Marshal(v)
+	return mqtt.Message{Topic: topic, Payload: payload, Retain: true}
+}
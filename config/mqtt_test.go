@@ -0,0 +1,86 @@
+package config
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestMqttMessages(t *testing.T) {
+	dnsConf := &DnsConfig{Name: "Home NAS"}
+	dnsConf.DNS.Name = "cloudflare"
+	dnsConf.Ipv4.Enable = true
+	domains := &Domains{
+		Ipv4Addr:   "203.0.113.1",
+		Ipv4Source: "url",
+		Ipv4Domains: []*Domain{
+			{DomainName: "example.com", SubDomain: "www", UpdateStatus: UpdatedSuccess},
+		},
+	}
+
+	msgs := Mqtt{}.messages(0, domains, dnsConf, UpdatedSuccess, UpdatedNothing)
+	payloads := map[string]map[string]any{}
+	for _, msg := range msgs {
+		if !msg.Retain {
+			t.Errorf("Expected retained message %s", msg.Topic)
+		}
+		payload := map[string]any{}
+		if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+			t.Fatalf("Invalid payload %s: %v", msg.Topic, err)
+		}
+		payloads[msg.Topic] = payload
+	}
+
+	state := payloads["ddns-go/home_nas/state"]
+	if state["ipv4"] != "203.0.113.1" || state["ipv4_result"] != "success" || state["ipv6_result"] != "unchanged" {
+		t.Errorf("Unexpected state %v", state)
+	}
+	domain := payloads["ddns-go/home_nas/ipv4/www.example.com/state"]
+	if domain["status"] != "success" || domain["record_type"] != "A" || domain["addr"] != "203.0.113.1" {
+		t.Errorf("Unexpected domain state %v", domain)
+	}
+	discovery := payloads["homeassistant/sensor/ddns-go_home_nas/ipv4_www_example_com/config"]
+	if discovery["state_topic"] != "ddns-go/home_nas/ipv4/www.example.com/state" || discovery["availability_topic"] != "ddns-go/status" {
+		t.Errorf("Unexpected discovery %v", discovery)
+	}
+	if _, ok := payloads["homeassistant/sensor/ddns-go_home_nas/ipv6/config"]; ok {
+		t.Error("Expected no IPv6 sensor when IPv6 is disabled")
+	}
+	if len(msgs) != 5 {
+		t.Errorf("Expected 5 messages, got %d", len(msgs))
+	}
+
+	msgs = Mqtt{TopicPrefix: "home/ddns/", DiscoveryPrefix: "-"}.messages(1, domains, &DnsConfig{}, UpdatedSuccess, UpdatedNothing)
+	if len(msgs) != 2 || msgs[0].Topic != "home/ddns/config2/state" {
+		t.Errorf("Unexpected messages %v", msgs)
+	}
+}
+
+func TestPublishMqttCoalesce(t *testing.T) {
+	// 模拟后台发布协程正在运行, 只检查等待发布的状态
+	mqttQueue.Lock()
+	mqttQueue.running = true
+	mqttQueue.Unlock()
+	t.Cleanup(func() {
+		mqttQueue.Lock()
+		mqttQueue.pending, mqttQueue.order, mqttQueue.running = map[int]mqttJob{}, nil, false
+		mqttQueue.Unlock()
+	})
+
+	conf := &Config{Mqtt: Mqtt{URL: "mqtt://127.0.0.1:1", DiscoveryPrefix: "-"}}
+	dnsConf := &DnsConfig{Name: "home"}
+	for _, addr := range []string{"203.0.113.1", "203.0.113.2"} {
+		PublishMqtt(0, &Domains{Ipv4Addr: addr}, conf, dnsConf, UpdatedSuccess, UpdatedNothing)
+	}
+	PublishMqtt(1, &Domains{Ipv4Addr: "203.0.113.3"}, conf, &DnsConfig{Name: "office"}, UpdatedSuccess, UpdatedNothing)
+
+	mqttQueue.Lock()
+	defer mqttQueue.Unlock()
+	if len(mqttQueue.order) != 2 || mqttQueue.order[0] != 0 || mqttQueue.order[1] != 1 {
+		t.Fatalf("Expected configs 0 and 1 pending, got %v", mqttQueue.order)
+	}
+	state := map[string]any{}
+	json.Unmarshal(mqttQueue.pending[0].msgs[0].Payload, &state)
+	if state["ipv4"] != "203.0.113.2" {
+		t.Errorf("Expected the latest state 203.0.113.2, got %v", state["ipv4"])
+	}
+}
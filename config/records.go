@@ -0,0 +1,359 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jeessy2/ddns-go/v6/util"
+	"github.com/jeessy2/ddns-go/v6/util/nat"
+)
+
+// Record A/AAAA 以外的托管记录, 随IP变化一起更新
+type Record struct {
+	// Type 记录类型, 如 HTTPS/SVCB/SRV
+	Type   string
+	Domain *Domain
+	// Value 记录值, 格式与区域文件一致, 如 1 . alpn=h2 ipv4hint=1.1.1.1
+	Value string
+}
+
+// SvcbRecord 用户配置的 HTTPS/SVCB 记录, 地址提示由 ddns-go 生成
+type SvcbRecord struct {
+	Type     string
+	Domain   *Domain
+	Priority int
+	Target   string
+	// Params 除 ipv4hint/ipv6hint 外的参数, 如 alpn=h2,h3 port=443
+	Params []string
+}
+
+// SrvRecord 用户配置的 SRV 记录
+type SrvRecord struct {
+	Domain   *Domain
+	Priority int
+	Weight   int
+	Target   string
+	// Port 端口来源: 端口号, cmd:命令, upnp:内部端口, natpmp:内部端口
+	Port string
+}
+
+// publishedRecords 已成功发布的记录值 key: Record.key()
+var publishedRecords = struct {
+	sync.Mutex
+	values map[string]string
+}{values: map[string]string{}}
+
+// natpmpLifetime NAT-PMP 端口映射的有效期(秒), 有效期过半后续期
+const natpmpLifetime = 7200
+
+// portReg 从命令输出中匹配端口
+var portReg = regexp.MustCompile(`\d+`)
+
+// String 用于日志
+func (r Record) String() string {
+	return r.Type + " " + r.Domain.String()
+}
+
+func (r Record) key(dnsName string) string {
+	return dnsName + " " + r.String()
+}
+
+// SetPublished 记录已成功发布的记录值, 值未改变且IP未改变时不再更新
+func (r Record) SetPublished(dnsName string) {
+	publishedRecords.Lock()
+	defer publishedRecords.Unlock()
+	publishedRecords.values[r.key(dnsName)] = r.Value
+}
+
+// isPublished 记录值是否已发布
+func (r Record) isPublished(dnsName string) bool {
+	publishedRecords.Lock()
+	defer publishedRecords.Unlock()
+	return publishedRecords.values[r.key(dnsName)] == r.Value
+}
+
+// SetRecordsFailed 标记本次 A/AAAA 以外的记录更新失败
+func (domains *Domains) SetRecordsFailed() {
+	domains.recordsFailed = true
+}
+
+// Value 生成记录值, 地址为空时不添加对应的地址提示。
+// AliasMode(优先级为0)的记录不能带参数
+func (r SvcbRecord) Value(ipv4Addr, ipv6Addr string) string {
+	parts := []string{strconv.Itoa(r.Priority), r.Target}
+	if r.Priority == 0 {
+		return strings.Join(parts, " ")
+	}
+	parts = append(parts, r.Params...)
+	if ipv4Addr != "" {
+		parts = append(parts, "ipv4hint="+ipv4Addr)
+	}
+	if ipv6Addr != "" {
+		parts = append(parts, "ipv6hint="+ipv6Addr)
+	}
+	return strings.Join(parts, " ")
+}
+
+// parseSvcbRecords 解析用户输入的 HTTPS/SVCB 记录。
+// 格式: 类型 域名 优先级 目标 [参数...], 如 HTTPS www.example.com 1 . alpn=h2,h3 port=443
+func parseSvcbRecords(lines []string) (records []SvcbRecord) {
+	for _, line := range lines {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) < 4 {
+			util.Log("记录: %s 不正确", line)
+			continue
+		}
+
+		recordType := strings.ToUpper(fields[0])
+		if recordType != "HTTPS" && recordType != "SVCB" {
+			util.Log("记录: %s 不正确", line)
+			continue
+		}
+		domains := checkParseDomains(fields[1:2])
+		if len(domains) == 0 {
+			continue
+		}
+		priority, err := strconv.ParseUint(fields[2], 10, 16)
+		if err != nil {
+			util.Log("记录: %s 不正确", line)
+			continue
+		}
+
+		record := SvcbRecord{
+			Type:     recordType,
+			Domain:   domains[0],
+			Priority: int(priority),
+			Target:   fields[3],
+		}
+		for _, param := range fields[4:] {
+			// 地址提示由 ddns-go 生成
+			key, _, _ := strings.Cut(param, "=")
+			if key == "ipv4hint" || key == "ipv6hint" {
+				continue
+			}
+			record.Params = append(record.Params, param)
+		}
+		records = append(records, record)
+	}
+	return
+}
+
+// parseSrvRecords 解析用户输入的 SRV 记录。
+// 格式: 域名 优先级 权重 目标 端口来源, 如 _minecraft._tcp.example.com 0 5 mc.example.com upnp:25565
+func parseSrvRecords(lines []string) (records []SrvRecord) {
+	for _, line := range lines {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) < 5 {
+			util.Log("记录: %s 不正确", line)
+			continue
+		}
+
+		domains := checkParseDomains(fields[0:1])
+		if len(domains) == 0 {
+			continue
+		}
+		priority, err1 := strconv.ParseUint(fields[1], 10, 16)
+		weight, err2 := strconv.ParseUint(fields[2], 10, 16)
+		if err1 != nil || err2 != nil {
+			util.Log("记录: %s 不正确", line)
+			continue
+		}
+
+		records = append(records, SrvRecord{
+			Domain:   domains[0],
+			Priority: int(priority),
+			Weight:   int(weight),
+			Target:   fields[3],
+			// 命令中可以有空格
+			Port: strings.Join(fields[4:], " "),
+		})
+	}
+	return
+}
+
+// protocol 从域名 _service._proto.name 中获得协议
+func (r SrvRecord) protocol() string {
+	for _, label := range strings.Split(r.Domain.String(), ".") {
+		if strings.EqualFold(label, "_tcp") || strings.EqualFold(label, "_udp") {
+			return label
+		}
+	}
+	return "_tcp"
+}
+
+// Value 获得端口并生成记录值
+func (r SrvRecord) Value(resolver *portResolver) (string, error) {
+	port, err := resolver.resolve(r.Port, r.protocol())
+	if err != nil {
+		return "", err
+	}
+	return strconv.Itoa(r.Priority) + " " + strconv.Itoa(r.Weight) + " " + strconv.Itoa(port) + " " + r.Target, nil
+}
+
+// portResolver 获得 SRV 记录的端口。
+// 在各周期间缓存网关的发现结果及 NAT-PMP 映射, 避免每个周期重新发现网关及创建映射
+type portResolver struct {
+	mu      sync.Mutex
+	igd     *nat.IGD
+	gateway net.IP
+	// mappings 已创建的 NAT-PMP 映射 key: 协议/内部端口
+	mappings map[string]natpmpMapping
+}
+
+// natpmpMapping 已创建的 NAT-PMP 映射, 有效期过半后续期
+type natpmpMapping struct {
+	port    int
+	renewAt time.Time
+}
+
+// srvPorts 所有配置共用
+var srvPorts = &portResolver{}
+
+// resolve 按端口来源获得端口
+func (pr *portResolver) resolve(source string, protocol string) (port int, err error) {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+
+	kind, param, found := strings.Cut(source, ":")
+	if !found {
+		port, err = strconv.Atoi(source)
+	} else {
+		switch kind {
+		case "cmd":
+			port, err = portFromCmd(param)
+		case "upnp":
+			port, err = pr.upnp(param, protocol)
+		case "natpmp":
+			port, err = pr.natpmp(param, protocol)
+		default:
+			err = errors.New("unknown port source: " + kind)
+		}
+	}
+	if err == nil && (port <= 0 || port > 65535) {
+		err = errors.New("invalid port: " + strconv.Itoa(port))
+	}
+	return
+}
+
+// upnp 查询网关上已有的端口映射, 失败时下次重新发现网关
+func (pr *portResolver) upnp(internalPort string, protocol string) (int, error) {
+	port, err := strconv.Atoi(internalPort)
+	if err != nil {
+		return 0, err
+	}
+	if pr.igd == nil {
+		pr.igd, err = nat.DiscoverIGD(3 * time.Second)
+		if err != nil {
+			return 0, err
+		}
+	}
+	port, err = pr.igd.GetExternalPort(protocol, port)
+	if err != nil {
+		pr.igd = nil
+	}
+	return port, err
+}
+
+// natpmp NAT-PMP 不能查询已有的映射, 因此使用已创建的映射, 有效期过半后再续期
+func (pr *portResolver) natpmp(internalPort string, protocol string) (int, error) {
+	port, err := strconv.Atoi(internalPort)
+	if err != nil {
+		return 0, err
+	}
+	key := protocol + "/" + internalPort
+	if mapping, ok := pr.mappings[key]; ok && time.Now().Before(mapping.renewAt) {
+		return mapping.port, nil
+	}
+
+	if pr.gateway == nil {
+		pr.gateway, err = nat.DefaultGateway()
+		if err != nil {
+			return 0, err
+		}
+	}
+	port, err = nat.NatpmpMapPort(pr.gateway, protocol, port, natpmpLifetime)
+	if err != nil {
+		pr.gateway = nil
+		delete(pr.mappings, key)
+		return 0, err
+	}
+	if pr.mappings == nil {
+		pr.mappings = map[string]natpmpMapping{}
+	}
+	pr.mappings[key] = natpmpMapping{port: port, renewAt: time.Now().Add(natpmpLifetime * time.Second / 2)}
+	return port, nil
+}
+
+// portFromCmd 执行命令, 使用输出中的第一个数字作为端口, 超时时间与获取IP的命令一致
+func portFromCmd(cmd string) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), cmdTimeout)
+	defer cancel()
+	execCmd := shellCommandContext(ctx, cmd)
+	// 命令的子进程未退出时, 超时后不再等待其输出
+	execCmd.WaitDelay = time.Second
+	out, err := execCmd.Output()
+	if err != nil {
+		if ctx.Err() != nil {
+			err = ctx.Err()
+		}
+		return 0, err
+	}
+	result := portReg.FindString(string(out))
+	if result == "" {
+		return 0, errors.New("no port in output: " + string(out))
+	}
+	return strconv.Atoi(result)
+}
+
+// GetRecords 获得需要随IP一起更新的记录。
+// 本次与DNS服务商比对IP时, 或记录值与上次发布的不同时返回。
+// 获取 SRV 记录的端口失败时也返回, 并标记为更新失败
+func (domains *Domains) GetRecords(dnsConf *DnsConfig) (records []*Record) {
+	var all []*Record
+	for _, svcb := range parseSvcbRecords(dnsConf.Svcb) {
+		all = append(all, &Record{
+			Type:   svcb.Type,
+			Domain: svcb.Domain,
+			Value:  svcb.Value(domains.Ipv4Addr, domains.Ipv6Addr),
+		})
+	}
+
+	if prefix := net.ParseIP(domains.Ipv6Addr); prefix != nil {
+		for _, host := range domains.ipv6Hosts {
+			host.Domain.addr = host.Addr(prefix)
+			all = append(all, &Record{Type: "AAAA", Domain: host.Domain, Value: host.Domain.addr})
+		}
+	}
+
+	for _, srv := range parseSrvRecords(dnsConf.Srv) {
+		value, err := srv.Value(srvPorts)
+		if err != nil {
+			util.Log("获取 SRV 记录 %s 的端口失败! 异常信息: %s", srv.Domain, err)
+			// 仍然返回, 以便计为更新失败
+			srv.Domain.UpdateStatus = UpdatedFailed
+		}
+		all = append(all, &Record{Type: "SRV", Domain: srv.Domain, Value: value})
+	}
+
+	compared := domains.ipv4Compared || domains.ipv6Compared
+	for _, record := range all {
+		record.Domain.recordType = record.Type
+		record.Domain.dnsConf = dnsConf
+		if compared || record.Domain.UpdateStatus == UpdatedFailed || !record.isPublished(dnsConf.DNS.Name) {
+			records = append(records, record)
+		}
+	}
+	return
+}
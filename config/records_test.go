@@ -0,0 +1,100 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+// TestSvcbRecordValue 测试 HTTPS/SVCB 记录的解析及地址提示的生成
+func TestSvcbRecordValue(t *testing.T) {
+	tests := []struct {
+		line     string
+		domain   string
+		ipv4Addr string
+		ipv6Addr string
+		expected string
+	}{
+		{"HTTPS www.example.com 1 . alpn=h2,h3 port=443", "www.example.com", "1.1.1.1", "2001:db8::1",
+			"1 . alpn=h2,h3 port=443 ipv4hint=1.1.1.1 ipv6hint=2001:db8::1"},
+		{"https example.com 1 . ipv4hint=9.9.9.9", "example.com", "1.1.1.1", "",
+			"1 . ipv4hint=1.1.1.1"},
+		{"SVCB _8443._https.example.com 2 svc.example.com port=8443", "_8443._https.example.com", "", "2001:db8::1",
+			"2 svc.example.com port=8443 ipv6hint=2001:db8::1"},
+		{"HTTPS alias.example.com 0 www.example.com alpn=h2", "alias.example.com", "1.1.1.1", "2001:db8::1",
+			"0 www.example.com"},
+	}
+
+	for _, tt := range tests {
+		records := parseSvcbRecords([]string{tt.line})
+		if len(records) != 1 {
+			t.Fatalf("解析 %s 失败", tt.line)
+		}
+		if records[0].Domain.String() != tt.domain {
+			t.Errorf("期待域名: %s, 得到: %s", tt.domain, records[0].Domain)
+		}
+		if value := records[0].Value(tt.ipv4Addr, tt.ipv6Addr); value != tt.expected {
+			t.Errorf("期待记录值: %s, 得到: %s", tt.expected, value)
+		}
+	}
+
+	invalid := []string{"", "HTTPS www.example.com 1", "TXT www.example.com 1 .", "HTTPS www.example.com high ."}
+	if records := parseSvcbRecords(invalid); len(records) != 0 {
+		t.Errorf("期待忽略不正确的记录, 得到: %v", records)
+	}
+}
+
+// TestSrvRecordValue 测试 SRV 记录的解析及端口的获取
+func TestSrvRecordValue(t *testing.T) {
+	records := parseSrvRecords([]string{
+		"_sip._udp.example.com 10 5 sip.example.com 5060",
+		"_minecraft._tcp.example.com 0 5 mc.example.com cmd:echo port 25565",
+		"_bad._tcp.example.com 0 5 mc.example.com",
+	})
+	if len(records) != 2 {
+		t.Fatalf("期待解析出 2 条记录, 得到 %d 条", len(records))
+	}
+	if records[0].protocol() != "_udp" || records[1].protocol() != "_tcp" {
+		t.Errorf("协议解析失败: %s, %s", records[0].protocol(), records[1].protocol())
+	}
+
+	expected := []string{"10 5 5060 sip.example.com", "0 5 25565 mc.example.com"}
+	resolver := &portResolver{}
+	for i, record := range records {
+		value, err := record.Value(resolver)
+		if err != nil {
+			t.Fatalf("获取端口失败: %s", err)
+		}
+		if value != expected[i] {
+			t.Errorf("期待记录值: %s, 得到: %s", expected[i], value)
+		}
+	}
+
+	if _, err := resolver.resolve("70000", "_tcp"); err == nil {
+		t.Error("期待端口超出范围时返回错误")
+	}
+}
+
+// TestGetRecordsSrvPortFailed 测试获取 SRV 记录的端口失败时仍返回记录, 并计为失败
+func TestGetRecordsSrvPortFailed(t *testing.T) {
+	domains := &Domains{}
+	records := domains.GetRecords(&DnsConfig{Srv: []string{"_minecraft._tcp.example.com 0 5 mc.example.com cmd:echo none"}})
+	if len(records) != 1 || records[0].Domain.UpdateStatus != UpdatedFailed {
+		t.Fatalf("期待 1 条更新失败的 SRV 记录, 得到 %v", records)
+	}
+
+	domains.SetRecordsFailed()
+	if !domains.isFailed(UpdatedNothing, UpdatedNothing) {
+		t.Error("期待其它记录更新失败时 Webhook 计为失败")
+	}
+}
+
+// TestNatpmpMappingCache 测试有效期过半前使用已创建的 NAT-PMP 映射
+func TestNatpmpMappingCache(t *testing.T) {
+	resolver := &portResolver{mappings: map[string]natpmpMapping{
+		"_tcp/25565": {port: 35565, renewAt: time.Now().Add(time.Hour)},
+	}}
+	port, err := resolver.resolve("natpmp:25565", "_tcp")
+	if err != nil || port != 35565 {
+		t.Errorf("期待使用已创建的映射 35565, 得到 %d, %v", port, err)
+	}
+}
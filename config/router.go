@@ -0,0 +1,59 @@
+package config
+
+import (
+	"errors"
+	"net"
+	"time"
+
+	"github.com/jeessy2/ddns-go/v6/util"
+	"github.com/jeessy2/ddns-go/v6/util/nat"
+)
+
+// cgnatNet 运营商级NAT使用的共享地址, RFC 6598
+var cgnatNet = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// getIpv4AddrFromRouter 查询上游路由器的外部IPv4地址。
+// 依次尝试 UPnP IGD、NAT-PMP、PCP
+func (conf *DnsConfig) getIpv4AddrFromRouter() string {
+	ip, err := routerExternalAddress()
+	if err != nil {
+		util.Log("从路由器获得IPv4失败! 异常信息: %s", err)
+		return ""
+	}
+
+	if isPrivateOrShared(ip) {
+		util.Log("路由器的外部地址 %s 为私有地址或运营商级NAT地址, 可能存在多层NAT, 外网可能无法访问", ip)
+	}
+	return ip.String()
+}
+
+// routerExternalAddress 获得路由器的外部地址
+func routerExternalAddress() (net.IP, error) {
+	igd, err := nat.DiscoverIGD(3 * time.Second)
+	if err == nil {
+		var ip net.IP
+		if ip, err = igd.GetExternalIPAddress(); err == nil {
+			return ip, nil
+		}
+	}
+	util.Log("通过UPnP获取路由器的外部地址失败, 将尝试NAT-PMP/PCP! 异常信息: %s", err)
+
+	gateway, err := nat.DefaultGateway()
+	if err != nil {
+		return nil, err
+	}
+	ip, natpmpErr := nat.NatpmpExternalAddress(gateway)
+	if natpmpErr == nil {
+		return ip, nil
+	}
+	ip, pcpErr := nat.PcpExternalAddress(gateway)
+	if pcpErr != nil {
+		return nil, errors.Join(natpmpErr, pcpErr)
+	}
+	return ip, nil
+}
+
+// isPrivateOrShared 是否为私有地址或运营商级NAT地址
+func isPrivateOrShared(ip net.IP) bool {
+	return ip.IsPrivate() || ip.IsLoopback() || ip.IsLinkLocalUnicast() || cgnatNet.Contains(ip)
+}
@@ -0,0 +1,25 @@
+package config
+
+import (
+	"net"
+	"testing"
+)
+
+func TestIsPrivateOrShared(t *testing.T) {
+	tests := []struct {
+		ip       string
+		expected bool
+	}{
+		{"192.168.1.1", true},
+		{"10.0.0.1", true},
+		{"100.64.0.1", true},
+		{"100.127.255.254", true},
+		{"100.128.0.1", false},
+		{"203.0.113.1", false},
+	}
+	for _, tt := range tests {
+		if result := isPrivateOrShared(net.ParseIP(tt.ip)); result != tt.expected {
+			t.Errorf("%s: 期待 %v, 得到 %v", tt.ip, tt.expected, result)
+		}
+	}
+}
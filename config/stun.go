@@ -0,0 +1,51 @@
+package config
+
+import (
+	"net"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jeessy2/ddns-go/v6/util"
+	"github.com/jeessy2/ddns-go/v6/util/nat"
+)
+
+// stunTimeout 单个 STUN 服务器的超时时间
+const stunTimeout = 3 * time.Second
+
+// getAddrFromStun 依次查询 STUN 服务器, 返回NAT后的公网地址。
+// 开启 StunConsensus 时, 需要两个服务器返回相同的地址
+func (conf *DnsConfig) getAddrFromStun(addrType string) string {
+	servers, consensus, network := conf.Ipv4.Stun, conf.Ipv4.StunConsensus, "udp4"
+	if addrType == "IPv6" {
+		servers, consensus, network = conf.Ipv6.Stun, conf.Ipv6.StunConsensus, "udp6"
+	}
+
+	var results []string
+	for _, server := range strings.Split(servers, ",") {
+		server = strings.TrimSpace(server)
+		if server == "" {
+			continue
+		}
+		ip, err := nat.StunMappedAddress(network, server, stunTimeout)
+		if err == nil && (ip.To4() != nil) != (addrType == "IPv4") {
+			err = &net.AddrError{Err: "unexpected address family", Addr: ip.String()}
+		}
+		if err != nil {
+			util.Log("通过STUN服务器获取%s失败! 服务器: %s", addrType, server)
+			util.Log("异常信息: %s", err)
+			continue
+		}
+
+		addr := ip.String()
+		if !consensus || slices.Contains(results, addr) {
+			return addr
+		}
+		results = append(results, addr)
+	}
+
+	if consensus && len(results) > 0 {
+		util.Log("没有两个STUN服务器返回相同的%s! 返回值: %s", addrType, strings.Join(results, ", "))
+	}
+	return ""
+}
@@ -0,0 +1,103 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/jeessy2/ddns-go/v6/util"
+)
+
+// parseUrlQuorum 解析共识数量, 为空或不正确时返回 0, 即不启用共识模式
+func parseUrlQuorum(quorum string) int {
+	k, err := strconv.Atoi(strings.TrimSpace(quorum))
+	if err != nil || k < 0 {
+		return 0
+	}
+	return k
+}
+
+// getAddrFromUrlQuorum 并行查询所有接口, 至少 quorum 个接口返回相同的地址时才使用该地址。
+// 防止某个接口返回错误、缓存的或代理的地址。返回地址及返回该地址的接口
+func getAddrFromUrlQuorum(ctx context.Context, addrType string, client *http.Client, urls []string, comp *regexp.Regexp, quorum int) (string, []string) {
+	var (
+		mu    sync.Mutex
+		wg    sync.WaitGroup
+		votes = map[string][]string{}
+	)
+	for _, url := range urls {
+		url = strings.TrimSpace(url)
+		if url == "" {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			addr, err := fetchAddr(ctx, client, url, comp)
+			if err != nil {
+				util.Log("通过接口获取%s失败! 接口地址: %s", addrType, url)
+				util.Log("异常信息: %s", err)
+				return
+			}
+			mu.Lock()
+			votes[addr] = append(votes[addr], url)
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	// 按票数从多到少排序
+	addrs := make([]string, 0, len(votes))
+	for addr := range votes {
+		addrs = append(addrs, addr)
+	}
+	sort.Slice(addrs, func(i, j int) bool {
+		return len(votes[addrs[i]]) > len(votes[addrs[j]])
+	})
+
+	if len(addrs) > 1 {
+		details := make([]string, 0, len(addrs))
+		for _, addr := range addrs {
+			details = append(details, addr+" ("+strings.Join(votes[addr], ", ")+")")
+		}
+		util.Log("接口返回的%s不一致: %s", addrType, strings.Join(details, "; "))
+	}
+
+	if len(addrs) == 0 {
+		return "", nil
+	}
+	best := len(votes[addrs[0]])
+	if best < quorum {
+		util.Log("仅有 %d 个接口返回相同的%s, 少于要求的 %d 个, 将不会更新", best, addrType, quorum)
+		return "", nil
+	}
+	if len(addrs) > 1 && len(votes[addrs[1]]) == best {
+		util.Log("返回不同%s的接口数量相同(各 %d 个), 无法确定地址, 将不会更新", addrType, best)
+		return "", nil
+	}
+	return addrs[0], votes[addrs[0]]
+}
+
+// fetchAddr 请求接口并从返回值中匹配地址
+func fetchAddr(ctx context.Context, client *http.Client, url string, comp *regexp.Regexp) (string, error) {
+	// 状态码为300及以上时返回错误, 如代理的错误页面
+	body, err := util.GetHTTPResponseOrg(getWithContext(ctx, client, url))
+	if err != nil {
+		return "", err
+	}
+	result := comp.FindString(string(body))
+	if result == "" {
+		return "", fmt.Errorf("no address in response: %s", body)
+	}
+	// 统一IPv6的格式以便比较
+	if ip := net.ParseIP(result); ip != nil {
+		result = ip.String()
+	}
+	return result, nil
+}
@@ -0,0 +1,53 @@
+package config
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// startTestIPServer 启动一个仅用于测试的接口, 返回固定的IP
+func startTestIPServer(t *testing.T, ip string) string {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "Your IP: "+ip)
+	}))
+	t.Cleanup(server.Close)
+	return server.URL
+}
+
+func TestGetAddrFromUrlQuorum(t *testing.T) {
+	good1 := startTestIPServer(t, "203.0.113.1")
+	good2 := startTestIPServer(t, "203.0.113.1")
+	proxy := startTestIPServer(t, "198.51.100.9")
+	broken := startTestIPServer(t, "")
+	// 返回错误页面的代理, 页面中包含地址
+	errorPage := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+		io.WriteString(w, "Bad gateway from 198.51.100.9")
+	}))
+	t.Cleanup(errorPage.Close)
+
+	tests := []struct {
+		name     string
+		urls     []string
+		quorum   int
+		expected string
+	}{
+		{"Agree", []string{good1, proxy, good2}, 2, "203.0.113.1"},
+		{"Not enough", []string{good1, proxy, broken}, 2, ""},
+		{"Tie", []string{good1, proxy}, 1, ""},
+		{"Single", []string{good1, broken}, 1, "203.0.113.1"},
+		{"Error status", []string{good1, errorPage.URL}, 1, "203.0.113.1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, _ := getAddrFromUrlQuorum(context.Background(), "IPv4", http.DefaultClient, tt.urls, Ipv4Reg, tt.quorum)
+			if result != tt.expected {
+				t.Errorf("期待 %q, 得到 %q", tt.expected, result)
+			}
+		})
+	}
+}
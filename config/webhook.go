@@ -4,18 +4,73 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/url"
+	"os"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/jeessy2/ddns-go/v6/notify"
 	"github.com/jeessy2/ddns-go/v6/util"
 )
 
 // Webhook Webhook
 type Webhook struct {
-	WebhookURL         string
-	WebhookRequestBody string
-	WebhookHeaders     string
+	// 兼容之前的配置文件, 已迁移到 Webhooks
+	WebhookURL         string `yaml:"webhookurl,omitempty"`
+	WebhookRequestBody string `yaml:"webhookrequestbody,omitempty"`
+	WebhookHeaders     string `yaml:"webhookheaders,omitempty"`
+	Webhooks           []WebhookConfig
 }
 
+// WebhookConfig 单个Webhook或内置的通知渠道
+type WebhookConfig struct {
+	// 为空则为Webhook, 否则为内置的通知渠道: telegram/slack/discord/dingtalk/feishu/wecom/ntfy/gotify/bark/email
+	Type        string
+	URL         string
+	RequestBody string
+	Headers     string
+	// 通知渠道的 Token/Chat ID/加签密钥, Webhook 使用 Secret 签名
+	Token  string
+	Target string
+	Secret string
+	// 失败时的重试次数
+	Retries int
+	// 每次发送的超时时间(秒), 为0则为30秒
+	Timeout int
+	// 连续失败该次数后通知, 为0则为3次
+	FailedTimes int
+	// 通知失败后每隔该小时数提醒一次, 为0则不提醒
+	RemindHours int
+	// 使用 Go text/template 渲染 URL 及 RequestBody, 否则替换 #{} 变量
+	Template bool
+	// 订阅的事件, 为空则订阅 success/failed/unverified
+	Events []string
+	// 仅用于该名称的DNS配置, 为空则用于全部
+	DnsConf string
+}
+
+// Webhook 事件
+const (
+	// EventSuccess 更新成功
+	EventSuccess = "success"
+	// EventFailed 更新失败
+	EventFailed = "failed"
+	// EventUnverified 更新成功但未验证
+	EventUnverified = "unverified"
+	// EventIpChanged IP与上次获取到的不同
+	EventIpChanged = "ipChanged"
+	// EventIpFailed 获取IP失败
+	EventIpFailed = "ipFailed"
+	// EventDrift 检测到漂移
+	EventDrift = "drift"
+	// EventRecovered 通知过更新失败后恢复
+	EventRecovered = "recovered"
+)
+
+// defaultEvents 未填写订阅的事件时, 与之前相同并通知恢复
+var defaultEvents = []string{EventSuccess, EventFailed, EventUnverified, EventRecovered}
+
 // updateStatusType 更新状态
 type updateStatusType string
 
@@ -26,94 +81,252 @@ const (
 	UpdatedFailed = "失败"
 	// UpdatedSuccess 更新成功
 	UpdatedSuccess = "成功"
+	// UpdatedUnverified 更新成功, 但权威DNS服务器未在超时时间内返回新的记录
+	UpdatedUnverified = "未验证"
 )
 
-// 更新失败次数
-var updatedFailedTimes = 0
+// Code 不翻译的状态, 用于MQTT、结构化日志等: unchanged/failed/success/unverified
+func (status updateStatusType) Code() string {
+	switch status {
+	case UpdatedNothing:
+		return "unchanged"
+	case UpdatedFailed:
+		return "failed"
+	case UpdatedSuccess:
+		return "success"
+	case UpdatedUnverified:
+		return "unverified"
+	}
+	return ""
+}
 
 // hasJSONPrefix returns true if the string starts with a JSON open brace.
 func hasJSONPrefix(s string) bool {
 	return strings.HasPrefix(s, "{") || strings.HasPrefix(s, "[")
 }
 
-// ExecWebhook 添加或更新IPv4/IPv6记录, 返回是否有更新失败的
-func ExecWebhook(domains *Domains, conf *Config) (v4Status updateStatusType, v6Status updateStatusType) {
+// ExecWebhook 在后台发送第 index 个DNS配置的Webhook, 返回IPv4/IPv6的更新状态
+func ExecWebhook(index int, domains *Domains, conf *Config, dnsConf *DnsConfig) (v4Status updateStatusType, v6Status updateStatusType) {
 	v4Status = getDomainsStatus(domains.Ipv4Domains)
-	v6Status = getDomainsStatus(domains.Ipv6Domains)
-
-	if conf.WebhookURL != "" && (v4Status != UpdatedNothing || v6Status != UpdatedNothing) {
-		// 第3次失败才触发一次webhook
-		if v4Status == UpdatedFailed || v6Status == UpdatedFailed {
-			updatedFailedTimes++
-			if updatedFailedTimes != 3 {
-				util.Log("将不会触发Webhook, 仅在第 3 次失败时触发一次Webhook, 当前失败次数：%d", updatedFailedTimes)
-				return
-			}
-		} else {
-			updatedFailedTimes = 0
+	v6Status = getDomainsStatus(domains.ipv6AllDomains())
+
+	if len(conf.Webhooks) == 0 {
+		return
+	}
+	failed := domains.isFailed(v4Status, v6Status)
+	events := domains.getEvents(v4Status, v6Status)
+	for _, webhook := range conf.Webhooks {
+		if webhook.IsEmpty() {
+			continue
+		}
+		// 每个Webhook单独计算失败次数
+		webhookEvents := webhook.filterEvents(index, failed, events, time.Now())
+		if !webhook.subscribed(dnsConf.Name, webhookEvents) {
+			continue
 		}
+		webhook.SendAsync(NewWebhookData(domains, dnsConf.Name, webhookEvents, v4Status, v6Status))
+	}
+	return
+}
 
-		// 成功和失败都要触发webhook
-		method := "GET"
-		postPara := ""
-		contentType := "application/x-www-form-urlencoded"
-		if conf.WebhookRequestBody != "" {
-			method = "POST"
-			postPara = replacePara(domains, conf.WebhookRequestBody, v4Status, v6Status)
-			if json.Valid([]byte(postPara)) {
-				contentType = "application/json"
-			} else if hasJSONPrefix(postPara) {
-				// 如果 RequestBody 的 JSON 无效但前缀为 JSON，提示无效
-				util.Log("Webhook中的 RequestBody JSON 无效")
-			}
+// isFailed 本次是否失败。
+// 获取IP失败时只在第3次标记为失败, 之后的每次也要计为失败, 不能当作已恢复
+func (domains *Domains) isFailed(v4Status updateStatusType, v6Status updateStatusType) bool {
+	return v4Status == UpdatedFailed || v6Status == UpdatedFailed || domains.ipv4Failing || domains.ipv6Failing || domains.recordsFailed
+}
+
+// getEvents 获得本次发生的事件
+func (domains *Domains) getEvents(v4Status updateStatusType, v6Status updateStatusType) (events []string) {
+	if domains.isFailed(v4Status, v6Status) {
+		events = append(events, EventFailed)
+	}
+	if v4Status == UpdatedSuccess || v6Status == UpdatedSuccess {
+		events = append(events, EventSuccess)
+	}
+	if v4Status == UpdatedUnverified || v6Status == UpdatedUnverified {
+		events = append(events, EventUnverified)
+	}
+
+	if (domains.Ipv4OldAddr != "" && domains.Ipv4Addr != "" && domains.Ipv4OldAddr != domains.Ipv4Addr) ||
+		(domains.Ipv6OldAddr != "" && domains.Ipv6Addr != "" && domains.Ipv6OldAddr != domains.Ipv6Addr) {
+		events = append(events, EventIpChanged)
+	}
+	if domains.ipv4Failed || domains.ipv6Failed {
+		events = append(events, EventIpFailed)
+	}
+	if getDriftStr(domains.Ipv4Domains) != "" || getDriftStr(domains.Ipv6Domains) != "" {
+		events = append(events, EventDrift)
+	}
+	return
+}
+
+// subscribed 是否订阅了该DNS配置的任一事件
+func (webhook WebhookConfig) subscribed(dnsConfName string, events []string) bool {
+	if webhook.DnsConf != "" && webhook.DnsConf != dnsConfName {
+		return false
+	}
+	subscribed := webhook.subscribedEvents()
+	for _, event := range events {
+		if slices.Contains(subscribed, event) {
+			return true
 		}
-		requestURL := replacePara(domains, conf.WebhookURL, v4Status, v6Status)
-		u, err := url.Parse(requestURL)
+	}
+	return false
+}
+
+// subscribedEvents 订阅的事件
+func (webhook WebhookConfig) subscribedEvents() []string {
+	if len(webhook.Events) == 0 {
+		return defaultEvents
+	}
+	return webhook.Events
+}
+
+// IsEmpty 是否未填写
+func (webhook WebhookConfig) IsEmpty() bool {
+	return webhook.URL == "" && webhook.Token == "" && webhook.Target == ""
+}
+
+// Send 发送Webhook, 失败时按 Retries 重试
+func (webhook WebhookConfig) Send(data *WebhookData) {
+	if webhook.Type != "" {
+		webhook.notify(data)
+		return
+	}
+
+	// 成功和失败都要触发webhook
+	method := "GET"
+	postPara := ""
+	contentType := "application/x-www-form-urlencoded"
+	if webhook.RequestBody != "" {
+		method = "POST"
+		var err error
+		postPara, err = data.render(webhook.RequestBody, webhook.Template)
 		if err != nil {
-			util.Log("Webhook配置中的URL不正确")
+			util.Log("Webhook模板不正确! 异常信息：%s", err)
+			webhook.failedDelivery(data, err)
 			return
 		}
+		if json.Valid([]byte(postPara)) {
+			contentType = "application/json"
+		} else if hasJSONPrefix(postPara) {
+			// 如果 RequestBody 的 JSON 无效但前缀为 JSON，提示无效
+			util.Log("Webhook中的 RequestBody JSON 无效")
+		}
+	}
+	requestURL, err := data.render(webhook.URL, webhook.Template)
+	if err != nil {
+		util.Log("Webhook模板不正确! 异常信息：%s", err)
+		webhook.failedDelivery(data, err)
+		return
+	}
+	u, err := url.Parse(requestURL)
+	if err != nil {
+		util.Log("Webhook配置中的URL不正确")
+		webhook.failedDelivery(data, err)
+		return
+	}
+
+	q, _ := url.ParseQuery(u.RawQuery)
+	u.RawQuery = q.Encode()
 
-		q, _ := url.ParseQuery(u.RawQuery)
-		u.RawQuery = q.Encode()
+	headers := extractHeaders(webhook.Headers)
+	clt := util.CreateHTTPClient()
+	if timeout := webhook.timeout(); timeout > 0 {
+		clt.Timeout = timeout
+	}
 
+	delivery, err := webhook.deliver(data, func() (int, string, bool, error) {
 		req, err := http.NewRequest(method, u.String(), strings.NewReader(postPara))
 		if err != nil {
-			util.Log("Webhook调用失败! 异常信息：%s", err)
-			return
+			return 0, "", false, err
 		}
-
-		headers := extractHeaders(conf.WebhookHeaders)
 		for key, value := range headers {
 			req.Header.Add(key, value)
 		}
 		req.Header.Add("content-type", contentType)
+		webhook.sign(req.Header, postPara)
 
-		clt := util.CreateHTTPClient()
-		resp, err := clt.Do(req)
-		body, err := util.GetHTTPResponseOrg(resp, err)
-		if err == nil {
-			util.Log("Webhook调用成功! 返回数据：%s", string(body))
-		} else {
-			util.Log("Webhook调用失败! 异常信息：%s", err)
+		// URL中可能有Token, 不在日志中输出
+		resp, err := clt.Do(util.WithRedactedURL(req))
+		if err != nil {
+			return 0, "", true, util.RedactURLError(err)
 		}
+		body, err := util.GetHTTPResponseOrg(resp, nil)
+		return resp.StatusCode, string(body), retryableStatus(resp.StatusCode), err
+	})
+	if delivery.Success {
+		util.Log("Webhook调用成功! 返回数据：%s", delivery.Response)
+	} else {
+		util.Log("Webhook调用失败! 异常信息：%s", err)
 	}
-	return
+}
+
+// defaultNotifyTemplate 通知渠道未填写 RequestBody 时的消息模板
+const defaultNotifyTemplate = `{{with .Ipv4}}{{if .Domains}}IPv4 {{.Addr}} {{.Result}}: {{join .DomainNames ", "}}
+{{end}}{{end}}{{with .Ipv6}}{{if .Domains}}IPv6 {{.Addr}} {{.Result}}: {{join .DomainNames ", "}}
+{{end}}{{end}}`
+
+// notify 通过内置的通知渠道发送, RequestBody 为消息内容
+func (webhook WebhookConfig) notify(data *WebhookData) {
+	notifier, err := notify.New(notify.Config{
+		Type:    webhook.Type,
+		URL:     webhook.URL,
+		Token:   webhook.Token,
+		Target:  webhook.Target,
+		Secret:  webhook.Secret,
+		Timeout: webhook.timeout(),
+	})
+	if err != nil {
+		util.Log("通知发送失败! 渠道: %s, 异常信息：%s", webhook.Type, err)
+		webhook.failedDelivery(data, err)
+		return
+	}
+
+	text, err := data.render(webhook.RequestBody, webhook.Template)
+	if webhook.RequestBody == "" {
+		text, err = data.render(defaultNotifyTemplate, true)
+	}
+	if err != nil {
+		util.Log("Webhook模板不正确! 异常信息：%s", err)
+		webhook.failedDelivery(data, err)
+		return
+	}
+	title := "ddns-go"
+	if data.Name != "" {
+		title += " - " + data.Name
+	}
+
+	delivery, err := webhook.deliver(data, func() (int, string, bool, error) {
+		return 0, "", true, notifier.Send(title, strings.TrimSpace(text))
+	})
+	if !delivery.Success {
+		util.Log("通知发送失败! 渠道: %s, 异常信息：%s", webhook.Type, err)
+		return
+	}
+	util.Log("通知发送成功! 渠道: %s", webhook.Type)
 }
 
 // getDomainsStatus 获取域名状态
 func getDomainsStatus(domains []*Domain) updateStatusType {
 	successNum := 0
+	unverifiedNum := 0
 	for _, v46 := range domains {
 		switch v46.UpdateStatus {
 		case UpdatedFailed:
 			// 一个失败，全部失败
 			return UpdatedFailed
+		case UpdatedUnverified:
+			unverifiedNum++
 		case UpdatedSuccess:
 			successNum++
 		}
 	}
 
+	if unverifiedNum > 0 {
+		// 没有失败时, 一个未验证就是未验证
+		return UpdatedUnverified
+	}
 	if successNum > 0 {
 		// 迭代完成后一个成功，就成功
 		return UpdatedSuccess
@@ -129,7 +342,13 @@ func replacePara(domains *Domains, orgPara string, ipv4Result updateStatusType,
 		"#{ipv4Domains}", getDomainsStr(domains.Ipv4Domains),
 		"#{ipv6Addr}", domains.Ipv6Addr,
 		"#{ipv6Result}", util.LogStr(string(ipv6Result)), // i18n
-		"#{ipv6Domains}", getDomainsStr(domains.Ipv6Domains),
+		"#{ipv6Domains}", getDomainsStr(domains.ipv6AllDomains()),
+		"#{ipv4Source}", domains.Ipv4Source,
+		"#{ipv6Source}", domains.Ipv6Source,
+		"#{ipv4Drift}", getDriftStr(domains.Ipv4Domains),
+		"#{ipv6Drift}", getDriftStr(domains.Ipv6Domains),
+		"#{timestamp}", strconv.FormatInt(time.Now().Unix(), 10),
+		"#{version}", os.Getenv(util.VersionEnv),
 	).Replace(orgPara)
 }
 
@@ -146,6 +365,18 @@ func getDomainsStr(domains []*Domain) string {
 	return str
 }
 
+// getDriftStr 被修改的域名及其修改后的值, 如 www.example.com=1.1.1.1, 多个以逗号分割
+func getDriftStr(domains []*Domain) string {
+	var drifts []string
+	for _, v46 := range domains {
+		if v46.Drift != "" {
+			drifts = append(drifts, v46.String()+"="+v46.Drift)
+		}
+	}
+
+	return strings.Join(drifts, ",")
+}
+
 // extractHeaders converts s into a map of headers.
 //
 // See also: https://github.com/appleboy/gorush/blob/v1.17.0/notify/feedback.go#L15
@@ -0,0 +1,82 @@
+package config
+
+import (
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/jeessy2/ddns-go/v6/util"
+)
+
+// defaultFailedTimes 未填写时, 连续失败 3 次后通知, 与之前相同
+const defaultFailedTimes = 3
+
+// alertState 单个DNS配置对单个Webhook的失败通知状态
+type alertState struct {
+	// 连续失败次数
+	failures int
+	// 本次连续失败是否已通知
+	alerted   bool
+	lastAlert time.Time
+}
+
+// alertStates key 为 DNS配置序号|Webhook
+var alertStates = struct {
+	sync.Mutex
+	m map[string]*alertState
+}{m: map[string]*alertState{}}
+
+// alertKey 第 index 个DNS配置与该Webhook的状态的 key
+func (webhook WebhookConfig) alertKey(index int) string {
+	return strconv.Itoa(index) + "|" + webhook.Type + "|" + webhook.URL + "|" + webhook.Target
+}
+
+// filterEvents 根据失败通知状态过滤事件。
+// 连续失败 FailedTimes 次后通知一次, 之后每 RemindHours 小时提醒一次, 恢复后发送 recovered
+func (webhook WebhookConfig) filterEvents(index int, failed bool, events []string, now time.Time) []string {
+	alertStates.Lock()
+	defer alertStates.Unlock()
+
+	key := webhook.alertKey(index)
+	state := alertStates.m[key]
+	if state == nil {
+		state = &alertState{}
+		alertStates.m[key] = state
+	}
+
+	if !failed {
+		if state.alerted {
+			events = append(slices.Clone(events), EventRecovered)
+		}
+		delete(alertStates.m, key)
+		return events
+	}
+
+	state.failures++
+	failedTimes := webhook.FailedTimes
+	if failedTimes <= 0 {
+		failedTimes = defaultFailedTimes
+	}
+	remind := webhook.RemindHours > 0 && now.Sub(state.lastAlert) >= time.Duration(webhook.RemindHours)*time.Hour
+	if state.failures >= failedTimes && (!state.alerted || remind) {
+		state.alerted = true
+		state.lastAlert = now
+		return events
+	}
+
+	switch {
+	case !slices.Contains(webhook.subscribedEvents(), EventFailed):
+		// 未订阅失败时不提示
+	case state.failures < failedTimes:
+		util.Log("将不会触发Webhook, 连续失败 %d 次后触发, 当前失败次数：%d", failedTimes, state.failures)
+	case webhook.RemindHours > 0:
+		util.Log("将不会触发Webhook, 每 %d 小时提醒一次, 当前失败次数：%d", webhook.RemindHours, state.failures)
+	default:
+		util.Log("将不会触发Webhook, 已通知过本次失败, 当前失败次数：%d", state.failures)
+	}
+	// 未到通知时间, 不发送更新结果
+	return slices.DeleteFunc(slices.Clone(events), func(event string) bool {
+		return event == EventSuccess || event == EventFailed || event == EventUnverified
+	})
+}
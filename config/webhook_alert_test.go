@@ -0,0 +1,87 @@
+package config
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/jeessy2/ddns-go/v6/util"
+)
+
+func TestFilterEvents(t *testing.T) {
+	webhook := WebhookConfig{URL: "http://127.0.0.1/alert", RemindHours: 2}
+	other := WebhookConfig{URL: "http://127.0.0.1/other", FailedTimes: 1}
+	failed := []string{EventFailed, EventIpChanged}
+	now := time.Now()
+
+	steps := []struct {
+		name     string
+		webhook  WebhookConfig
+		index    int
+		failed   bool
+		after    time.Duration
+		expected []string
+	}{
+		{"First failure", webhook, 0, true, 0, []string{EventIpChanged}},
+		{"Other config", webhook, 1, true, 0, []string{EventIpChanged}},
+		{"Second failure", webhook, 0, true, 0, []string{EventIpChanged}},
+		{"Alert", webhook, 0, true, 0, failed},
+		{"Other target alerts at once", other, 0, true, 0, failed},
+		{"No reminder yet", webhook, 0, true, time.Hour, []string{EventIpChanged}},
+		{"Reminder", webhook, 0, true, 2 * time.Hour, failed},
+		{"Recovered", webhook, 0, false, 2 * time.Hour, []string{EventSuccess, EventRecovered}},
+		{"Not alerted", webhook, 1, false, 2 * time.Hour, []string{EventSuccess}},
+		{"Reset", webhook, 0, true, 2 * time.Hour, []string{EventIpChanged}},
+	}
+	for _, step := range steps {
+		events := failed
+		if !step.failed {
+			events = []string{EventSuccess}
+		}
+		result := step.webhook.filterEvents(step.index, step.failed, events, now.Add(step.after))
+		if !slices.Equal(result, step.expected) {
+			t.Errorf("%s: expected %v, got %v", step.name, step.expected, result)
+		}
+	}
+}
+
+// TestFilterEventsIpFailed 测试连续多次获取IP失败时的通知, 失败次数超过3次时不能当作已恢复
+func TestFilterEventsIpFailed(t *testing.T) {
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+	ok := startTestIPServer(t, "203.0.113.1")
+
+	conf := &DnsConfig{}
+	conf.Ipv4.Enable = true
+	conf.Ipv4.GetType = "url"
+	conf.Ipv4.Domains = []string{"www.example.com"}
+	webhook := WebhookConfig{URL: "http://127.0.0.1/ip-failed"}
+	cache := &util.IpCache{}
+
+	steps := []struct {
+		url       string
+		failed    bool
+		recovered bool
+	}{
+		{closed.URL, false, false},
+		{closed.URL, false, false},
+		// 连续失败3次后通知
+		{closed.URL, true, false},
+		{closed.URL, false, false},
+		{closed.URL, false, false},
+		{ok, false, true},
+		{ok, false, false},
+	}
+	for i, step := range steps {
+		conf.Ipv4.URL = step.url
+		domains := &Domains{Ipv4Cache: cache, Ipv6Cache: &util.IpCache{}}
+		domains.GetNewIp(conf)
+		v4Status := getDomainsStatus(domains.Ipv4Domains)
+		events := webhook.filterEvents(99, domains.isFailed(v4Status, UpdatedNothing), domains.getEvents(v4Status, UpdatedNothing), time.Now())
+		if slices.Contains(events, EventFailed) != step.failed || slices.Contains(events, EventRecovered) != step.recovered {
+			t.Errorf("第 %d 次: 期待失败 %v 恢复 %v, 得到 %v", i+1, step.failed, step.recovered, events)
+		}
+	}
+}
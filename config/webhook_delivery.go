@@ -0,0 +1,230 @@
+package config
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/jeessy2/ddns-go/v6/util"
+)
+
+const (
+	// maxWebhookRetries 最大重试次数
+	maxWebhookRetries = 10
+	// maxWebhookDelay 重试的最大间隔
+	maxWebhookDelay = 30 * time.Second
+	// maxWebhookDeliveries 保存的发送记录条数
+	maxWebhookDeliveries = 50
+	// maxWebhookResponse 记录的返回内容的最大长度
+	maxWebhookResponse = 200
+	// maxWebhookQueue 每个Webhook等待发送的最大数量
+	maxWebhookQueue = 20
+)
+
+// webhookRetryDelay 第一次重试的间隔, 之后每次翻倍
+var webhookRetryDelay = time.Second
+
+// WebhookDelivery 一次Webhook/通知的发送记录
+type WebhookDelivery struct {
+	Time time.Time
+	// 通知渠道, Webhook 为 webhook
+	Channel string
+	// 接收方的主机名, 不含路径以免泄露Token
+	Host string
+	// DNS配置的名称
+	Name   string
+	Events []string
+	// 尝试次数, 包括重试
+	Attempts int
+	Success  bool
+	// HTTP状态码, 通知渠道及未收到响应时为0
+	StatusCode int
+	// 最后一次尝试的耗时(毫秒)
+	Latency int64
+	// 返回内容或异常信息的前 200 个字符
+	Response string
+}
+
+// webhookDeliveries 最近的发送记录
+var webhookDeliveries struct {
+	sync.Mutex
+	list []WebhookDelivery
+}
+
+// GetWebhookDeliveries 最近的发送记录, 最新的在前
+func GetWebhookDeliveries() []WebhookDelivery {
+	webhookDeliveries.Lock()
+	defer webhookDeliveries.Unlock()
+
+	list := append([]WebhookDelivery{}, webhookDeliveries.list...)
+	slices.Reverse(list)
+	return list
+}
+
+func addWebhookDelivery(delivery WebhookDelivery) {
+	webhookDeliveries.Lock()
+	defer webhookDeliveries.Unlock()
+
+	webhookDeliveries.list = append(webhookDeliveries.list, delivery)
+	if len(webhookDeliveries.list) > maxWebhookDeliveries {
+		webhookDeliveries.list = webhookDeliveries.list[len(webhookDeliveries.list)-maxWebhookDeliveries:]
+	}
+}
+
+// webhookJob 等待发送的Webhook
+type webhookJob struct {
+	webhook WebhookConfig
+	data    *WebhookData
+}
+
+// webhookQueues 每个Webhook一个发送队列, 存在时其后台发送协程在运行
+var webhookQueues = struct {
+	sync.Mutex
+	m map[string][]webhookJob
+}{m: map[string][]webhookJob{}}
+
+// SendAsync 在后台发送, 重试不会阻塞DNS的更新。同一Webhook按顺序发送
+func (webhook WebhookConfig) SendAsync(data *WebhookData) {
+	key := webhook.channel() + "\n" + webhook.URL + "\n" + webhook.Target
+	webhookQueues.Lock()
+	defer webhookQueues.Unlock()
+
+	jobs, running := webhookQueues.m[key]
+	if len(jobs) >= maxWebhookQueue {
+		util.Log("等待发送的Webhook过多, 将丢弃本次发送")
+		webhook.failedDelivery(data, errors.New("too many pending deliveries"))
+		return
+	}
+	webhookQueues.m[key] = append(jobs, webhookJob{webhook, data})
+	if !running {
+		go runWebhookQueue(key)
+	}
+}
+
+// runWebhookQueue 按顺序发送队列中的Webhook, 发送完后退出
+func runWebhookQueue(key string) {
+	for {
+		webhookQueues.Lock()
+		jobs := webhookQueues.m[key]
+		if len(jobs) == 0 {
+			delete(webhookQueues.m, key)
+			webhookQueues.Unlock()
+			return
+		}
+		webhookQueues.m[key] = jobs[1:]
+		webhookQueues.Unlock()
+
+		jobs[0].webhook.Send(jobs[0].data)
+	}
+}
+
+// webhookAttempt 发送一次, 返回HTTP状态码、返回内容及失败时是否可以重试
+type webhookAttempt func() (statusCode int, response string, retryable bool, err error)
+
+// deliver 发送并在失败时按 Retries 重试, 间隔从 1 秒开始翻倍, 最长 30 秒。
+// 返回发送记录及最后一次的异常信息
+func (webhook WebhookConfig) deliver(data *WebhookData, attempt webhookAttempt) (WebhookDelivery, error) {
+	delivery := WebhookDelivery{
+		Time:    time.Now(),
+		Channel: webhook.channel(),
+		Host:    webhook.host(),
+		Name:    data.Name,
+		Events:  data.Events,
+	}
+	var err error
+	retries := min(max(webhook.Retries, 0), maxWebhookRetries)
+	delay := webhookRetryDelay
+	for {
+		start := time.Now()
+		statusCode, response, retryable, attemptErr := attempt()
+		err = attemptErr
+		delivery.Attempts++
+		delivery.StatusCode = statusCode
+		delivery.Latency = time.Since(start).Milliseconds()
+		delivery.Success = err == nil
+		if err != nil && response == "" {
+			response = err.Error()
+		}
+		delivery.Response = excerpt(response)
+
+		if err == nil || !retryable || delivery.Attempts > retries {
+			break
+		}
+		util.Log("发送失败, 将在 %s 后重试(%d/%d)! 异常信息：%s", delay, delivery.Attempts, retries, err)
+		time.Sleep(delay)
+		delay = min(delay*2, maxWebhookDelay)
+	}
+	addWebhookDelivery(delivery)
+	return delivery, err
+}
+
+// failedDelivery 发送前出错, 如模板不正确
+func (webhook WebhookConfig) failedDelivery(data *WebhookData, err error) {
+	addWebhookDelivery(WebhookDelivery{
+		Time:     time.Now(),
+		Channel:  webhook.channel(),
+		Host:     webhook.host(),
+		Name:     data.Name,
+		Events:   data.Events,
+		Response: excerpt(err.Error()),
+	})
+}
+
+// channel 通知渠道
+func (webhook WebhookConfig) channel() string {
+	if webhook.Type == "" {
+		return "webhook"
+	}
+	return webhook.Type
+}
+
+// host URL中的主机名
+func (webhook WebhookConfig) host() string {
+	if u, err := url.Parse(webhook.URL); err == nil {
+		return u.Host
+	}
+	return ""
+}
+
+// timeout 每次发送的超时时间, 为0则使用默认的超时时间
+func (webhook WebhookConfig) timeout() time.Duration {
+	return time.Duration(max(webhook.Timeout, 0)) * time.Second
+}
+
+// sign 使用 Secret 签名, 签名内容为 时间戳.RequestBody
+func (webhook WebhookConfig) sign(header http.Header, body string) {
+	if webhook.Secret == "" {
+		return
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	header.Set("X-DDNS-Timestamp", timestamp)
+	header.Set("X-DDNS-Signature", "sha256="+webhookSignature(webhook.Secret, timestamp, body))
+}
+
+// webhookSignature HMAC-SHA256(secret, 时间戳.RequestBody) 的十六进制
+func webhookSignature(secret string, timestamp string, body string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "." + body))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// retryableStatus 服务器错误及限流时可以重试
+func retryableStatus(statusCode int) bool {
+	return statusCode >= 500 || statusCode == http.StatusTooManyRequests
+}
+
+// excerpt 截取前 200 个字符
+func excerpt(s string) string {
+	if utf8.RuneCountInString(s) <= maxWebhookResponse {
+		return s
+	}
+	return string([]rune(s)[:maxWebhookResponse]) + "..."
+}
@@ -0,0 +1,130 @@
+package config
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWebhookRetry(t *testing.T) {
+	webhookRetryDelay = time.Millisecond
+	t.Cleanup(func() { webhookRetryDelay = time.Second })
+
+	var requests []*http.Request
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests, bodies = append(requests, r), append(bodies, string(body))
+		if len(requests) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		io.WriteString(w, "ok")
+	}))
+	t.Cleanup(server.Close)
+
+	data := NewWebhookData(&Domains{Ipv4Addr: "203.0.113.1"}, "home", []string{EventSuccess}, UpdatedSuccess, UpdatedNothing)
+	webhook := WebhookConfig{URL: server.URL, RequestBody: `{"ip":"#{ipv4Addr}"}`, Secret: "s3cret", Retries: 3}
+	webhook.Send(data)
+
+	if len(requests) != 3 {
+		t.Fatalf("Expected 3 attempts, got %d", len(requests))
+	}
+	last := requests[2]
+	timestamp := last.Header.Get("X-DDNS-Timestamp")
+	expected := "sha256=" + webhookSignature("s3cret", timestamp, `{"ip":"203.0.113.1"}`)
+	if timestamp == "" || last.Header.Get("X-DDNS-Signature") != expected || bodies[2] != `{"ip":"203.0.113.1"}` {
+		t.Errorf("Unexpected signature %s, expected %s", last.Header.Get("X-DDNS-Signature"), expected)
+	}
+
+	delivery := GetWebhookDeliveries()[0]
+	if !delivery.Success || delivery.Attempts != 3 || delivery.StatusCode != 200 || delivery.Response != "ok" ||
+		delivery.Name != "home" || delivery.Channel != "webhook" || !strings.HasPrefix(delivery.Host, "127.0.0.1:") {
+		t.Errorf("Unexpected delivery %+v", delivery)
+	}
+}
+
+func TestWebhookNoRetry(t *testing.T) {
+	webhookRetryDelay = time.Millisecond
+	t.Cleanup(func() { webhookRetryDelay = time.Second })
+
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if r.Header.Get("X-DDNS-Signature") != "" {
+			t.Error("Expected no signature without secret")
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		io.WriteString(w, strings.Repeat("x", 300))
+	}))
+	t.Cleanup(server.Close)
+
+	data := NewWebhookData(&Domains{}, "", nil, UpdatedFailed, UpdatedNothing)
+	WebhookConfig{URL: server.URL, Retries: 3}.Send(data)
+	if attempts != 1 {
+		t.Errorf("Expected 1 attempt for 4xx, got %d", attempts)
+	}
+	delivery := GetWebhookDeliveries()[0]
+	if delivery.Success || delivery.StatusCode != http.StatusBadRequest || len(delivery.Response) != 203 {
+		t.Errorf("Unexpected delivery %+v", delivery)
+	}
+}
+
+func TestWebhookTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(1500 * time.Millisecond)
+	}))
+	t.Cleanup(server.Close)
+
+	WebhookConfig{URL: server.URL, Timeout: 1}.Send(NewWebhookData(&Domains{}, "", nil, UpdatedSuccess, UpdatedNothing))
+	if delivery := GetWebhookDeliveries()[0]; delivery.Success || delivery.StatusCode != 0 || delivery.Latency >= 1500 {
+		t.Errorf("Unexpected delivery %+v", delivery)
+	}
+}
+
+func TestWebhookSendAsync(t *testing.T) {
+	webhookRetryDelay = time.Millisecond
+	t.Cleanup(func() { webhookRetryDelay = time.Second })
+
+	release := make(chan struct{})
+	received := make(chan string, 3)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		received <- r.URL.Query().Get("ip")
+	}))
+	t.Cleanup(server.Close)
+
+	// 服务器未返回时不阻塞, 且按顺序发送
+	webhook := WebhookConfig{URL: server.URL + "?ip=#{ipv4Addr}"}
+	start := time.Now()
+	for _, addr := range []string{"203.0.113.1", "203.0.113.2", "203.0.113.3"} {
+		webhook.SendAsync(NewWebhookData(&Domains{Ipv4Addr: addr}, "", nil, UpdatedSuccess, UpdatedNothing))
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Expected SendAsync not to block, took %s", elapsed)
+	}
+	close(release)
+
+	for _, expected := range []string{"203.0.113.1", "203.0.113.2", "203.0.113.3"} {
+		select {
+		case addr := <-received:
+			if addr != expected {
+				t.Errorf("Expected %s, got %s", expected, addr)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("Expected the webhook to be sent")
+		}
+	}
+}
+
+func TestWebhookDeliveriesBounded(t *testing.T) {
+	for i := 0; i < maxWebhookDeliveries+5; i++ {
+		addWebhookDelivery(WebhookDelivery{Attempts: i})
+	}
+	deliveries := GetWebhookDeliveries()
+	if len(deliveries) != maxWebhookDeliveries || deliveries[0].Attempts != maxWebhookDeliveries+4 {
+		t.Errorf("Expected %d deliveries with the newest first, got %d", maxWebhookDeliveries, len(deliveries))
+	}
+}
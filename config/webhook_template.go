@@ -0,0 +1,131 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/jeessy2/ddns-go/v6/util"
+)
+
+// WebhookData Webhook模板的数据
+type WebhookData struct {
+	// DNS配置的名称
+	Name string
+	// 主机名
+	Hostname string
+	// ddns-go的版本
+	Version   string
+	Timestamp time.Time
+	// 本次发生的事件, 如 success ipChanged
+	Events []string
+	Ipv4   WebhookAddr
+	Ipv6   WebhookAddr
+
+	// 兼容 #{} 变量
+	domains  *Domains
+	v4Status updateStatusType
+	v6Status updateStatusType
+}
+
+// WebhookAddr IPv4/IPv6的数据
+type WebhookAddr struct {
+	Addr    string
+	OldAddr string
+	// 获取到地址的来源
+	Source string
+	// 更新结果: 未改变/失败/成功/未验证
+	Result  string
+	Domains []WebhookDomain
+}
+
+// WebhookDomain 单个域名的数据
+type WebhookDomain struct {
+	Domain string
+	// 更新结果, 未更新时为空
+	Status string
+	// 检测到漂移时DNS中的实际记录值
+	Drift string
+}
+
+// DomainNames 所有域名
+func (addr WebhookAddr) DomainNames() []string {
+	names := make([]string, 0, len(addr.Domains))
+	for _, domain := range addr.Domains {
+		names = append(names, domain.Domain)
+	}
+	return names
+}
+
+// NewWebhookData 生成Webhook模板的数据
+func NewWebhookData(domains *Domains, dnsConfName string, events []string, v4Status updateStatusType, v6Status updateStatusType) *WebhookData {
+	hostname, _ := os.Hostname()
+	return &WebhookData{
+		Name:      dnsConfName,
+		Hostname:  hostname,
+		Version:   os.Getenv(util.VersionEnv),
+		Timestamp: time.Now(),
+		Events:    events,
+		Ipv4:      newWebhookAddr(domains.Ipv4Addr, domains.Ipv4OldAddr, domains.Ipv4Source, v4Status, domains.Ipv4Domains),
+		Ipv6:      newWebhookAddr(domains.Ipv6Addr, domains.Ipv6OldAddr, domains.Ipv6Source, v6Status, domains.ipv6AllDomains()),
+		domains:   domains,
+		v4Status:  v4Status,
+		v6Status:  v6Status,
+	}
+}
+
+func newWebhookAddr(addr, oldAddr, source string, status updateStatusType, domains []*Domain) WebhookAddr {
+	result := WebhookAddr{
+		Addr:    addr,
+		OldAddr: oldAddr,
+		Source:  source,
+		Result:  util.LogStr(string(status)), // i18n
+	}
+	for _, domain := range domains {
+		wd := WebhookDomain{Domain: domain.String(), Drift: domain.Drift}
+		if domain.UpdateStatus != "" {
+			wd.Status = util.LogStr(string(domain.UpdateStatus))
+		}
+		result.Domains = append(result.Domains, wd)
+	}
+	return result
+}
+
+// webhookFuncs 模板中可使用的函数
+var webhookFuncs = template.FuncMap{
+	// json 转为JSON, 字符串会被转义并加上引号
+	"json": func(v any) (string, error) {
+		var sb strings.Builder
+		encoder := json.NewEncoder(&sb)
+		encoder.SetEscapeHTML(false)
+		err := encoder.Encode(v)
+		return strings.TrimSuffix(sb.String(), "\n"), err
+	},
+	// join 用分隔符连接
+	"join": func(elems []string, sep string) string {
+		return strings.Join(elems, sep)
+	},
+	// formatTime 按 Go 的格式格式化时间, 如 2006-01-02 15:04:05
+	"formatTime": func(t time.Time, layout string) string {
+		return t.Format(layout)
+	},
+}
+
+// render 渲染URL/RequestBody。开启模板时使用 text/template, 否则替换 #{} 变量
+func (data *WebhookData) render(text string, useTemplate bool) (string, error) {
+	if !useTemplate {
+		return replacePara(data.domains, text, data.v4Status, data.v6Status), nil
+	}
+
+	tmpl, err := template.New("webhook").Funcs(webhookFuncs).Parse(text)
+	if err != nil {
+		return "", err
+	}
+	var sb strings.Builder
+	if err = tmpl.Execute(&sb, data); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
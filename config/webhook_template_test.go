@@ -0,0 +1,63 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/jeessy2/ddns-go/v6/util"
+)
+
+func TestWebhookRender(t *testing.T) {
+	domains := &Domains{
+		Ipv4Addr:    "203.0.113.2",
+		Ipv4OldAddr: "203.0.113.1",
+		Ipv4Domains: []*Domain{
+			{DomainName: "example.com", SubDomain: "www", UpdateStatus: UpdatedSuccess},
+			{DomainName: "example.com", SubDomain: "a\"b"},
+		},
+	}
+	data := NewWebhookData(domains, "home", []string{EventSuccess, EventIpChanged}, UpdatedSuccess, UpdatedNothing)
+
+	tests := []struct {
+		name     string
+		text     string
+		template bool
+		expected string
+	}{
+		{"Placeholder", "#{ipv4Addr} #{ipv4Domains}", false, "203.0.113.2 www.example.com,a\"b.example.com"},
+		{"Template", "{{.Name}} {{.Ipv4.OldAddr}} -> {{.Ipv4.Addr}} {{join .Events \",\"}}", true,
+			"home 203.0.113.1 -> 203.0.113.2 success,ipChanged"},
+		{"Loop", "{{range .Ipv4.Domains}}{{.Domain}}={{.Status}};{{end}}", true, "www.example.com=" + util.LogStr(UpdatedSuccess) + ";a\"b.example.com=;"},
+		{"JSON", "{\"domains\":{{json .Ipv4.DomainNames}}}", true, "{\"domains\":[\"www.example.com\",\"a\\\"b.example.com\"]}"},
+		{"Conditional", "{{if .Ipv6.Addr}}v6{{else}}no v6{{end}}", true, "no v6"},
+		{"Time", "{{formatTime .Timestamp \"2006\"}}", true, data.Timestamp.Format("2006")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := data.render(tt.text, tt.template)
+			if err != nil {
+				t.Fatalf("Expected nil error, got %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, result)
+			}
+		})
+	}
+
+	if _, err := data.render("{{.Unknown}}", true); err == nil {
+		t.Error("Expected error, got nil")
+	}
+}
+
+func TestWebhookRenderReadmeExample(t *testing.T) {
+	domains := &Domains{
+		Ipv4Addr:    "203.0.113.2",
+		Ipv4Domains: []*Domain{{DomainName: "example.com"}, {DomainName: "example.org"}},
+	}
+	data := NewWebhookData(domains, "home", nil, UpdatedNothing, UpdatedNothing)
+	result, err := data.render(`{"text": {{json (printf "%s: %s -> %s" .Name .Ipv4.OldAddr .Ipv4.Addr)}}, "domains": [{{range $i, $d := .Ipv4.Domains}}{{if $i}},{{end}}{"name": {{json $d.Domain}}, "status": {{json $d.Status}}}{{end}}]}`, true)
+	expected := `{"text": "home:  -> 203.0.113.2", "domains": [{"name": "example.com", "status": ""},{"name": "example.org", "status": ""}]}`
+	if err != nil || result != expected {
+		t.Errorf("Expected %s, got %s (%v)", expected, result, err)
+	}
+}
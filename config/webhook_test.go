@@ -20,3 +20,46 @@ b: bar`
 		t.Errorf("Expected %v, got %v", expected, parsedHeaders)
 	}
 }
+
+func TestGetEvents(t *testing.T) {
+	domains := &Domains{
+		Ipv4Addr:    "203.0.113.2",
+		Ipv4OldAddr: "203.0.113.1",
+		Ipv6Domains: []*Domain{{DomainName: "example.com", Drift: "2001:db8::9"}},
+		ipv6Failed:  true,
+	}
+	events := domains.getEvents(UpdatedSuccess, UpdatedNothing)
+	expected := []string{EventSuccess, EventIpChanged, EventIpFailed, EventDrift}
+	if !reflect.DeepEqual(events, expected) {
+		t.Errorf("Expected %v, got %v", expected, events)
+	}
+
+	if events := (&Domains{}).getEvents(UpdatedNothing, UpdatedNothing); len(events) != 0 {
+		t.Errorf("Expected no events, got %v", events)
+	}
+}
+
+func TestWebhookSubscribed(t *testing.T) {
+	tests := []struct {
+		name     string
+		webhook  WebhookConfig
+		dnsConf  string
+		events   []string
+		expected bool
+	}{
+		{"Default", WebhookConfig{}, "home", []string{EventSuccess}, true},
+		{"Default ignores drift", WebhookConfig{}, "home", []string{EventDrift}, false},
+		{"Subscribed", WebhookConfig{Events: []string{EventFailed, EventDrift}}, "home", []string{EventIpChanged, EventDrift}, true},
+		{"Not subscribed", WebhookConfig{Events: []string{EventFailed}}, "home", []string{EventSuccess}, false},
+		{"Scope", WebhookConfig{DnsConf: "home"}, "home", []string{EventSuccess}, true},
+		{"Other scope", WebhookConfig{DnsConf: "office"}, "home", []string{EventSuccess}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := tt.webhook.subscribed(tt.dnsConf, tt.events); result != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, result)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package dns
 
 import (
 	"bytes"
+	"log/slog"
 	"net/http"
 	"net/url"
 
@@ -73,39 +74,51 @@ func (ali *Alidns) addUpdateDomainRecords(recordType string) {
 	}
 
 	for _, domain := range domains {
-		var records AlidnsSubDomainRecords
-		// 获取当前域名信息
-		params := domain.GetCustomParams()
-		params.Set("Action", "DescribeSubDomainRecords")
-		params.Set("DomainName", domain.DomainName)
-		params.Set("SubDomain", domain.GetFullDomain())
-		params.Set("Type", recordType)
-		err := ali.request(params, &records)
-
-		if err != nil {
-			util.Log("查询域名信息发生异常! %s", err)
-			domain.UpdateStatus = config.UpdatedFailed
+		if !ali.addUpdateRecord(domain, recordType, ipAddr) {
 			return
 		}
+	}
+}
+
+// AddUpdateRecord 添加或更新 A/AAAA 以外的记录
+func (ali *Alidns) AddUpdateRecord(record *config.Record) {
+	ali.addUpdateRecord(record.Domain, record.Type, record.Value)
+}
+
+// addUpdateRecord 添加或更新单条记录, 查询域名信息失败时返回 false
+func (ali *Alidns) addUpdateRecord(domain *config.Domain, recordType string, value string) bool {
+	var records AlidnsSubDomainRecords
+	// 获取当前域名信息
+	params := domain.GetCustomParams()
+	params.Set("Action", "DescribeSubDomainRecords")
+	params.Set("DomainName", domain.DomainName)
+	params.Set("SubDomain", domain.GetFullDomain())
+	params.Set("Type", recordType)
+	err := ali.request(params, &records)
 
-		if records.TotalCount > 0 {
-			// 默认第一个
-			recordSelected := records.DomainRecords.Record[0]
-			if params.Has("RecordId") {
-				for i := 0; i < len(records.DomainRecords.Record); i++ {
-					if records.DomainRecords.Record[i].RecordID == params.Get("RecordId") {
-						recordSelected = records.DomainRecords.Record[i]
-					}
+	if err != nil {
+		util.LogAttrs(slog.LevelError, domain.LogAttrs("query"), "查询域名信息发生异常! %s", err)
+		domain.UpdateStatus = config.UpdatedFailed
+		return false
+	}
+
+	if records.TotalCount > 0 {
+		// 默认第一个
+		recordSelected := records.DomainRecords.Record[0]
+		if params.Has("RecordId") {
+			for i := 0; i < len(records.DomainRecords.Record); i++ {
+				if records.DomainRecords.Record[i].RecordID == params.Get("RecordId") {
+					recordSelected = records.DomainRecords.Record[i]
 				}
 			}
-			// 存在，更新
-			ali.modify(recordSelected, domain, recordType, ipAddr)
-		} else {
-			// 不存在，创建
-			ali.create(domain, recordType, ipAddr)
 		}
-
+		// 存在，更新
+		ali.modify(recordSelected, domain, recordType, value)
+	} else {
+		// 不存在，创建
+		ali.create(domain, recordType, value)
 	}
+	return true
 }
 
 // 创建
@@ -122,16 +135,16 @@ func (ali *Alidns) create(domain *config.Domain, recordType string, ipAddr strin
 	err := ali.request(params, &result)
 
 	if err != nil {
-		util.Log("新增域名解析 %s 失败! 异常信息: %s", domain, err)
+		util.LogAttrs(slog.LevelError, domain.LogAttrs("create"), "新增域名解析 %s 失败! 异常信息: %s", domain, err)
 		domain.UpdateStatus = config.UpdatedFailed
 		return
 	}
 
 	if result.RecordID != "" {
-		util.Log("新增域名解析 %s 成功! IP: %s", domain, ipAddr)
+		util.LogAttrs(slog.LevelInfo, domain.LogAttrs("create"), "新增域名解析 %s 成功! IP: %s", domain, ipAddr)
 		domain.UpdateStatus = config.UpdatedSuccess
 	} else {
-		util.Log("新增域名解析 %s 失败! 异常信息: %s", domain, "返回RecordId为空")
+		util.LogAttrs(slog.LevelError, domain.LogAttrs("create"), "新增域名解析 %s 失败! 异常信息: %s", domain, "返回RecordId为空")
 		domain.UpdateStatus = config.UpdatedFailed
 	}
 }
@@ -141,7 +154,7 @@ func (ali *Alidns) modify(recordSelected AlidnsRecord, domain *config.Domain, re
 
 	// 相同不修改
 	if recordSelected.Value == ipAddr {
-		util.Log("你的IP %s 没有变化, 域名 %s", ipAddr, domain)
+		util.LogAttrs(slog.LevelInfo, domain.LogAttrs("unchanged"), "你的IP %s 没有变化, 域名 %s", ipAddr, domain)
 		return
 	}
 
@@ -157,16 +170,16 @@ func (ali *Alidns) modify(recordSelected AlidnsRecord, domain *config.Domain, re
 	err := ali.request(params, &result)
 
 	if err != nil {
-		util.Log("更新域名解析 %s 失败! 异常信息: %s", domain, err)
+		util.LogAttrs(slog.LevelError, domain.LogAttrs("update"), "更新域名解析 %s 失败! 异常信息: %s", domain, err)
 		domain.UpdateStatus = config.UpdatedFailed
 		return
 	}
 
 	if result.RecordID != "" {
-		util.Log("更新域名解析 %s 成功! IP: %s", domain, ipAddr)
+		util.LogAttrs(slog.LevelInfo, domain.LogAttrs("update"), "更新域名解析 %s 成功! IP: %s", domain, ipAddr)
 		domain.UpdateStatus = config.UpdatedSuccess
 	} else {
-		util.Log("更新域名解析 %s 失败! 异常信息: %s", domain, "返回RecordId为空")
+		util.LogAttrs(slog.LevelError, domain.LogAttrs("update"), "更新域名解析 %s 失败! 异常信息: %s", domain, "返回RecordId为空")
 		domain.UpdateStatus = config.UpdatedFailed
 	}
 }
@@ -3,6 +3,7 @@ package dns
 import (
 	"bytes"
 	"encoding/json"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -96,12 +97,12 @@ func (ali *Aliesa) addUpdateDomainRecords(recordType string) {
 		// 获取站点
 		siteSelected, err := ali.getSite(domain)
 		if err != nil {
-			util.Log("查询域名信息发生异常! %s", err)
+			util.LogAttrs(slog.LevelError, domain.LogAttrs("query"), "查询域名信息发生异常! %s", err)
 			domain.SetUpdateStatus(config.UpdatedFailed)
 			return
 		}
 		if siteSelected.SiteId == 0 {
-			util.Log("在DNS服务商中未找到根域名: %s", domain.Primary.DomainName)
+			util.LogAttrs(slog.LevelError, domain.LogAttrs("query"), "在DNS服务商中未找到根域名: %s", domain.Primary.DomainName)
 			domain.SetUpdateStatus(config.UpdatedFailed)
 			return
 		}
@@ -109,7 +110,7 @@ func (ali *Aliesa) addUpdateDomainRecords(recordType string) {
 		// 处理源地址池
 		poolId, origins, err := ali.getOriginPool(siteSelected, domain)
 		if err != nil {
-			util.Log("查询域名信息发生异常! %s", err)
+			util.LogAttrs(slog.LevelError, domain.LogAttrs("query"), "查询域名信息发生异常! %s", err)
 			domain.SetUpdateStatus(config.UpdatedFailed)
 			return
 		}
@@ -122,7 +123,7 @@ func (ali *Aliesa) addUpdateDomainRecords(recordType string) {
 		// 获取记录
 		recordSelected, err := ali.getRecord(siteSelected, domain, "A/AAAA")
 		if err != nil {
-			util.Log("查询域名信息发生异常! %s", err)
+			util.LogAttrs(slog.LevelError, domain.LogAttrs("query"), "查询域名信息发生异常! %s", err)
 			domain.SetUpdateStatus(config.UpdatedFailed)
 			return
 		}
@@ -163,16 +164,16 @@ func (ali *Aliesa) create(site AliesaSite, domainTuple *config.DomainTuple, reco
 	err := ali.request(http.MethodPost, params, &result)
 
 	if err != nil {
-		util.Log("新增域名解析 %s 失败! 异常信息: %s", domain, err)
+		util.LogAttrs(slog.LevelError, domain.LogAttrs("create"), "新增域名解析 %s 失败! 异常信息: %s", domain, err)
 		domainTuple.SetUpdateStatus(config.UpdatedFailed)
 		return
 	}
 
 	if result.RecordID != 0 {
-		util.Log("新增域名解析 %s 成功! IP: %s", domain, ipAddr)
+		util.LogAttrs(slog.LevelInfo, domain.LogAttrs("create"), "新增域名解析 %s 成功! IP: %s", domain, ipAddr)
 		domainTuple.SetUpdateStatus(config.UpdatedSuccess)
 	} else {
-		util.Log("新增域名解析 %s 失败! 异常信息: %s", domain, "返回RecordId为空")
+		util.LogAttrs(slog.LevelError, domain.LogAttrs("create"), "新增域名解析 %s 失败! 异常信息: %s", domain, "返回RecordId为空")
 		domainTuple.SetUpdateStatus(config.UpdatedFailed)
 	}
 }
@@ -184,7 +185,7 @@ func (ali *Aliesa) modify(record AliesaRecord, domainTuple *config.DomainTuple,
 	ipAddr := domainTuple.GetIpAddrPool(",")
 	// 相同不修改
 	if record.Data.Value == ipAddr {
-		util.Log("你的IP %s 没有变化, 域名 %s", ipAddr, domain)
+		util.LogAttrs(slog.LevelInfo, domain.LogAttrs("unchanged"), "你的IP %s 没有变化, 域名 %s", ipAddr, domain)
 		return
 	}
 
@@ -200,13 +201,13 @@ func (ali *Aliesa) modify(record AliesaRecord, domainTuple *config.DomainTuple,
 	err := ali.request(http.MethodPost, params, &result)
 
 	if err != nil {
-		util.Log("更新域名解析 %s 失败! 异常信息: %s", domain, err)
+		util.LogAttrs(slog.LevelError, domain.LogAttrs("update"), "更新域名解析 %s 失败! 异常信息: %s", domain, err)
 		domainTuple.SetUpdateStatus(config.UpdatedFailed)
 		return
 	}
 
 	// 不检查 result.RecordID ，更新成功也会返回 0
-	util.Log("更新域名解析 %s 成功! IP: %s", domain, ipAddr)
+	util.LogAttrs(slog.LevelInfo, domain.LogAttrs("update"), "更新域名解析 %s 成功! IP: %s", domain, ipAddr)
 	domainTuple.SetUpdateStatus(config.UpdatedSuccess)
 }
 
@@ -336,12 +337,12 @@ func (ali *Aliesa) updateOriginPool(site AliesaSite, domainTuple *config.DomainT
 	ipAddr := domainTuple.GetIpAddrPool(",")
 	if count > 0 {
 		// 有新增的源地址
-		util.Log("更新域名解析 %s 失败! 异常信息: %s", domain, "不支持新增源地址")
+		util.LogAttrs(slog.LevelError, domain.LogAttrs("update"), "更新域名解析 %s 失败! 异常信息: %s", domain, "不支持新增源地址")
 		domainTuple.SetUpdateStatus(config.UpdatedFailed)
 		return
 	}
 	if !needUpdate {
-		util.Log("你的IP %s 没有变化, 域名 %s", ipAddr, domain)
+		util.LogAttrs(slog.LevelInfo, domain.LogAttrs("unchanged"), "你的IP %s 没有变化, 域名 %s", ipAddr, domain)
 		return
 	}
 
@@ -356,16 +357,16 @@ func (ali *Aliesa) updateOriginPool(site AliesaSite, domainTuple *config.DomainT
 	err := ali.request(http.MethodPost, params, &result)
 
 	if err != nil {
-		util.Log("更新域名解析 %s 失败! 异常信息: %s", domain, err)
+		util.LogAttrs(slog.LevelError, domain.LogAttrs("update"), "更新域名解析 %s 失败! 异常信息: %s", domain, err)
 		domainTuple.SetUpdateStatus(config.UpdatedFailed)
 		return
 	}
 
 	if result.OriginPoolId != 0 {
-		util.Log("更新域名解析 %s 成功! IP: %s", domain, ipAddr)
+		util.LogAttrs(slog.LevelInfo, domain.LogAttrs("update"), "更新域名解析 %s 成功! IP: %s", domain, ipAddr)
 		domainTuple.SetUpdateStatus(config.UpdatedSuccess)
 	} else {
-		util.Log("更新域名解析 %s 失败! 异常信息: %s", domain, "返回 OriginPool Id为空")
+		util.LogAttrs(slog.LevelError, domain.LogAttrs("update"), "更新域名解析 %s 失败! 异常信息: %s", domain, "返回 OriginPool Id为空")
 		domainTuple.SetUpdateStatus(config.UpdatedFailed)
 	}
 }
@@ -3,8 +3,10 @@ package dns
 import (
 	"bytes"
 	"encoding/json"
+	"log/slog"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/jeessy2/ddns-go/v6/config"
 	"github.com/jeessy2/ddns-go/v6/util"
@@ -101,35 +103,45 @@ func (baidu *BaiduCloud) addUpdateDomainRecords(recordType string) {
 	}
 
 	for _, domain := range domains {
-		var records BaiduRecordsResp
-
-		requestBody := BaiduListRequest{
-			Domain:   domain.DomainName,
-			PageNum:  1,
-			PageSize: 1000,
-		}
-
-		err := baidu.request("POST", baiduEndpoint+"/v1/domain/resolve/list", requestBody, &records)
-		if err != nil {
-			util.Log("查询域名信息发生异常! %s", err)
-			domain.UpdateStatus = config.UpdatedFailed
+		if !baidu.addUpdateRecord(domain, recordType, ipAddr) {
 			return
 		}
+	}
+}
 
-		find := false
-		for _, record := range records.Result {
-			if record.Domain == domain.GetSubDomain() {
-				//存在就去更新
-				baidu.modify(record, domain, recordType, ipAddr)
-				find = true
-				break
-			}
-		}
-		if !find {
-			//没找到，去创建
-			baidu.create(domain, recordType, ipAddr)
+// AddUpdateRecord 添加或更新 A/AAAA 以外的记录
+func (baidu *BaiduCloud) AddUpdateRecord(record *config.Record) {
+	baidu.addUpdateRecord(record.Domain, record.Type, record.Value)
+}
+
+// addUpdateRecord 添加或更新单条记录, 查询域名信息失败时返回 false
+func (baidu *BaiduCloud) addUpdateRecord(domain *config.Domain, recordType string, value string) bool {
+	var records BaiduRecordsResp
+
+	requestBody := BaiduListRequest{
+		Domain:   domain.DomainName,
+		PageNum:  1,
+		PageSize: 1000,
+	}
+
+	err := baidu.request("POST", baiduEndpoint+"/v1/domain/resolve/list", requestBody, &records)
+	if err != nil {
+		util.LogAttrs(slog.LevelError, domain.LogAttrs("query"), "查询域名信息发生异常! %s", err)
+		domain.UpdateStatus = config.UpdatedFailed
+		return false
+	}
+
+	for _, record := range records.Result {
+		// 列表包含根域名下所有类型的记录
+		if record.Domain == domain.GetSubDomain() && strings.EqualFold(record.Rdtype, recordType) {
+			//存在就去更新
+			baidu.modify(record, domain, recordType, value)
+			return true
 		}
 	}
+	//没找到，去创建
+	baidu.create(domain, recordType, value)
+	return true
 }
 
 // create 创建新的解析
@@ -145,10 +157,10 @@ func (baidu *BaiduCloud) create(domain *config.Domain, recordType string, ipAddr
 
 	err := baidu.request("POST", baiduEndpoint+"/v1/domain/resolve/add", baiduCreateRequest, &result)
 	if err == nil {
-		util.Log("新增域名解析 %s 成功! IP: %s", domain, ipAddr)
+		util.LogAttrs(slog.LevelInfo, domain.LogAttrs("create"), "新增域名解析 %s 成功! IP: %s", domain, ipAddr)
 		domain.UpdateStatus = config.UpdatedSuccess
 	} else {
-		util.Log("新增域名解析 %s 失败! 异常信息: %s", domain, err)
+		util.LogAttrs(slog.LevelError, domain.LogAttrs("create"), "新增域名解析 %s 失败! 异常信息: %s", domain, err)
 		domain.UpdateStatus = config.UpdatedFailed
 	}
 }
@@ -157,7 +169,7 @@ func (baidu *BaiduCloud) create(domain *config.Domain, recordType string, ipAddr
 func (baidu *BaiduCloud) modify(record BaiduRecord, domain *config.Domain, rdType string, ipAddr string) {
 	//没有变化直接跳过
 	if record.Rdata == ipAddr {
-		util.Log("你的IP %s 没有变化, 域名 %s", ipAddr, domain)
+		util.LogAttrs(slog.LevelInfo, domain.LogAttrs("unchanged"), "你的IP %s 没有变化, 域名 %s", ipAddr, domain)
 		return
 	}
 	var baiduModifyRequest = BaiduModifyRequest{
@@ -173,10 +185,10 @@ func (baidu *BaiduCloud) modify(record BaiduRecord, domain *config.Domain, rdTyp
 
 	err := baidu.request("POST", baiduEndpoint+"/v1/domain/resolve/edit", baiduModifyRequest, &result)
 	if err == nil {
-		util.Log("更新域名解析 %s 成功! IP: %s", domain, ipAddr)
+		util.LogAttrs(slog.LevelInfo, domain.LogAttrs("update"), "更新域名解析 %s 成功! IP: %s", domain, ipAddr)
 		domain.UpdateStatus = config.UpdatedSuccess
 	} else {
-		util.Log("更新域名解析 %s 失败! 异常信息: %s", domain, err)
+		util.LogAttrs(slog.LevelError, domain.LogAttrs("update"), "更新域名解析 %s 失败! 异常信息: %s", domain, err)
 		domain.UpdateStatus = config.UpdatedFailed
 	}
 }
@@ -4,8 +4,10 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"net/url"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -49,6 +51,8 @@ type CloudflareRecord struct {
 	Proxied bool   `json:"proxied"`
 	TTL     int    `json:"ttl"`
 	Comment string `json:"comment"`
+	// Data HTTPS/SVCB/SRV 等记录使用 data 代替 content
+	Data map[string]interface{} `json:"data,omitempty"`
 }
 
 // CloudflareStatus 公共状态
@@ -92,64 +96,85 @@ func (cf *Cloudflare) addUpdateDomainRecords(recordType string) {
 	}
 
 	for _, domain := range domains {
-		// get zone
-		result, err := cf.getZones(domain)
-
-		if err != nil {
-			util.Log("查询域名信息发生异常! %s", err)
-			domain.UpdateStatus = config.UpdatedFailed
+		if !cf.addUpdateRecord(domain, recordType, ipAddr) {
 			return
 		}
+	}
+}
 
-		if len(result.Result) == 0 {
-			util.Log("在DNS服务商中未找到根域名: %s", domain.DomainName)
-			domain.UpdateStatus = config.UpdatedFailed
-			return
-		}
+// AddUpdateRecord 添加或更新 A/AAAA 以外的记录
+func (cf *Cloudflare) AddUpdateRecord(record *config.Record) {
+	cf.addUpdateRecord(record.Domain, record.Type, record.Value)
+}
 
-		params := url.Values{}
-		params.Set("type", recordType)
-		// The name of DNS records in Cloudflare API expects Punycode.
-		//
-		// See: cloudflare/cloudflare-go#690
-		params.Set("name", domain.ToASCII())
-		params.Set("per_page", "50")
-		// Add a comment only if it exists
-		if c := domain.GetCustomParams().Get("comment"); c != "" {
-			params.Set("comment", c)
-		}
+// addUpdateRecord 添加或更新单条记录, 查询域名信息失败时返回 false
+func (cf *Cloudflare) addUpdateRecord(domain *config.Domain, recordType string, value string) bool {
+	// get zone
+	result, err := cf.getZones(domain)
 
-		zoneID := result.Result[0].ID
+	if err != nil {
+		util.LogAttrs(slog.LevelError, domain.LogAttrs("query"), "查询域名信息发生异常! %s", err)
+		domain.UpdateStatus = config.UpdatedFailed
+		return false
+	}
 
-		var records CloudflareRecordsResp
-		// getDomains 最多更新前50条
-		err = cf.request(
-			"GET",
-			fmt.Sprintf(zonesAPI+"/%s/dns_records?%s", zoneID, params.Encode()),
-			nil,
-			&records,
-		)
+	if len(result.Result) == 0 {
+		util.LogAttrs(slog.LevelError, domain.LogAttrs("query"), "在DNS服务商中未找到根域名: %s", domain.DomainName)
+		domain.UpdateStatus = config.UpdatedFailed
+		return false
+	}
 
-		if err != nil {
-			util.Log("查询域名信息发生异常! %s", err)
-			domain.UpdateStatus = config.UpdatedFailed
-			return
-		}
+	params := url.Values{}
+	params.Set("type", recordType)
+	// The name of DNS records in Cloudflare API expects Punycode.
+	//
+	// See: cloudflare/cloudflare-go#690
+	params.Set("name", domain.ToASCII())
+	params.Set("per_page", "50")
+	// Add a comment only if it exists
+	if c := domain.GetCustomParams().Get("comment"); c != "" {
+		params.Set("comment", c)
+	}
 
-		if !records.Success {
-			util.Log("查询域名信息发生异常! %s", strings.Join(records.Messages, ", "))
-			domain.UpdateStatus = config.UpdatedFailed
-			return
-		}
+	zoneID := result.Result[0].ID
 
-		if len(records.Result) > 0 {
-			// 更新
-			cf.modify(records, zoneID, domain, ipAddr)
-		} else {
-			// 新增
-			cf.create(zoneID, domain, recordType, ipAddr)
-		}
+	var records CloudflareRecordsResp
+	// getDomains 最多更新前50条
+	err = cf.request(
+		"GET",
+		fmt.Sprintf(zonesAPI+"/%s/dns_records?%s", zoneID, params.Encode()),
+		nil,
+		&records,
+	)
+
+	if err != nil {
+		util.LogAttrs(slog.LevelError, domain.LogAttrs("query"), "查询域名信息发生异常! %s", err)
+		domain.UpdateStatus = config.UpdatedFailed
+		return false
+	}
+
+	if !records.Success {
+		util.LogAttrs(slog.LevelError, domain.LogAttrs("query"), "查询域名信息发生异常! %s", strings.Join(records.Messages, ", "))
+		domain.UpdateStatus = config.UpdatedFailed
+		return false
+	}
+
+	if recordType == "HTTPS" || recordType == "SVCB" {
+		// 同名的多条记录只更新优先级及目标相同的
+		value = svcbValue(value)
+		records.Result = slices.DeleteFunc(records.Result, func(record CloudflareRecord) bool {
+			return svcbKey(record.getValue()) != svcbKey(value)
+		})
 	}
+
+	if len(records.Result) > 0 {
+		// 更新
+		cf.modify(records, zoneID, domain, value)
+	} else {
+		// 新增
+		cf.create(zoneID, domain, recordType, value)
+	}
+	return true
 }
 
 // 创建
@@ -157,11 +182,11 @@ func (cf *Cloudflare) create(zoneID string, domain *config.Domain, recordType st
 	record := &CloudflareRecord{
 		Type:    recordType,
 		Name:    domain.ToASCII(),
-		Content: ipAddr,
 		Proxied: false,
 		TTL:     cf.TTL,
 		Comment: domain.GetCustomParams().Get("comment"),
 	}
+	record.setValue(ipAddr)
 	record.Proxied = domain.GetCustomParams().Get("proxied") == "true"
 	var status CloudflareStatus
 	err := cf.request(
@@ -172,16 +197,16 @@ func (cf *Cloudflare) create(zoneID string, domain *config.Domain, recordType st
 	)
 
 	if err != nil {
-		util.Log("新增域名解析 %s 失败! 异常信息: %s", domain, err)
+		util.LogAttrs(slog.LevelError, domain.LogAttrs("create"), "新增域名解析 %s 失败! 异常信息: %s", domain, err)
 		domain.UpdateStatus = config.UpdatedFailed
 		return
 	}
 
 	if status.Success {
-		util.Log("新增域名解析 %s 成功! IP: %s", domain, ipAddr)
+		util.LogAttrs(slog.LevelInfo, domain.LogAttrs("create"), "新增域名解析 %s 成功! IP: %s", domain, ipAddr)
 		domain.UpdateStatus = config.UpdatedSuccess
 	} else {
-		util.Log("新增域名解析 %s 失败! 异常信息: %s", domain, strings.Join(status.Messages, ", "))
+		util.LogAttrs(slog.LevelError, domain.LogAttrs("create"), "新增域名解析 %s 失败! 异常信息: %s", domain, strings.Join(status.Messages, ", "))
 		domain.UpdateStatus = config.UpdatedFailed
 	}
 }
@@ -190,12 +215,12 @@ func (cf *Cloudflare) create(zoneID string, domain *config.Domain, recordType st
 func (cf *Cloudflare) modify(result CloudflareRecordsResp, zoneID string, domain *config.Domain, ipAddr string) {
 	for _, record := range result.Result {
 		// 相同不修改
-		if record.Content == ipAddr {
-			util.Log("你的IP %s 没有变化, 域名 %s", ipAddr, domain)
+		if record.getValue() == ipAddr {
+			util.LogAttrs(slog.LevelInfo, domain.LogAttrs("unchanged"), "你的IP %s 没有变化, 域名 %s", ipAddr, domain)
 			continue
 		}
 		var status CloudflareStatus
-		record.Content = ipAddr
+		record.setValue(ipAddr)
 		record.TTL = cf.TTL
 		// 存在参数才修改proxied
 		if domain.GetCustomParams().Has("proxied") {
@@ -209,21 +234,101 @@ func (cf *Cloudflare) modify(result CloudflareRecordsResp, zoneID string, domain
 		)
 
 		if err != nil {
-			util.Log("更新域名解析 %s 失败! 异常信息: %s", domain, err)
+			util.LogAttrs(slog.LevelError, domain.LogAttrs("update"), "更新域名解析 %s 失败! 异常信息: %s", domain, err)
 			domain.UpdateStatus = config.UpdatedFailed
 			return
 		}
 
 		if status.Success {
-			util.Log("更新域名解析 %s 成功! IP: %s", domain, ipAddr)
+			util.LogAttrs(slog.LevelInfo, domain.LogAttrs("update"), "更新域名解析 %s 成功! IP: %s", domain, ipAddr)
 			domain.UpdateStatus = config.UpdatedSuccess
 		} else {
-			util.Log("更新域名解析 %s 失败! 异常信息: %s", domain, strings.Join(status.Messages, ", "))
+			util.LogAttrs(slog.LevelError, domain.LogAttrs("update"), "更新域名解析 %s 失败! 异常信息: %s", domain, strings.Join(status.Messages, ", "))
 			domain.UpdateStatus = config.UpdatedFailed
 		}
 	}
 }
 
+// setValue 设置记录值, HTTPS/SVCB/SRV 记录需拆分为 data 字段
+func (record *CloudflareRecord) setValue(value string) {
+	switch record.Type {
+	case "HTTPS", "SVCB":
+		// 优先级 目标 参数
+		fields := strings.SplitN(svcbValue(value), " ", 3)
+		priority, _ := strconv.Atoi(fields[0])
+		record.Data = map[string]interface{}{"priority": priority, "target": "", "value": ""}
+		if len(fields) > 1 {
+			record.Data["target"] = fields[1]
+		}
+		if len(fields) > 2 {
+			record.Data["value"] = fields[2]
+		}
+		record.Content = ""
+	case "SRV":
+		// 优先级 权重 端口 目标
+		fields := strings.Fields(value)
+		if len(fields) != 4 {
+			record.Content = value
+			return
+		}
+		priority, _ := strconv.Atoi(fields[0])
+		weight, _ := strconv.Atoi(fields[1])
+		port, _ := strconv.Atoi(fields[2])
+		record.Data = map[string]interface{}{"priority": priority, "weight": weight, "port": port, "target": fields[3]}
+		record.Content = ""
+	default:
+		record.Content = value
+	}
+}
+
+// getValue 获得与 setValue 格式一致的记录值
+func (record *CloudflareRecord) getValue() string {
+	switch record.Type {
+	case "HTTPS", "SVCB":
+		if record.Data == nil {
+			return svcbValue(record.Content)
+		}
+		params, _ := record.Data["value"].(string)
+		return svcbValue(fmt.Sprintf("%v %v %s", record.Data["priority"], record.Data["target"], params))
+	case "SRV":
+		if record.Data == nil {
+			return record.Content
+		}
+		return fmt.Sprintf("%v %v %v %v", record.Data["priority"], record.Data["weight"], record.Data["port"], record.Data["target"])
+	default:
+		return record.Content
+	}
+}
+
+// svcbValue 统一 HTTPS/SVCB 记录值的格式: 目标去掉末尾的点, 参数按名称排序且值统一加引号
+func svcbValue(value string) string {
+	fields := strings.Fields(value)
+	if len(fields) < 2 {
+		return value
+	}
+	parts := []string{fields[0], strings.ToLower(trimDot(fields[1]))}
+	var params []string
+	for _, param := range fields[2:] {
+		key, val, ok := strings.Cut(param, "=")
+		key = strings.ToLower(key)
+		if ok {
+			key += `="` + strings.Trim(val, `"`) + `"`
+		}
+		params = append(params, key)
+	}
+	slices.Sort(params)
+	return strings.Join(append(parts, params...), " ")
+}
+
+// svcbKey HTTPS/SVCB 记录的优先级及目标, 用于区分同名的多条记录
+func svcbKey(value string) string {
+	fields := strings.Fields(value)
+	if len(fields) > 2 {
+		fields = fields[:2]
+	}
+	return strings.Join(fields, " ")
+}
+
 // 获得域名记录列表
 func (cf *Cloudflare) getZones(domain *config.Domain) (result CloudflareZonesResp, err error) {
 	params := url.Values{}
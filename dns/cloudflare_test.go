@@ -0,0 +1,37 @@
+package dns
+
+import "testing"
+
+// TestCloudflareSvcbValue 测试 HTTPS/SVCB 记录值统一格式后比较
+func TestCloudflareSvcbValue(t *testing.T) {
+	tests := []struct {
+		record CloudflareRecord
+		value  string
+	}{
+		{CloudflareRecord{Type: "HTTPS", Content: `1 . alpn="h2,h3" port="443"`}, "1 . port=443 alpn=h2,h3"},
+		{CloudflareRecord{Type: "SVCB", Content: `1 svc.example.com. ipv4hint="192.0.2.1"`}, "1 svc.example.com ipv4hint=192.0.2.1"},
+		{CloudflareRecord{Type: "HTTPS", Data: map[string]interface{}{"priority": float64(1), "target": ".", "value": `port="443" alpn="h2"`}}, `1 . alpn="h2" port=443`},
+		{CloudflareRecord{Type: "HTTPS", Data: map[string]interface{}{"priority": float64(0), "target": "example.com", "value": ""}}, "0 example.com."},
+	}
+	for _, tt := range tests {
+		if got, want := tt.record.getValue(), svcbValue(tt.value); got != want {
+			t.Errorf("期待 %q, 得到 %q", want, got)
+		}
+	}
+
+	record := CloudflareRecord{Type: "HTTPS"}
+	record.setValue("1 . port=443 alpn=h2")
+	if record.Data["target"] != "." || record.Data["value"] != `alpn="h2" port="443"` {
+		t.Errorf("期待目标 . 参数 %q, 得到 %v", `alpn="h2" port="443"`, record.Data)
+	}
+}
+
+// TestCloudflareSvcbKey 测试按优先级及目标区分同名的 HTTPS/SVCB 记录
+func TestCloudflareSvcbKey(t *testing.T) {
+	if svcbKey(svcbValue("1 svc.example.com. port=443")) != svcbKey(svcbValue(`1 svc.example.com alpn="h2"`)) {
+		t.Error("期待优先级及目标相同")
+	}
+	if svcbKey(svcbValue("1 . port=443")) == svcbKey(svcbValue("2 . port=443")) {
+		t.Error("期待优先级不同")
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/jeessy2/ddns-go/v6/config"
 	"github.com/jeessy2/ddns-go/v6/util"
 	"io"
+	"log/slog"
 	"net/http"
 	"strconv"
 )
@@ -53,6 +54,9 @@ type DnslaStatus struct {
 	} `json:"data"`
 }
 
+// dnslaRecordTypes 记录类型对应的编号
+var dnslaRecordTypes = map[string]int{"A": 1, "TXT": 16, "AAAA": 28}
+
 // Init 初始化
 func (dnsla *Dnsla) Init(dnsConf *config.DnsConfig, ipv4cache *util.IpCache, ipv6cache *util.IpCache) {
 	dnsla.Domains.Ipv4Cache = ipv4cache
@@ -82,43 +86,53 @@ func (dnsla *Dnsla) addUpdateDomainRecords(recordType string) {
 		return
 	}
 	for _, domain := range domains {
-		resultByte, err := dnsla.getRecordList(domain, recordType)
-		if err != nil {
-			util.Log("查询域名信息发生异常! %s", err)
-			domain.UpdateStatus = config.UpdatedFailed
-			return
-		}
-		var jsonResult DnslaRecordListResp
-		errU := json.Unmarshal(resultByte, &jsonResult)
-		if errU != nil {
-			util.Log(errU.Error())
+		if !dnsla.addUpdateRecord(domain, recordType, ipAddr) {
 			return
 		}
-		if jsonResult.Data.Total > 0 { // 默认第一个
-			recordSelected := jsonResult.Data.Results[0]
-			params := domain.GetCustomParams()
-			if params.Has("id") {
-				for i := 0; i < len(jsonResult.Data.Results); i++ {
-					if jsonResult.Data.Results[i].ID == params.Get("id") {
-						recordSelected = jsonResult.Data.Results[i]
-					}
+	}
+}
+
+// AddUpdateRecord 添加或更新 A/AAAA 以外的记录
+func (dnsla *Dnsla) AddUpdateRecord(record *config.Record) {
+	dnsla.addUpdateRecord(record.Domain, record.Type, record.Value)
+}
+
+// addUpdateRecord 添加或更新单条记录, 查询域名信息失败时返回 false
+func (dnsla *Dnsla) addUpdateRecord(domain *config.Domain, recordType string, value string) bool {
+	resultByte, err := dnsla.getRecordList(domain, recordType)
+	if err != nil {
+		util.LogAttrs(slog.LevelError, domain.LogAttrs("query"), "查询域名信息发生异常! %s", err)
+		domain.UpdateStatus = config.UpdatedFailed
+		return false
+	}
+	var jsonResult DnslaRecordListResp
+	errU := json.Unmarshal(resultByte, &jsonResult)
+	if errU != nil {
+		util.Log(errU.Error())
+		return false
+	}
+	if jsonResult.Data.Total > 0 { // 默认第一个
+		recordSelected := jsonResult.Data.Results[0]
+		params := domain.GetCustomParams()
+		if params.Has("id") {
+			for i := 0; i < len(jsonResult.Data.Results); i++ {
+				if jsonResult.Data.Results[i].ID == params.Get("id") {
+					recordSelected = jsonResult.Data.Results[i]
 				}
 			}
-			// 更新
-			dnsla.modify(recordSelected, domain, recordType, ipAddr)
-		} else {
-			// 新增
-			dnsla.create(domain, recordType, ipAddr)
 		}
+		// 更新
+		dnsla.modify(recordSelected, domain, recordType, value)
+	} else {
+		// 新增
+		dnsla.create(domain, recordType, value)
 	}
+	return true
 }
 
 // 创建
 func (dnsla *Dnsla) create(domain *config.Domain, recordType string, ipAddr string) {
-	recordTypeInt := 1
-	if recordType == "AAAA" {
-		recordTypeInt = 28
-	}
+	recordTypeInt := dnslaRecordTypes[recordType]
 	type CreateParams struct {
 		Domain string `json:"Domain"`
 		Host   string `json:"Host"`
@@ -136,7 +150,7 @@ func (dnsla *Dnsla) create(domain *config.Domain, recordType string, ipAddr stri
 	jsonData, _ := json.Marshal(createParams)
 	resultByte, err := dnsla.request("POST", recordCreate, jsonData)
 	if err != nil {
-		util.Log("新增域名解析 %s 失败! 异常信息: %s", domain, err)
+		util.LogAttrs(slog.LevelError, domain.LogAttrs("create"), "新增域名解析 %s 失败! 异常信息: %s", domain, err)
 		domain.UpdateStatus = config.UpdatedFailed
 		return
 	}
@@ -147,10 +161,10 @@ func (dnsla *Dnsla) create(domain *config.Domain, recordType string, ipAddr stri
 		return
 	}
 	if jsonResult.Code == 200 {
-		util.Log("新增域名解析 %s 成功! IP: %s", domain, ipAddr)
+		util.LogAttrs(slog.LevelInfo, domain.LogAttrs("create"), "新增域名解析 %s 成功! IP: %s", domain, ipAddr)
 		domain.UpdateStatus = config.UpdatedSuccess
 	} else {
-		util.Log("新增域名解析 %s 失败! 异常信息: %s", domain, jsonResult.Msg)
+		util.LogAttrs(slog.LevelError, domain.LogAttrs("create"), "新增域名解析 %s 失败! 异常信息: %s", domain, jsonResult.Msg)
 		domain.UpdateStatus = config.UpdatedFailed
 	}
 }
@@ -159,13 +173,10 @@ func (dnsla *Dnsla) create(domain *config.Domain, recordType string, ipAddr stri
 func (dnsla *Dnsla) modify(record DnslaRecord, domain *config.Domain, recordType string, ipAddr string) {
 	// 相同不修改
 	if record.Data == ipAddr {
-		util.Log("你的IP %s 没有变化, 域名 %s", ipAddr, domain)
+		util.LogAttrs(slog.LevelInfo, domain.LogAttrs("unchanged"), "你的IP %s 没有变化, 域名 %s", ipAddr, domain)
 		return
 	}
-	recordTypeInt := 1
-	if recordType == "AAAA" {
-		recordTypeInt = 28
-	}
+	recordTypeInt := dnslaRecordTypes[recordType]
 	type ModifyParams struct {
 		ID   string `json:"Id"`
 		Host string `json:"Host"`
@@ -184,7 +195,7 @@ func (dnsla *Dnsla) modify(record DnslaRecord, domain *config.Domain, recordType
 	resultByte, err := dnsla.request("PUT", recordModify, jsonData)
 
 	if err != nil {
-		util.Log("更新域名解析 %s 失败! 异常信息: %s", domain, err)
+		util.LogAttrs(slog.LevelError, domain.LogAttrs("update"), "更新域名解析 %s 失败! 异常信息: %s", domain, err)
 		domain.UpdateStatus = config.UpdatedFailed
 		return
 	}
@@ -196,10 +207,10 @@ func (dnsla *Dnsla) modify(record DnslaRecord, domain *config.Domain, recordType
 		return
 	}
 	if jsonResult.Code == 200 {
-		util.Log("更新域名解析 %s 成功! IP: %s", domain, ipAddr)
+		util.LogAttrs(slog.LevelInfo, domain.LogAttrs("update"), "更新域名解析 %s 成功! IP: %s", domain, ipAddr)
 		domain.UpdateStatus = config.UpdatedSuccess
 	} else {
-		util.Log("更新域名解析 %s 失败! 异常信息: %s", domain, jsonResult.Msg)
+		util.LogAttrs(slog.LevelError, domain.LogAttrs("update"), "更新域名解析 %s 失败! 异常信息: %s", domain, jsonResult.Msg)
 		domain.UpdateStatus = config.UpdatedFailed
 	}
 }
@@ -233,10 +244,7 @@ func (dnsla *Dnsla) request(method, apiAddr string, values []byte) (body []byte,
 
 // 获得域名记录列表
 func (dnsla *Dnsla) getRecordList(domain *config.Domain, typ string) (result []byte, err error) {
-	recordTypeInt := "1"
-	if typ == "AAAA" {
-		recordTypeInt = "28"
-	}
+	recordTypeInt := strconv.Itoa(dnslaRecordTypes[typ])
 	params := domain.GetCustomParams()
 	params.Set("domain", domain.DomainName)
 	params.Set("host", domain.GetSubDomain())
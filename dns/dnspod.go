@@ -1,6 +1,7 @@
 package dns
 
 import (
+	"log/slog"
 	"net/http"
 	"net/url"
 
@@ -76,31 +77,44 @@ func (dnspod *Dnspod) addUpdateDomainRecords(recordType string) {
 	}
 
 	for _, domain := range domains {
-		result, err := dnspod.getRecordList(domain, recordType)
-		if err != nil {
-			util.Log("查询域名信息发生异常! %s", err)
-			domain.UpdateStatus = config.UpdatedFailed
+		if !dnspod.addUpdateRecord(domain, recordType, ipAddr) {
 			return
 		}
+	}
+}
 
-		if len(result.Records) > 0 {
-			// 默认第一个
-			recordSelected := result.Records[0]
-			params := domain.GetCustomParams()
-			if params.Has("record_id") {
-				for i := 0; i < len(result.Records); i++ {
-					if result.Records[i].ID == params.Get("record_id") {
-						recordSelected = result.Records[i]
-					}
+// AddUpdateRecord 添加或更新 A/AAAA 以外的记录
+func (dnspod *Dnspod) AddUpdateRecord(record *config.Record) {
+	dnspod.addUpdateRecord(record.Domain, record.Type, record.Value)
+}
+
+// addUpdateRecord 添加或更新单条记录, 查询域名信息失败时返回 false
+func (dnspod *Dnspod) addUpdateRecord(domain *config.Domain, recordType string, value string) bool {
+	result, err := dnspod.getRecordList(domain, recordType)
+	if err != nil {
+		util.LogAttrs(slog.LevelError, domain.LogAttrs("query"), "查询域名信息发生异常! %s", err)
+		domain.UpdateStatus = config.UpdatedFailed
+		return false
+	}
+
+	if len(result.Records) > 0 {
+		// 默认第一个
+		recordSelected := result.Records[0]
+		params := domain.GetCustomParams()
+		if params.Has("record_id") {
+			for i := 0; i < len(result.Records); i++ {
+				if result.Records[i].ID == params.Get("record_id") {
+					recordSelected = result.Records[i]
 				}
 			}
-			// 更新
-			dnspod.modify(recordSelected, domain, recordType, ipAddr)
-		} else {
-			// 新增
-			dnspod.create(domain, recordType, ipAddr)
 		}
+		// 更新
+		dnspod.modify(recordSelected, domain, recordType, value)
+	} else {
+		// 新增
+		dnspod.create(domain, recordType, value)
 	}
+	return true
 }
 
 // 创建
@@ -121,16 +135,16 @@ func (dnspod *Dnspod) create(domain *config.Domain, recordType string, ipAddr st
 	status, err := dnspod.request(recordCreateAPI, params)
 
 	if err != nil {
-		util.Log("新增域名解析 %s 失败! 异常信息: %s", domain, err)
+		util.LogAttrs(slog.LevelError, domain.LogAttrs("create"), "新增域名解析 %s 失败! 异常信息: %s", domain, err)
 		domain.UpdateStatus = config.UpdatedFailed
 		return
 	}
 
 	if status.Status.Code == "1" {
-		util.Log("新增域名解析 %s 成功! IP: %s", domain, ipAddr)
+		util.LogAttrs(slog.LevelInfo, domain.LogAttrs("create"), "新增域名解析 %s 成功! IP: %s", domain, ipAddr)
 		domain.UpdateStatus = config.UpdatedSuccess
 	} else {
-		util.Log("新增域名解析 %s 失败! 异常信息: %s", domain, status.Status.Message)
+		util.LogAttrs(slog.LevelError, domain.LogAttrs("create"), "新增域名解析 %s 失败! 异常信息: %s", domain, status.Status.Message)
 		domain.UpdateStatus = config.UpdatedFailed
 	}
 }
@@ -140,7 +154,7 @@ func (dnspod *Dnspod) modify(record DnspodRecord, domain *config.Domain, recordT
 
 	// 相同不修改
 	if record.Value == ipAddr {
-		util.Log("你的IP %s 没有变化, 域名 %s", ipAddr, domain)
+		util.LogAttrs(slog.LevelInfo, domain.LogAttrs("unchanged"), "你的IP %s 没有变化, 域名 %s", ipAddr, domain)
 		return
 	}
 
@@ -161,16 +175,16 @@ func (dnspod *Dnspod) modify(record DnspodRecord, domain *config.Domain, recordT
 	status, err := dnspod.request(recordModifyURL, params)
 
 	if err != nil {
-		util.Log("更新域名解析 %s 失败! 异常信息: %s", domain, err)
+		util.LogAttrs(slog.LevelError, domain.LogAttrs("update"), "更新域名解析 %s 失败! 异常信息: %s", domain, err)
 		domain.UpdateStatus = config.UpdatedFailed
 		return
 	}
 
 	if status.Status.Code == "1" {
-		util.Log("更新域名解析 %s 成功! IP: %s", domain, ipAddr)
+		util.LogAttrs(slog.LevelInfo, domain.LogAttrs("update"), "更新域名解析 %s 成功! IP: %s", domain, ipAddr)
 		domain.UpdateStatus = config.UpdatedSuccess
 	} else {
-		util.Log("更新域名解析 %s 失败! 异常信息: %s", domain, status.Status.Message)
+		util.LogAttrs(slog.LevelError, domain.LogAttrs("update"), "更新域名解析 %s 失败! 异常信息: %s", domain, status.Status.Message)
 		domain.UpdateStatus = config.UpdatedFailed
 	}
 }
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"github.com/jeessy2/ddns-go/v6/config"
 	"github.com/jeessy2/ddns-go/v6/util"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -150,16 +151,16 @@ func (dynadot *Dynadot) createOrModify(record *DynadotRecord, recordType string,
 	for _, domain := range domains {
 
 		if err != nil {
-			util.Log("更新域名解析 %s 失败! 异常信息: %s", domain, err)
+			util.LogAttrs(slog.LevelError, domain.LogAttrs("update"), "更新域名解析 %s 失败! 异常信息: %s", domain, err)
 			domain.UpdateStatus = config.UpdatedFailed
 			return
 		}
 
 		if result.ErrorCode != -1 {
-			util.Log("更新域名解析 %s 成功! IP: %s", domain, ipAddr)
+			util.LogAttrs(slog.LevelInfo, domain.LogAttrs("update"), "更新域名解析 %s 成功! IP: %s", domain, ipAddr)
 			domain.UpdateStatus = config.UpdatedSuccess
 		} else {
-			util.Log("更新域名解析 %s 失败! 异常信息: %s", domain, strings.Join(result.Content, ","))
+			util.LogAttrs(slog.LevelError, domain.LogAttrs("update"), "更新域名解析 %s 失败! 异常信息: %s", domain, strings.Join(result.Content, ","))
 			domain.UpdateStatus = config.UpdatedFailed
 		}
 	}
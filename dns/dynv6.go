@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"github.com/jeessy2/ddns-go/v6/config"
 	"github.com/jeessy2/ddns-go/v6/util"
+	"log/slog"
 	"net/http"
 	"strconv"
 	"strings"
@@ -66,66 +67,77 @@ func (dynv6 *Dynv6) addUpdateDomainRecords(recordType string) {
 	}
 
 	for _, domain := range domains {
-		isFindZone, findZone, isMain, err := dynv6.findZone(domain)
-
-		if err != nil {
-			util.Log("查询域名信息发生异常! %s", err)
-			domain.UpdateStatus = config.UpdatedFailed
+		if !dynv6.addUpdateRecord(domain, recordType, ipAddr) {
 			return
 		}
+	}
+}
 
-		if !isFindZone {
-			util.Log("在DNS服务商中未找到根域名: %s", domain)
-			domain.UpdateStatus = config.UpdatedFailed
-			continue
-		}
+// AddUpdateRecord 添加或更新 A/AAAA 以外的记录
+func (dynv6 *Dynv6) AddUpdateRecord(record *config.Record) {
+	dynv6.addUpdateRecord(record.Domain, record.Type, record.Value)
+}
 
-		zoneId := strconv.FormatUint(uint64(findZone.ID), 10)
+// addUpdateRecord 添加或更新单条记录, 查询域名信息失败时返回 false
+func (dynv6 *Dynv6) addUpdateRecord(domain *config.Domain, recordType string, value string) bool {
+	isFindZone, findZone, isMain, err := dynv6.findZone(domain)
 
-		if isMain {
-			// 如果使用的域名是主域名，对比DNS记录确定是否调用更新接口
-			if (recordType == "A" && findZone.Ipv4 == ipAddr) || (recordType == "AAAA" && findZone.Ipv6 == ipAddr) {
-				// ip与dns服务器一致，不执行更新
-				util.Log("你的IP %s 没有变化, 域名 %s", ipAddr, domain)
-				domain.UpdateStatus = config.UpdatedNothing
-			} else {
-				dynv6.modifyMain(domain, zoneId, recordType, ipAddr)
-			}
-		} else {
-			// 如果是子域名，检查是否有该子域名记录，有就更新记录，没有就创建
+	if err != nil {
+		util.LogAttrs(slog.LevelError, domain.LogAttrs("query"), "查询域名信息发生异常! %s", err)
+		domain.UpdateStatus = config.UpdatedFailed
+		return false
+	}
 
-			// 处理subDomain
-			processSubDomainOk := dynv6.processSubDomain(domain, findZone)
+	if !isFindZone {
+		util.LogAttrs(slog.LevelError, domain.LogAttrs("query"), "在DNS服务商中未找到根域名: %s", domain)
+		domain.UpdateStatus = config.UpdatedFailed
+		return true
+	}
 
-			if !processSubDomainOk {
-				util.Log("域名: %s 不正确", domain)
-				domain.UpdateStatus = config.UpdatedFailed
-				continue
-			}
+	zoneId := strconv.FormatUint(uint64(findZone.ID), 10)
 
-			isFindRecord, findRecord, err := dynv6.findRecord(domain, zoneId, recordType)
+	if isMain && (recordType == "A" || recordType == "AAAA") {
+		// 如果使用的域名是主域名，对比DNS记录确定是否调用更新接口
+		if (recordType == "A" && findZone.Ipv4 == value) || (recordType == "AAAA" && findZone.Ipv6 == value) {
+			// ip与dns服务器一致，不执行更新
+			util.LogAttrs(slog.LevelInfo, domain.LogAttrs("unchanged"), "你的IP %s 没有变化, 域名 %s", value, domain)
+			domain.UpdateStatus = config.UpdatedNothing
+		} else {
+			dynv6.modifyMain(domain, zoneId, recordType, value)
+		}
+	} else {
+		// 如果是子域名或其它记录，检查是否有该记录，有就更新记录，没有就创建
 
-			if err != nil {
-				util.Log("查询域名信息发生异常! %s", err)
-				domain.UpdateStatus = config.UpdatedFailed
-				return
-			}
+		// 处理subDomain, 主域名的其它记录的名称为空
+		if !isMain && !dynv6.processSubDomain(domain, findZone) {
+			util.Log("域名: %s 不正确", domain)
+			domain.UpdateStatus = config.UpdatedFailed
+			return true
+		}
+
+		isFindRecord, findRecord, err := dynv6.findRecord(domain, zoneId, recordType)
 
-			if isFindRecord {
-				// 判断是否需要更新
-				if findRecord.Type == recordType && findRecord.Data == ipAddr {
-					// ip与dns服务器一致，不执行更新
-					util.Log("你的IP %s 没有变化, 域名 %s", ipAddr, domain)
-					domain.UpdateStatus = config.UpdatedNothing
-				} else {
-					dynv6.modify(domain, zoneId, findRecord, recordType, ipAddr)
-				}
+		if err != nil {
+			util.LogAttrs(slog.LevelError, domain.LogAttrs("query"), "查询域名信息发生异常! %s", err)
+			domain.UpdateStatus = config.UpdatedFailed
+			return false
+		}
+
+		if isFindRecord {
+			// 判断是否需要更新
+			if findRecord.Type == recordType && findRecord.Data == value {
+				// ip与dns服务器一致，不执行更新
+				util.LogAttrs(slog.LevelInfo, domain.LogAttrs("unchanged"), "你的IP %s 没有变化, 域名 %s", value, domain)
+				domain.UpdateStatus = config.UpdatedNothing
 			} else {
-				// 创建记录
-				dynv6.create(domain, zoneId, recordType, ipAddr)
+				dynv6.modify(domain, zoneId, findRecord, recordType, value)
 			}
+		} else {
+			// 创建记录
+			dynv6.create(domain, zoneId, recordType, value)
 		}
 	}
+	return true
 }
 
 func (dynv6 *Dynv6) processSubDomain(domain *config.Domain, zone Dynv6Zone) bool {
@@ -203,10 +215,10 @@ func (dynv6 *Dynv6) modifyMain(domain *config.Domain, zoneId string, recordType
 	err := dynv6.request("PATCH", dynv6Endpoint+"/api/v2/zones/"+zoneId, zoneUpdateReq, &Dynv6Zone{})
 
 	if err != nil {
-		util.Log("更新域名解析 %s 失败! 异常信息: %s", domain, err)
+		util.LogAttrs(slog.LevelError, domain.LogAttrs("update"), "更新域名解析 %s 失败! 异常信息: %s", domain, err)
 		domain.UpdateStatus = config.UpdatedFailed
 	} else {
-		util.Log("更新域名解析 %s 成功! IP: %s", domain, ipAddr)
+		util.LogAttrs(slog.LevelInfo, domain.LogAttrs("update"), "更新域名解析 %s 成功! IP: %s", domain, ipAddr)
 		domain.UpdateStatus = config.UpdatedSuccess
 	}
 }
@@ -222,10 +234,10 @@ func (dynv6 *Dynv6) create(domain *config.Domain, zoneId string, recordType stri
 	err := dynv6.request("POST", dynv6Endpoint+"/api/v2/zones/"+zoneId+"/records", recordUpdateReq, &Dynv6Record{})
 
 	if err != nil {
-		util.Log("新增域名解析 %s 失败! 异常信息: %s", domain, err)
+		util.LogAttrs(slog.LevelError, domain.LogAttrs("create"), "新增域名解析 %s 失败! 异常信息: %s", domain, err)
 		domain.UpdateStatus = config.UpdatedFailed
 	} else {
-		util.Log("新增域名解析 %s 成功! IP: %s", domain, ipAddr)
+		util.LogAttrs(slog.LevelInfo, domain.LogAttrs("create"), "新增域名解析 %s 成功! IP: %s", domain, ipAddr)
 		domain.UpdateStatus = config.UpdatedSuccess
 	}
 }
@@ -240,10 +252,10 @@ func (dynv6 *Dynv6) modify(domain *config.Domain, zoneId string, record Dynv6Rec
 	err := dynv6.request("PATCH", dynv6Endpoint+"/api/v2/zones/"+zoneId+"/records/"+recordId, record, &Dynv6Record{})
 
 	if err != nil {
-		util.Log("更新域名解析 %s 失败! 异常信息: %s", domain, err)
+		util.LogAttrs(slog.LevelError, domain.LogAttrs("update"), "更新域名解析 %s 失败! 异常信息: %s", domain, err)
 		domain.UpdateStatus = config.UpdatedFailed
 	} else {
-		util.Log("更新域名解析 %s 成功! IP: %s", domain, ipAddr)
+		util.LogAttrs(slog.LevelInfo, domain.LogAttrs("update"), "更新域名解析 %s 成功! IP: %s", domain, ipAddr)
 		domain.UpdateStatus = config.UpdatedSuccess
 	}
 }
@@ -3,6 +3,7 @@ package dns
 import (
 	"bytes"
 	"encoding/json"
+	"log/slog"
 	"net/http"
 	"strconv"
 
@@ -112,14 +113,14 @@ func (eo *EdgeOne) addUpdateDomainRecords(recordType string) {
 	for _, domain := range domains {
 		zoneResult, err := eo.getZone(domain.DomainName)
 		if err != nil || zoneResult.Response.TotalCount <= 0 || zoneResult.Response.Zones[0].ZoneName != domain.DomainName {
-			util.Log("查询域名信息发生异常! %s", err)
+			util.LogAttrs(slog.LevelError, domain.LogAttrs("query"), "查询域名信息发生异常! %s", err)
 			domain.UpdateStatus = config.UpdatedFailed
 			return
 		}
 		zoneId := zoneResult.Response.Zones[0].ZoneId
 		recordResult, err := eo.getRecordList(domain, recordType, zoneId)
 		if err != nil {
-			util.Log("查询域名信息发生异常! %s", err)
+			util.LogAttrs(slog.LevelError, domain.LogAttrs("query"), "查询域名信息发生异常! %s", err)
 			domain.UpdateStatus = config.UpdatedFailed
 			return
 		}
@@ -174,16 +175,16 @@ func (eo *EdgeOne) create(domain *config.Domain, recordType string, ipAddr strin
 	)
 
 	if err != nil {
-		util.Log("新增域名解析 %s 失败! 异常信息: %s", domain, err)
+		util.LogAttrs(slog.LevelError, domain.LogAttrs("create"), "新增域名解析 %s 失败! 异常信息: %s", domain, err)
 		domain.UpdateStatus = config.UpdatedFailed
 		return
 	}
 
 	if status.Response.Error.Code == "" {
-		util.Log("新增域名解析 %s 成功! IP: %s", domain, ipAddr)
+		util.LogAttrs(slog.LevelInfo, domain.LogAttrs("create"), "新增域名解析 %s 成功! IP: %s", domain, ipAddr)
 		domain.UpdateStatus = config.UpdatedSuccess
 	} else {
-		util.Log("新增域名解析 %s 失败! 异常信息: %s", domain, status.Response.Error.Message)
+		util.LogAttrs(slog.LevelError, domain.LogAttrs("create"), "新增域名解析 %s 失败! 异常信息: %s", domain, status.Response.Error.Message)
 		domain.UpdateStatus = config.UpdatedFailed
 	}
 }
@@ -192,7 +193,7 @@ func (eo *EdgeOne) create(domain *config.Domain, recordType string, ipAddr strin
 func (eo *EdgeOne) modify(record EdgeOneRecord, domain *config.Domain, recordType string, ipAddr string, ZoneId string) {
 	// 相同不修改
 	if record.Content == ipAddr {
-		util.Log("你的IP %s 没有变化, 域名 %s", ipAddr, domain)
+		util.LogAttrs(slog.LevelInfo, domain.LogAttrs("unchanged"), "你的IP %s 没有变化, 域名 %s", ipAddr, domain)
 		return
 	}
 	var status EdgeOneStatus
@@ -221,16 +222,16 @@ func (eo *EdgeOne) modify(record EdgeOneRecord, domain *config.Domain, recordTyp
 	)
 
 	if err != nil {
-		util.Log("更新域名解析 %s 失败! 异常信息: %s", domain, err)
+		util.LogAttrs(slog.LevelError, domain.LogAttrs("update"), "更新域名解析 %s 失败! 异常信息: %s", domain, err)
 		domain.UpdateStatus = config.UpdatedFailed
 		return
 	}
 
 	if status.Response.Error.Code == "" {
-		util.Log("更新域名解析 %s 成功! IP: %s", domain, ipAddr)
+		util.LogAttrs(slog.LevelInfo, domain.LogAttrs("update"), "更新域名解析 %s 成功! IP: %s", domain, ipAddr)
 		domain.UpdateStatus = config.UpdatedSuccess
 	} else {
-		util.Log("更新域名解析 %s 失败! 异常信息: %s", domain, status.Response.Error.Message)
+		util.LogAttrs(slog.LevelError, domain.LogAttrs("update"), "更新域名解析 %s 失败! 异常信息: %s", domain, status.Response.Error.Message)
 		domain.UpdateStatus = config.UpdatedFailed
 	}
 }
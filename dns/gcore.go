@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -106,35 +107,45 @@ func (gc *Gcore) addUpdateDomainRecords(recordType string) {
 	}
 
 	for _, domain := range domains {
-		// get zone
-		zoneInfo, err := gc.getZoneByDomain(domain)
-		if err != nil {
-			util.Log("查询域名信息发生异常! %s", err)
-			domain.UpdateStatus = config.UpdatedFailed
-			continue
-		}
+		gc.addUpdateRecord(domain, recordType, ipAddr)
+	}
+}
 
-		if zoneInfo == nil {
-			util.Log("在DNS服务商中未找到根域名: %s", domain.DomainName)
-			domain.UpdateStatus = config.UpdatedFailed
-			continue
-		}
+// AddUpdateRecord 添加或更新 A/AAAA 以外的记录
+func (gc *Gcore) AddUpdateRecord(record *config.Record) {
+	gc.addUpdateRecord(record.Domain, record.Type, record.Value)
+}
 
-		// 查询现有记录
-		existingRecord, err := gc.getRRSet(zoneInfo.Name, domain.GetSubDomain(), recordType)
-		if err != nil {
-			util.Log("查询域名信息发生异常! %s", err)
-			domain.UpdateStatus = config.UpdatedFailed
-			continue
-		}
+// addUpdateRecord 添加或更新单条记录
+func (gc *Gcore) addUpdateRecord(domain *config.Domain, recordType string, value string) {
+	// get zone
+	zoneInfo, err := gc.getZoneByDomain(domain)
+	if err != nil {
+		util.LogAttrs(slog.LevelError, domain.LogAttrs("query"), "查询域名信息发生异常! %s", err)
+		domain.UpdateStatus = config.UpdatedFailed
+		return
+	}
 
-		if existingRecord != nil {
-			// 更新现有记录
-			gc.updateRecord(zoneInfo.Name, domain, recordType, ipAddr, existingRecord)
-		} else {
-			// 创建新记录
-			gc.createRecord(zoneInfo.Name, domain, recordType, ipAddr)
-		}
+	if zoneInfo == nil {
+		util.LogAttrs(slog.LevelError, domain.LogAttrs("query"), "在DNS服务商中未找到根域名: %s", domain.DomainName)
+		domain.UpdateStatus = config.UpdatedFailed
+		return
+	}
+
+	// 查询现有记录
+	existingRecord, err := gc.getRRSet(zoneInfo.Name, domain.GetSubDomain(), recordType)
+	if err != nil {
+		util.LogAttrs(slog.LevelError, domain.LogAttrs("query"), "查询域名信息发生异常! %s", err)
+		domain.UpdateStatus = config.UpdatedFailed
+		return
+	}
+
+	if existingRecord != nil {
+		// 更新现有记录
+		gc.updateRecord(zoneInfo.Name, domain, recordType, value, existingRecord)
+	} else {
+		// 创建新记录
+		gc.createRecord(zoneInfo.Name, domain, recordType, value)
 	}
 }
 
@@ -222,12 +233,12 @@ func (gc *Gcore) createRecord(zoneName string, domain *config.Domain, recordType
 	)
 
 	if err != nil {
-		util.Log("新增域名解析 %s 失败! 异常信息: %s", domain, err)
+		util.LogAttrs(slog.LevelError, domain.LogAttrs("create"), "新增域名解析 %s 失败! 异常信息: %s", domain, err)
 		domain.UpdateStatus = config.UpdatedFailed
 		return
 	}
 
-	util.Log("新增域名解析 %s 成功! IP: %s", domain, ipAddr)
+	util.LogAttrs(slog.LevelInfo, domain.LogAttrs("create"), "新增域名解析 %s 成功! IP: %s", domain, ipAddr)
 	domain.UpdateStatus = config.UpdatedSuccess
 }
 
@@ -236,7 +247,7 @@ func (gc *Gcore) updateRecord(zoneName string, domain *config.Domain, recordType
 	// 检查IP是否相同
 	if len(existingRecord.ResourceRecords) > 0 && len(existingRecord.ResourceRecords[0].Content) > 0 {
 		if existingRecord.ResourceRecords[0].Content[0] == ipAddr {
-			util.Log("你的IP %s 没有变化, 域名 %s", ipAddr, domain)
+			util.LogAttrs(slog.LevelInfo, domain.LogAttrs("unchanged"), "你的IP %s 没有变化, 域名 %s", ipAddr, domain)
 			return
 		}
 	}
@@ -267,12 +278,12 @@ func (gc *Gcore) updateRecord(zoneName string, domain *config.Domain, recordType
 	)
 
 	if err != nil {
-		util.Log("更新域名解析 %s 失败! 异常信息: %s", domain, err)
+		util.LogAttrs(slog.LevelError, domain.LogAttrs("update"), "更新域名解析 %s 失败! 异常信息: %s", domain, err)
 		domain.UpdateStatus = config.UpdatedFailed
 		return
 	}
 
-	util.Log("更新域名解析 %s 成功! IP: %s", domain, ipAddr)
+	util.LogAttrs(slog.LevelInfo, domain.LogAttrs("update"), "更新域名解析 %s 成功! IP: %s", domain, ipAddr)
 	domain.UpdateStatus = config.UpdatedSuccess
 }
 
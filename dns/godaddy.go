@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"strconv"
 
@@ -69,19 +70,29 @@ func (g *GoDaddyDNS) updateDomainRecord(recordType string, ipAddr string, domain
 	}
 
 	for _, domain := range domains {
-		err := g.sendReq(http.MethodPut, recordType, domain, &godaddyRecords{godaddyRecord{
-			Data: ipAddr,
-			Name: domain.GetSubDomain(),
-			TTL:  g.ttl,
-			Type: recordType,
-		}})
-		if err == nil {
-			util.Log("更新域名解析 %s 成功! IP: %s", domain, ipAddr)
-			domain.UpdateStatus = config.UpdatedSuccess
-		} else {
-			util.Log("更新域名解析 %s 失败! 异常信息: %s", domain, err)
-			domain.UpdateStatus = config.UpdatedFailed
-		}
+		g.putRecord(domain, recordType, ipAddr)
+	}
+}
+
+// AddUpdateRecord 添加或更新 A/AAAA 以外的记录
+func (g *GoDaddyDNS) AddUpdateRecord(record *config.Record) {
+	g.putRecord(record.Domain, record.Type, record.Value)
+}
+
+// putRecord 替换域名的所有同类型记录, 不存在时创建
+func (g *GoDaddyDNS) putRecord(domain *config.Domain, recordType string, value string) {
+	err := g.sendReq(http.MethodPut, recordType, domain, &godaddyRecords{godaddyRecord{
+		Data: value,
+		Name: domain.GetSubDomain(),
+		TTL:  g.ttl,
+		Type: recordType,
+	}})
+	if err == nil {
+		util.LogAttrs(slog.LevelInfo, domain.LogAttrs("update"), "更新域名解析 %s 成功! IP: %s", domain, value)
+		domain.UpdateStatus = config.UpdatedSuccess
+	} else {
+		util.LogAttrs(slog.LevelError, domain.LogAttrs("update"), "更新域名解析 %s 失败! 异常信息: %s", domain, err)
+		domain.UpdateStatus = config.UpdatedFailed
 	}
 }
 
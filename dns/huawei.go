@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -86,95 +87,113 @@ func (hw *Huaweicloud) addUpdateDomainRecords(recordType string) {
 	}
 
 	for _, domain := range domains {
-		customParams := domain.GetCustomParams()
-		params := url.Values{}
-		params.Set("name", domain.String())
-		params.Set("type", recordType)
-
-		// 如果有精准匹配
-		// 详见 查询记录集 https://support.huaweicloud.com/api-dns/dns_api_64002.html
-		if customParams.Has("zone_id") && customParams.Has("recordset_id") {
-			var record HuaweicloudRecordsets
-			err := hw.request(
-				"GET",
-				fmt.Sprintf(huaweicloudEndpoint+"/v2.1/zones/%s/recordsets/%s", customParams.Get("zone_id"), customParams.Get("recordset_id")),
-				params,
-				&record,
-			)
-
-			if err != nil {
-				util.Log("查询域名信息发生异常！ %s", err)
-				domain.UpdateStatus = config.UpdatedFailed
-				return
-			}
+		if !hw.addUpdateRecord(domain, recordType, ipAddr) {
+			return
+		}
+	}
+}
 
-			// 更新
-			hw.modify(record, domain, ipAddr)
+// AddUpdateRecord 添加或更新 A/AAAA 以外的记录
+func (hw *Huaweicloud) AddUpdateRecord(record *config.Record) {
+	hw.addUpdateRecord(record.Domain, record.Type, record.Value)
+}
 
-		} else { // 没有精准匹配，则支持更多的查询参数。详见 查询租户记录集列表 https://support.huaweicloud.com/api-dns/dns_api_64003.html
-			// 复制所有自定义参数
-			util.CopyUrlParams(customParams, params, nil)
-			// 参数名修正
-			if params.Has("recordset_id") {
-				params.Set("id", params.Get("recordset_id"))
-				params.Del("recordset_id")
-			}
+// addUpdateRecord 添加或更新单条记录, 查询域名信息失败时返回 false
+func (hw *Huaweicloud) addUpdateRecord(domain *config.Domain, recordType string, value string) bool {
+	// TXT记录值需要使用双引号
+	if recordType == "TXT" {
+		value = strconv.Quote(value)
+	}
+
+	customParams := domain.GetCustomParams()
+	params := url.Values{}
+	params.Set("name", domain.String())
+	params.Set("type", recordType)
+
+	// 如果有精准匹配
+	// 详见 查询记录集 https://support.huaweicloud.com/api-dns/dns_api_64002.html
+	if customParams.Has("zone_id") && customParams.Has("recordset_id") {
+		var record HuaweicloudRecordsets
+		err := hw.request(
+			"GET",
+			fmt.Sprintf(huaweicloudEndpoint+"/v2.1/zones/%s/recordsets/%s", customParams.Get("zone_id"), customParams.Get("recordset_id")),
+			params,
+			&record,
+		)
+
+		if err != nil {
+			util.LogAttrs(slog.LevelError, domain.LogAttrs("query"), "查询域名信息发生异常！ %s", err)
+			domain.UpdateStatus = config.UpdatedFailed
+			return false
+		}
+
+		// 更新
+		hw.modify(record, domain, value)
+
+	} else { // 没有精准匹配，则支持更多的查询参数。详见 查询租户记录集列表 https://support.huaweicloud.com/api-dns/dns_api_64003.html
+		// 复制所有自定义参数
+		util.CopyUrlParams(customParams, params, nil)
+		// 参数名修正
+		if params.Has("recordset_id") {
+			params.Set("id", params.Get("recordset_id"))
+			params.Del("recordset_id")
+		}
+
+		var records HuaweicloudRecordsResp
+		err := hw.request(
+			"GET",
+			huaweicloudEndpoint+"/v2.1/recordsets",
+			params,
+			&records,
+		)
 
-			var records HuaweicloudRecordsResp
-			err := hw.request(
-				"GET",
-				huaweicloudEndpoint+"/v2.1/recordsets",
-				params,
-				&records,
-			)
-
-			if err != nil {
-				util.Log("查询域名信息发生异常! %s", err)
-				domain.UpdateStatus = config.UpdatedFailed
-				return
+		if err != nil {
+			util.LogAttrs(slog.LevelError, domain.LogAttrs("query"), "查询域名信息发生异常! %s", err)
+			domain.UpdateStatus = config.UpdatedFailed
+			return false
+		}
+
+		find := false
+		for _, record := range records.Recordsets {
+			// 名称相同才更新。华为云默认是模糊搜索
+			if record.Name == domain.String()+"." {
+				// 更新
+				hw.modify(record, domain, value)
+				find = true
+				break
 			}
+		}
 
-			find := false
-			for _, record := range records.Recordsets {
-				// 名称相同才更新。华为云默认是模糊搜索
-				if record.Name == domain.String()+"." {
-					// 更新
-					hw.modify(record, domain, ipAddr)
-					find = true
-					break
-				}
+		if !find {
+			thIdParamName := ""
+			if customParams.Has("id") {
+				thIdParamName = "id"
+			} else if customParams.Has("recordset_id") {
+				thIdParamName = "recordset_id"
 			}
 
-			if !find {
-				thIdParamName := ""
-				if customParams.Has("id") {
-					thIdParamName = "id"
-				} else if customParams.Has("recordset_id") {
-					thIdParamName = "recordset_id"
-				}
-
-				if thIdParamName != "" {
-					util.Log("域名 %s 解析未找到，且因添加了参数 %s=%s 导致无法创建。本次更新已被忽略", domain, thIdParamName, customParams.Get(thIdParamName))
-				} else {
-					// 新增
-					hw.create(domain, recordType, ipAddr)
-				}
+			if thIdParamName != "" {
+				util.Log("域名 %s 解析未找到，且因添加了参数 %s=%s 导致无法创建。本次更新已被忽略", domain, thIdParamName, customParams.Get(thIdParamName))
+			} else {
+				// 新增
+				hw.create(domain, recordType, value)
 			}
 		}
 	}
+	return true
 }
 
 // 创建
-func (hw *Huaweicloud) create(domain *config.Domain, recordType string, ipAddr string) {
+func (hw *Huaweicloud) create(domain *config.Domain, recordType string, value string) {
 	zone, err := hw.getZones(domain)
 	if err != nil {
-		util.Log("查询域名信息发生异常! %s", err)
+		util.LogAttrs(slog.LevelError, domain.LogAttrs("query"), "查询域名信息发生异常! %s", err)
 		domain.UpdateStatus = config.UpdatedFailed
 		return
 	}
 
 	if len(zone.Zones) == 0 {
-		util.Log("在DNS服务商中未找到根域名: %s", domain.DomainName)
+		util.LogAttrs(slog.LevelError, domain.LogAttrs("query"), "在DNS服务商中未找到根域名: %s", domain.DomainName)
 		domain.UpdateStatus = config.UpdatedFailed
 		return
 	}
@@ -190,7 +209,7 @@ func (hw *Huaweicloud) create(domain *config.Domain, recordType string, ipAddr s
 	record := &HuaweicloudRecordsets{
 		Type:    recordType,
 		Name:    domain.String() + ".",
-		Records: []string{ipAddr},
+		Records: []string{value},
 		TTL:     hw.TTL,
 		Weight:  1,
 	}
@@ -203,16 +222,16 @@ func (hw *Huaweicloud) create(domain *config.Domain, recordType string, ipAddr s
 	)
 
 	if err != nil {
-		util.Log("新增域名解析 %s 失败! 异常信息: %s", domain, err)
+		util.LogAttrs(slog.LevelError, domain.LogAttrs("create"), "新增域名解析 %s 失败! 异常信息: %s", domain, err)
 		domain.UpdateStatus = config.UpdatedFailed
 		return
 	}
 
-	if len(result.Records) > 0 && result.Records[0] == ipAddr {
-		util.Log("新增域名解析 %s 成功! IP: %s", domain, ipAddr)
+	if len(result.Records) > 0 && result.Records[0] == value {
+		util.LogAttrs(slog.LevelInfo, domain.LogAttrs("create"), "新增域名解析 %s 成功! IP: %s", domain, value)
 		domain.UpdateStatus = config.UpdatedSuccess
 	} else {
-		util.Log("新增域名解析 %s 失败! 异常信息: %s", domain, result.Status)
+		util.LogAttrs(slog.LevelError, domain.LogAttrs("create"), "新增域名解析 %s 失败! 异常信息: %s", domain, result.Status)
 		domain.UpdateStatus = config.UpdatedFailed
 	}
 }
@@ -222,7 +241,7 @@ func (hw *Huaweicloud) modify(record HuaweicloudRecordsets, domain *config.Domai
 
 	// 相同不修改
 	if len(record.Records) > 0 && record.Records[0] == ipAddr {
-		util.Log("你的IP %s 没有变化, 域名 %s", ipAddr, domain)
+		util.LogAttrs(slog.LevelInfo, domain.LogAttrs("unchanged"), "你的IP %s 没有变化, 域名 %s", ipAddr, domain)
 		return
 	}
 
@@ -242,16 +261,16 @@ func (hw *Huaweicloud) modify(record HuaweicloudRecordsets, domain *config.Domai
 	)
 
 	if err != nil {
-		util.Log("更新域名解析 %s 失败! 异常信息: %s", domain, err)
+		util.LogAttrs(slog.LevelError, domain.LogAttrs("update"), "更新域名解析 %s 失败! 异常信息: %s", domain, err)
 		domain.UpdateStatus = config.UpdatedFailed
 		return
 	}
 
 	if len(result.Records) > 0 && result.Records[0] == ipAddr {
-		util.Log("更新域名解析 %s 成功! IP: %s", domain, ipAddr)
+		util.LogAttrs(slog.LevelInfo, domain.LogAttrs("update"), "更新域名解析 %s 成功! IP: %s", domain, ipAddr)
 		domain.UpdateStatus = config.UpdatedSuccess
 	} else {
-		util.Log("更新域名解析 %s 失败! 异常信息: %s", domain, result.Status)
+		util.LogAttrs(slog.LevelError, domain.LogAttrs("update"), "更新域名解析 %s 失败! 异常信息: %s", domain, result.Status)
 		domain.UpdateStatus = config.UpdatedFailed
 	}
 }
@@ -1,6 +1,7 @@
 package dns
 
 import (
+	"sync"
 	"time"
 
 	"github.com/jeessy2/ddns-go/v6/config"
@@ -14,6 +15,12 @@ type DNS interface {
 	AddUpdateDomainRecords() (domains config.Domains)
 }
 
+// RecordUpdater 支持更新 A/AAAA 以外记录的DNS服务商
+type RecordUpdater interface {
+	// 添加或更新记录, 结果保存在 record.Domain.UpdateStatus
+	AddUpdateRecord(record *config.Record)
+}
+
 var (
 	Addresses = []string{
 		alidnsEndpoint,
@@ -33,10 +40,17 @@ var (
 	}
 
 	Ipcache = [][2]util.IpCache{}
+
+	// runMutex 防止定时更新与网卡地址变化触发的更新同时运行
+	runMutex sync.Mutex
 )
 
 // RunTimer 定时运行
 func RunTimer(delay time.Duration) {
+	// 网卡地址变化触发的更新会在 runMutex 中读取
+	runMutex.Lock()
+	runInterval = delay
+	runMutex.Unlock()
 	for {
 		RunOnce()
 		time.Sleep(delay)
@@ -45,83 +59,132 @@ func RunTimer(delay time.Duration) {
 
 // RunOnce RunOnce
 func RunOnce() {
+	runMutex.Lock()
+	defer runMutex.Unlock()
+
 	conf, err := config.GetConfigCached()
 	if err != nil {
 		return
 	}
+	resetIpcache(&conf)
+
+	for i, dc := range conf.DnsConf {
+		runDnsConf(i, dc, &conf)
+	}
+
+	util.ForceCompareGlobal = false
+}
+
+// RunOnceFor 仅运行第 index 个配置, 用于网卡地址变化时立即更新
+func RunOnceFor(index int) {
+	runMutex.Lock()
+	defer runMutex.Unlock()
+
+	conf, err := config.GetConfigCached()
+	if err != nil || index >= len(conf.DnsConf) {
+		return
+	}
+	resetIpcache(&conf)
+
+	runDnsConf(index, conf.DnsConf[index], &conf)
+}
+
+// resetIpcache 配置数量变化或需要强制比对时重置所有cache
+func resetIpcache(conf *config.Config) {
 	if util.ForceCompareGlobal || len(Ipcache) != len(conf.DnsConf) {
 		Ipcache = [][2]util.IpCache{}
 		for range conf.DnsConf {
 			Ipcache = append(Ipcache, [2]util.IpCache{{}, {}})
 		}
 	}
+}
 
-	for i, dc := range conf.DnsConf {
-		var dnsSelected DNS
-		switch dc.DNS.Name {
-		case "alidns":
-			dnsSelected = &Alidns{}
-		case "aliesa":
-			dnsSelected = &Aliesa{}
-		case "tencentcloud":
-			dnsSelected = &TencentCloud{}
-		case "trafficroute":
-			dnsSelected = &TrafficRoute{}
-		case "dnspod":
-			dnsSelected = &Dnspod{}
-		case "dnsla":
-			dnsSelected = &Dnsla{}
-		case "cloudflare":
-			dnsSelected = &Cloudflare{}
-		case "huaweicloud":
-			dnsSelected = &Huaweicloud{}
-		case "callback":
-			dnsSelected = &Callback{}
-		case "baiducloud":
-			dnsSelected = &BaiduCloud{}
-		case "porkbun":
-			dnsSelected = &Porkbun{}
-		case "godaddy":
-			dnsSelected = &GoDaddyDNS{}
-		case "namecheap":
-			dnsSelected = &NameCheap{}
-		case "namesilo":
-			dnsSelected = &NameSilo{}
-		case "vercel":
-			dnsSelected = &Vercel{}
-		case "dynadot":
-			dnsSelected = &Dynadot{}
-		case "dynv6":
-			dnsSelected = &Dynv6{}
-		case "spaceship":
-			dnsSelected = &Spaceship{}
-		case "nowcn":
-			dnsSelected = &Nowcn{}
-		case "eranet":
-			dnsSelected = &Eranet{}
-		case "gcore":
-			dnsSelected = &Gcore{}
-		case "edgeone":
-			dnsSelected = &EdgeOne{}
-		case "nsone":
-			dnsSelected = &NSOne{}
-		case "name_com":
-			dnsSelected = &NameCom{}
-		default:
-			dnsSelected = &Alidns{}
-		}
-		dnsSelected.Init(&dc, &Ipcache[i][0], &Ipcache[i][1])
-		domains := dnsSelected.AddUpdateDomainRecords()
-		// webhook
-		v4Status, v6Status := config.ExecWebhook(&domains, &conf)
-		// 重置单个cache
-		if v4Status == config.UpdatedFailed {
-			Ipcache[i][0] = util.IpCache{}
-		}
-		if v6Status == config.UpdatedFailed {
-			Ipcache[i][1] = util.IpCache{}
-		}
+// newDNS 根据名称创建DNS服务商, 未知的使用阿里云
+func newDNS(name string) DNS {
+	switch name {
+	case "alidns":
+		return &Alidns{}
+	case "aliesa":
+		return &Aliesa{}
+	case "tencentcloud":
+		return &TencentCloud{}
+	case "trafficroute":
+		return &TrafficRoute{}
+	case "dnspod":
+		return &Dnspod{}
+	case "dnsla":
+		return &Dnsla{}
+	case "cloudflare":
+		return &Cloudflare{}
+	case "huaweicloud":
+		return &Huaweicloud{}
+	case "callback":
+		return &Callback{}
+	case "baiducloud":
+		return &BaiduCloud{}
+	case "porkbun":
+		return &Porkbun{}
+	case "godaddy":
+		return &GoDaddyDNS{}
+	case "namecheap":
+		return &NameCheap{}
+	case "namesilo":
+		return &NameSilo{}
+	case "vercel":
+		return &Vercel{}
+	case "dynadot":
+		return &Dynadot{}
+	case "dynv6":
+		return &Dynv6{}
+	case "spaceship":
+		return &Spaceship{}
+	case "nowcn":
+		return &Nowcn{}
+	case "eranet":
+		return &Eranet{}
+	case "gcore":
+		return &Gcore{}
+	case "edgeone":
+		return &EdgeOne{}
+	case "nsone":
+		return &NSOne{}
+	case "name_com":
+		return &NameCom{}
+	default:
+		return &Alidns{}
 	}
+}
 
-	util.ForceCompareGlobal = false
+// runDnsConf 运行第 i 个配置
+func runDnsConf(i int, dc config.DnsConfig, conf *config.Config) {
+	dnsSelected := newDNS(dc.DNS.Name)
+	start := time.Now()
+	dnsSelected.Init(&dc, &Ipcache[i][0], &Ipcache[i][1])
+	domains := dnsSelected.AddUpdateDomainRecords()
+	// 更新其它记录
+	records, recordsFailed := updateRecords(dnsSelected, &dc, &domains)
+	// 校验权威DNS服务器
+	verifyDomains(&dc, &domains, records)
+	duration := time.Since(start)
+	// 心跳TXT记录, 每次都会更新, 只在失败时保存到更新历史
+	if !recordsFailed {
+		if heartbeat := updateHeartbeat(dnsSelected, &dc, &domains); heartbeat != nil && heartbeat.Domain.UpdateStatus == config.UpdatedFailed {
+			records = append(records, heartbeat)
+		}
+	}
+	// webhook
+	v4Status, v6Status := config.ExecWebhook(i, &domains, conf, &dc)
+	// 结构化日志
+	domains.RecordStatus()
+	// 更新历史
+	domains.SaveHistory(&dc, conf.History, duration, records)
+	// MQTT
+	config.PublishMqtt(i, &domains, conf, &dc, v4Status, v6Status)
+	// 重置单个cache, 其它记录更新失败时都重置
+	if v4Status == config.UpdatedFailed || v4Status == config.UpdatedUnverified || recordsFailed {
+		Ipcache[i][0] = util.IpCache{}
+	}
+	if v6Status == config.UpdatedFailed || v6Status == config.UpdatedUnverified || recordsFailed {
+		Ipcache[i][1] = util.IpCache{}
+	}
 }
@@ -5,6 +5,7 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"strconv"
 	"strings"
@@ -80,36 +81,49 @@ func (n *NameCom) addUpdateDomainRecords(recordType string) {
 	}
 
 	for _, domain := range domains {
-		resp, err := n.getRecordList(domain)
-		if err != nil {
-			util.Log("查询域名信息发生异常! %s", err)
-			domain.UpdateStatus = config.UpdatedFailed
+		if !n.addUpdateRecord(domain, recordType, ipAddr) {
 			return
 		}
-		resp4TypeRecords := make([]NameComRecordResp, 0, resp.TotalCount)
-		if resp.TotalCount > 0 {
-			for _, r := range resp.Records {
-				if r.Type == recordType && r.Host == domain.SubDomain {
-					resp4TypeRecords = append(resp4TypeRecords, r)
-				}
+	}
+}
+
+// AddUpdateRecord 添加或更新 A/AAAA 以外的记录
+func (n *NameCom) AddUpdateRecord(record *config.Record) {
+	n.addUpdateRecord(record.Domain, record.Type, record.Value)
+}
+
+// addUpdateRecord 添加或更新单条记录, 查询域名信息失败时返回 false
+func (n *NameCom) addUpdateRecord(domain *config.Domain, recordType string, value string) bool {
+	resp, err := n.getRecordList(domain)
+	if err != nil {
+		util.LogAttrs(slog.LevelError, domain.LogAttrs("query"), "查询域名信息发生异常! %s", err)
+		domain.UpdateStatus = config.UpdatedFailed
+		return false
+	}
+	resp4TypeRecords := make([]NameComRecordResp, 0, resp.TotalCount)
+	if resp.TotalCount > 0 {
+		for _, r := range resp.Records {
+			if r.Type == recordType && r.Host == domain.SubDomain {
+				resp4TypeRecords = append(resp4TypeRecords, r)
 			}
 		}
-		if len(resp4TypeRecords) > 0 {
-			for _, r := range resp4TypeRecords {
-				err := n.update(r, domain, ipAddr, recordType)
-				if err != nil {
-					domain.UpdateStatus = config.UpdatedFailed
-					return
-				}
-			}
-		} else {
-			_, err := n.create(domain, recordType, ipAddr)
+	}
+	if len(resp4TypeRecords) > 0 {
+		for _, r := range resp4TypeRecords {
+			err := n.update(r, domain, value, recordType)
 			if err != nil {
 				domain.UpdateStatus = config.UpdatedFailed
-				return
+				return false
 			}
 		}
+	} else {
+		_, err := n.create(domain, recordType, value)
+		if err != nil {
+			domain.UpdateStatus = config.UpdatedFailed
+			return false
+		}
 	}
+	return true
 }
 
 func (n *NameCom) getRecordList(domain *config.Domain) (resp *NameComRecordListResp, err error) {
@@ -133,16 +147,16 @@ func (n *NameCom) create(domain *config.Domain, recordType string, ipAddr string
 	url := fmt.Sprintf(createRecord, domain.DomainName)
 	err = n.request("POST", url, resq, resp)
 	if err != nil {
-		util.Log("新增域名解析 %s 失败! 异常信息: %s", domain, err)
+		util.LogAttrs(slog.LevelError, domain.LogAttrs("create"), "新增域名解析 %s 失败! 异常信息: %s", domain, err)
 		return
 	}
-	util.Log("新增域名解析 %s 成功! IP: %s", domain, ipAddr)
+	util.LogAttrs(slog.LevelInfo, domain.LogAttrs("create"), "新增域名解析 %s 成功! IP: %s", domain, ipAddr)
 	return
 }
 
 func (n *NameCom) update(record NameComRecordResp, domain *config.Domain, ipAddr, recordType string) (err error) {
 	if record.Answer == ipAddr {
-		util.Log("你的IP %s 没有变化, 域名 %s", ipAddr, domain)
+		util.LogAttrs(slog.LevelInfo, domain.LogAttrs("unchanged"), "你的IP %s 没有变化, 域名 %s", ipAddr, domain)
 		return
 	}
 	record.Answer = ipAddr
@@ -150,10 +164,10 @@ func (n *NameCom) update(record NameComRecordResp, domain *config.Domain, ipAddr
 	url := fmt.Sprintf(updateRecord, domain.DomainName, record.Id)
 	err = n.request("PUT", url, record, nil)
 	if err != nil {
-		util.Log("更新域名解析 %s 失败! 异常信息: %s", domain, err)
+		util.LogAttrs(slog.LevelError, domain.LogAttrs("update"), "更新域名解析 %s 失败! 异常信息: %s", domain, err)
 		return
 	}
-	util.Log("更新域名解析 %s 成功! IP: %s", domain, ipAddr)
+	util.LogAttrs(slog.LevelInfo, domain.LogAttrs("update"), "更新域名解析 %s 成功! IP: %s", domain, ipAddr)
 	return
 }
 
@@ -3,6 +3,7 @@ package dns
 import (
 	"encoding/xml"
 	"io"
+	"log/slog"
 	"net/http"
 	"strings"
 
@@ -99,7 +100,7 @@ func (ns *NameSilo) addUpdateDomainRecords(recordType string) {
 		// 拿到DNS记录列表，从列表中去取对应域名的id，有id进行修改，没ID进行新增
 		records, err := ns.listRecords(domain)
 		if err != nil {
-			util.Log("查询域名信息发生异常! %s", err)
+			util.LogAttrs(slog.LevelError, domain.LogAttrs("query"), "查询域名信息发生异常! %s", err)
 			domain.UpdateStatus = config.UpdatedFailed
 			return
 		}
@@ -112,7 +113,7 @@ func (ns *NameSilo) addUpdateDomainRecords(recordType string) {
 		} else {
 			recordID = record.RecordID
 			if record.Value == ipAddr {
-				util.Log("你的IP %s 没有变化, 域名 %s", ipAddr, domain)
+				util.LogAttrs(slog.LevelInfo, domain.LogAttrs("unchanged"), "你的IP %s 没有变化, 域名 %s", ipAddr, domain)
 				continue
 			}
 		}
@@ -7,6 +7,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"sort"
 	"strconv"
@@ -83,7 +84,7 @@ func (nowcn *Nowcn) addUpdateDomainRecords(recordType string) {
 	for _, domain := range domains {
 		result, err := nowcn.getRecordList(domain, recordType)
 		if err != nil {
-			util.Log("查询域名信息发生异常! %s", err)
+			util.LogAttrs(slog.LevelError, domain.LogAttrs("query"), "查询域名信息发生异常! %s", err)
 			domain.UpdateStatus = config.UpdatedFailed
 			return
 		}
@@ -119,17 +120,17 @@ func (nowcn *Nowcn) create(domain *config.Domain, recordType string, ipAddr stri
 	}
 	res, err := nowcn.request("/api/Dns/AddDomainRecord", param, "GET")
 	if err != nil {
-		util.Log("新增域名解析 %s 失败! 异常信息: %s", domain, err.Error())
+		util.LogAttrs(slog.LevelError, domain.LogAttrs("create"), "新增域名解析 %s 失败! 异常信息: %s", domain, err.Error())
 		domain.UpdateStatus = config.UpdatedFailed
 	}
 	var result NowcnBaseResult
 	err = json.Unmarshal(res, &result)
 	if err != nil {
-		util.Log("新增域名解析 %s 失败! 异常信息: %s", domain, err.Error())
+		util.LogAttrs(slog.LevelError, domain.LogAttrs("create"), "新增域名解析 %s 失败! 异常信息: %s", domain, err.Error())
 		domain.UpdateStatus = config.UpdatedFailed
 	}
 	if result.Error != "" {
-		util.Log("新增域名解析 %s 失败! 异常信息: %s", domain, result.Error)
+		util.LogAttrs(slog.LevelError, domain.LogAttrs("create"), "新增域名解析 %s 失败! 异常信息: %s", domain, result.Error)
 		domain.UpdateStatus = config.UpdatedFailed
 	} else {
 		domain.UpdateStatus = config.UpdatedSuccess
@@ -140,7 +141,7 @@ func (nowcn *Nowcn) create(domain *config.Domain, recordType string, ipAddr stri
 func (nowcn *Nowcn) modify(record NowcnRecord, domain *config.Domain, recordType string, ipAddr string) {
 	// 相同不修改
 	if record.Value == ipAddr {
-		util.Log("你的IP %s 没有变化, 域名 %s", ipAddr, domain)
+		util.LogAttrs(slog.LevelInfo, domain.LogAttrs("unchanged"), "你的IP %s 没有变化, 域名 %s", ipAddr, domain)
 		return
 	}
 	param := map[string]string{
@@ -153,20 +154,20 @@ func (nowcn *Nowcn) modify(record NowcnRecord, domain *config.Domain, recordType
 	}
 	res, err := nowcn.request("/api/Dns/UpdateDomainRecord", param, "GET")
 	if err != nil {
-		util.Log("更新域名解析 %s 失败! 异常信息: %s", domain, err.Error())
+		util.LogAttrs(slog.LevelError, domain.LogAttrs("update"), "更新域名解析 %s 失败! 异常信息: %s", domain, err.Error())
 		domain.UpdateStatus = config.UpdatedFailed
 	}
 	var result NowcnBaseResult
 	err = json.Unmarshal(res, &result)
 	if err != nil {
-		util.Log("更新域名解析 %s 失败! 异常信息: %s", domain, err.Error())
+		util.LogAttrs(slog.LevelError, domain.LogAttrs("update"), "更新域名解析 %s 失败! 异常信息: %s", domain, err.Error())
 		domain.UpdateStatus = config.UpdatedFailed
 	}
 	if result.Error != "" {
-		util.Log("更新域名解析 %s 失败! 异常信息: %s", domain, result.Error)
+		util.LogAttrs(slog.LevelError, domain.LogAttrs("update"), "更新域名解析 %s 失败! 异常信息: %s", domain, result.Error)
 		domain.UpdateStatus = config.UpdatedFailed
 	} else {
-		util.Log("更新域名解析 %s 成功! IP: %s", domain, ipAddr)
+		util.LogAttrs(slog.LevelInfo, domain.LogAttrs("update"), "更新域名解析 %s 成功! IP: %s", domain, ipAddr)
 		domain.UpdateStatus = config.UpdatedSuccess
 	}
 }
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -207,31 +208,41 @@ func (nsone *NSOne) addUpdateDomainRecords(recordType string) {
 	}
 
 	for _, domain := range domains {
-		zoneInfo, err := nsone.getZone(domain)
-		if err != nil {
-			util.Log("查询域名信息发生异常! %s", err)
-			domain.UpdateStatus = config.UpdatedFailed
-			continue
-		}
+		nsone.addUpdateRecord(domain, recordType, ipAddr)
+	}
+}
 
-		if zoneInfo == nil {
-			util.Log("在DNS服务商中未找到根域名: %s", domain.DomainName)
-			domain.UpdateStatus = config.UpdatedFailed
-			continue
-		}
+// AddUpdateRecord 添加或更新 A/AAAA 以外的记录
+func (nsone *NSOne) AddUpdateRecord(record *config.Record) {
+	nsone.addUpdateRecord(record.Domain, record.Type, record.Value)
+}
 
-		existingRecord, err := nsone.getRecord(domain, recordType)
-		if err != nil {
-			util.Log("查询域名信息发生异常! %s", err)
-			domain.UpdateStatus = config.UpdatedFailed
-			continue
-		}
+// addUpdateRecord 添加或更新单条记录
+func (nsone *NSOne) addUpdateRecord(domain *config.Domain, recordType string, value string) {
+	zoneInfo, err := nsone.getZone(domain)
+	if err != nil {
+		util.LogAttrs(slog.LevelError, domain.LogAttrs("query"), "查询域名信息发生异常! %s", err)
+		domain.UpdateStatus = config.UpdatedFailed
+		return
+	}
 
-		if existingRecord != nil {
-			nsone.updateRecord(domain, recordType, ipAddr, existingRecord)
-		} else {
-			nsone.createRecord(domain, recordType, ipAddr)
-		}
+	if zoneInfo == nil {
+		util.LogAttrs(slog.LevelError, domain.LogAttrs("query"), "在DNS服务商中未找到根域名: %s", domain.DomainName)
+		domain.UpdateStatus = config.UpdatedFailed
+		return
+	}
+
+	existingRecord, err := nsone.getRecord(domain, recordType)
+	if err != nil {
+		util.LogAttrs(slog.LevelError, domain.LogAttrs("query"), "查询域名信息发生异常! %s", err)
+		domain.UpdateStatus = config.UpdatedFailed
+		return
+	}
+
+	if existingRecord != nil {
+		nsone.updateRecord(domain, recordType, value, existingRecord)
+	} else {
+		nsone.createRecord(domain, recordType, value)
 	}
 }
 
@@ -298,19 +309,19 @@ func (nsone *NSOne) createRecord(domain *config.Domain, recordType string, ipAdd
 	)
 
 	if err != nil {
-		util.Log("新增域名解析 %s 失败! 异常信息: %s", domain, err)
+		util.LogAttrs(slog.LevelError, domain.LogAttrs("create"), "新增域名解析 %s 失败! 异常信息: %s", domain, err)
 		domain.UpdateStatus = config.UpdatedFailed
 		return
 	}
 
-	util.Log("新增域名解析 %s 成功! IP: %s", domain, ipAddr)
+	util.LogAttrs(slog.LevelInfo, domain.LogAttrs("create"), "新增域名解析 %s 成功! IP: %s", domain, ipAddr)
 	domain.UpdateStatus = config.UpdatedSuccess
 }
 
 func (nsone *NSOne) updateRecord(domain *config.Domain, recordType string, ipAddr string, existingRecord *NSOneRecordResponse) {
 	if len(existingRecord.Answers) > 0 && len(existingRecord.Answers[0].Answer) > 0 {
 		if existingRecord.Answers[0].Answer[0] == ipAddr {
-			util.Log("你的IP %s 没有变化, 域名 %s", ipAddr, domain)
+			util.LogAttrs(slog.LevelInfo, domain.LogAttrs("unchanged"), "你的IP %s 没有变化, 域名 %s", ipAddr, domain)
 			return
 		}
 	}
@@ -339,12 +350,12 @@ func (nsone *NSOne) updateRecord(domain *config.Domain, recordType string, ipAdd
 	)
 
 	if err != nil {
-		util.Log("更新域名解析 %s 失败! 异常信息: %s", domain, err)
+		util.LogAttrs(slog.LevelError, domain.LogAttrs("update"), "更新域名解析 %s 失败! 异常信息: %s", domain, err)
 		domain.UpdateStatus = config.UpdatedFailed
 		return
 	}
 
-	util.Log("更新域名解析 %s 成功! IP: %s", domain, ipAddr)
+	util.LogAttrs(slog.LevelInfo, domain.LogAttrs("update"), "更新域名解析 %s 成功! IP: %s", domain, ipAddr)
 	domain.UpdateStatus = config.UpdatedSuccess
 }
 
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"net/http"
 
 	"github.com/jeessy2/ddns-go/v6/config"
@@ -76,35 +77,48 @@ func (pb *Porkbun) addUpdateDomainRecords(recordType string) {
 	}
 
 	for _, domain := range domains {
-		var record PorkbunDomainQueryResponse
-		// 获取当前域名信息
-		err := pb.request(
-			porkbunEndpoint+fmt.Sprintf("/retrieveByNameType/%s/%s/%s", domain.DomainName, recordType, domain.SubDomain),
-			&PorkbunApiKey{
-				AccessKey: pb.DNSConfig.ID,
-				SecretKey: pb.DNSConfig.Secret,
-			},
-			&record,
-		)
-
-		if err != nil {
-			util.Log("查询域名信息发生异常! %s", err)
-			domain.UpdateStatus = config.UpdatedFailed
+		if !pb.addUpdateRecord(domain, recordType, ipAddr) {
 			return
 		}
-		if record.Status == "SUCCESS" {
-			if len(record.Records) > 0 {
-				// 存在，更新
-				pb.modify(&record, domain, recordType, ipAddr)
-			} else {
-				// 不存在，创建
-				pb.create(domain, recordType, ipAddr)
-			}
+	}
+}
+
+// AddUpdateRecord 添加或更新 A/AAAA 以外的记录
+func (pb *Porkbun) AddUpdateRecord(record *config.Record) {
+	pb.addUpdateRecord(record.Domain, record.Type, record.Value)
+}
+
+// addUpdateRecord 添加或更新单条记录, 查询域名信息失败时返回 false
+func (pb *Porkbun) addUpdateRecord(domain *config.Domain, recordType string, value string) bool {
+	var record PorkbunDomainQueryResponse
+	// 获取当前域名信息
+	err := pb.request(
+		porkbunEndpoint+fmt.Sprintf("/retrieveByNameType/%s/%s/%s", domain.DomainName, recordType, domain.SubDomain),
+		&PorkbunApiKey{
+			AccessKey: pb.DNSConfig.ID,
+			SecretKey: pb.DNSConfig.Secret,
+		},
+		&record,
+	)
+
+	if err != nil {
+		util.LogAttrs(slog.LevelError, domain.LogAttrs("query"), "查询域名信息发生异常! %s", err)
+		domain.UpdateStatus = config.UpdatedFailed
+		return false
+	}
+	if record.Status == "SUCCESS" {
+		if len(record.Records) > 0 {
+			// 存在，更新
+			pb.modify(&record, domain, recordType, value)
 		} else {
-			util.Log("在DNS服务商中未找到根域名: %s", domain.DomainName)
-			domain.UpdateStatus = config.UpdatedFailed
+			// 不存在，创建
+			pb.create(domain, recordType, value)
 		}
+	} else {
+		util.LogAttrs(slog.LevelError, domain.LogAttrs("query"), "在DNS服务商中未找到根域名: %s", domain.DomainName)
+		domain.UpdateStatus = config.UpdatedFailed
 	}
+	return true
 }
 
 // 创建
@@ -129,16 +143,16 @@ func (pb *Porkbun) create(domain *config.Domain, recordType string, ipAddr strin
 	)
 
 	if err != nil {
-		util.Log("新增域名解析 %s 失败! 异常信息: %s", domain, err)
+		util.LogAttrs(slog.LevelError, domain.LogAttrs("create"), "新增域名解析 %s 失败! 异常信息: %s", domain, err)
 		domain.UpdateStatus = config.UpdatedFailed
 		return
 	}
 
 	if response.Status == "SUCCESS" {
-		util.Log("新增域名解析 %s 成功! IP: %s", domain, ipAddr)
+		util.LogAttrs(slog.LevelInfo, domain.LogAttrs("create"), "新增域名解析 %s 成功! IP: %s", domain, ipAddr)
 		domain.UpdateStatus = config.UpdatedSuccess
 	} else {
-		util.Log("新增域名解析 %s 失败! 异常信息: %s", domain, response.Status)
+		util.LogAttrs(slog.LevelError, domain.LogAttrs("create"), "新增域名解析 %s 失败! 异常信息: %s", domain, response.Status)
 		domain.UpdateStatus = config.UpdatedFailed
 	}
 }
@@ -148,7 +162,7 @@ func (pb *Porkbun) modify(record *PorkbunDomainQueryResponse, domain *config.Dom
 
 	// 相同不修改
 	if len(record.Records) > 0 && *record.Records[0].Content == ipAddr {
-		util.Log("你的IP %s 没有变化, 域名 %s", ipAddr, domain)
+		util.LogAttrs(slog.LevelInfo, domain.LogAttrs("unchanged"), "你的IP %s 没有变化, 域名 %s", ipAddr, domain)
 		return
 	}
 
@@ -170,16 +184,16 @@ func (pb *Porkbun) modify(record *PorkbunDomainQueryResponse, domain *config.Dom
 	)
 
 	if err != nil {
-		util.Log("更新域名解析 %s 失败! 异常信息: %s", domain, err)
+		util.LogAttrs(slog.LevelError, domain.LogAttrs("update"), "更新域名解析 %s 失败! 异常信息: %s", domain, err)
 		domain.UpdateStatus = config.UpdatedFailed
 		return
 	}
 
 	if response.Status == "SUCCESS" {
-		util.Log("更新域名解析 %s 成功! IP: %s", domain, ipAddr)
+		util.LogAttrs(slog.LevelInfo, domain.LogAttrs("update"), "更新域名解析 %s 成功! IP: %s", domain, ipAddr)
 		domain.UpdateStatus = config.UpdatedSuccess
 	} else {
-		util.Log("更新域名解析 %s 失败! 异常信息: %s", domain, response.Status)
+		util.LogAttrs(slog.LevelError, domain.LogAttrs("update"), "更新域名解析 %s 失败! 异常信息: %s", domain, response.Status)
 		domain.UpdateStatus = config.UpdatedFailed
 	}
 }
@@ -0,0 +1,83 @@
+package dns
+
+import (
+	"slices"
+
+	"github.com/jeessy2/ddns-go/v6/config"
+	"github.com/jeessy2/ddns-go/v6/util"
+)
+
+// limitedRecordTypes 仅支持部分记录类型的DNS服务商, 未列出的 RecordUpdater 支持所有类型
+var limitedRecordTypes = map[string][]string{
+	"huaweicloud": {"AAAA", "TXT", "SRV"},
+	"baiducloud":  {"AAAA", "TXT"},
+	"porkbun":     {"AAAA", "TXT"},
+	"godaddy":     {"AAAA", "TXT"},
+	"gcore":       {"AAAA", "TXT"},
+	"dnsla":       {"AAAA", "TXT"},
+	"nsone":       {"AAAA", "TXT"},
+	"name_com":    {"AAAA", "TXT"},
+	"vercel":      {"AAAA", "TXT"},
+	"dynv6":       {"AAAA", "TXT"},
+}
+
+// supportsRecord DNS服务商是否支持更新该类型的记录
+func supportsRecord(dnsSelected DNS, dnsName string, recordType string) (RecordUpdater, bool) {
+	updater, ok := dnsSelected.(RecordUpdater)
+	if types, limited := limitedRecordTypes[dnsName]; ok && limited {
+		ok = slices.Contains(types, recordType)
+	}
+	return updater, ok
+}
+
+// SupportsRecord 名称为 dnsName 的DNS服务商是否支持更新该类型的记录, 用于保存配置时校验
+func SupportsRecord(dnsName string, recordType string) bool {
+	_, ok := supportsRecord(newDNS(dnsName), dnsName, recordType)
+	return ok
+}
+
+// updateRecords 更新随IP变化的 A/AAAA 以外的记录, 返回本次更新的记录及是否有更新失败的
+func updateRecords(dnsSelected DNS, dnsConf *config.DnsConfig, domains *config.Domains) (updated []*config.Record, failed bool) {
+	unsupported := map[string]bool{}
+	for _, record := range domains.GetRecords(dnsConf) {
+		updater, ok := supportsRecord(dnsSelected, dnsConf.DNS.Name, record.Type)
+		if !ok {
+			if !unsupported[record.Type] {
+				unsupported[record.Type] = true
+				util.Log("DNS服务商 %s 不支持更新 %s 记录", dnsConf.DNS.Name, record.Type)
+			}
+			continue
+		}
+
+		// 生成记录值失败时不更新, 如获取 SRV 记录的端口失败
+		if record.Domain.UpdateStatus != config.UpdatedFailed {
+			updater.AddUpdateRecord(record)
+		}
+		updated = append(updated, record)
+		if record.Domain.UpdateStatus == config.UpdatedFailed {
+			failed = true
+		} else {
+			record.SetPublished(dnsConf.DNS.Name)
+		}
+	}
+	if failed {
+		domains.SetRecordsFailed()
+	}
+	return
+}
+
+// updateHeartbeat 本周期成功时写入心跳TXT记录, 返回写入的记录, 未写入时返回 nil
+func updateHeartbeat(dnsSelected DNS, dnsConf *config.DnsConfig, domains *config.Domains) *config.Record {
+	record := domains.GetHeartbeat(dnsConf)
+	if record == nil {
+		return nil
+	}
+
+	updater, ok := supportsRecord(dnsSelected, dnsConf.DNS.Name, record.Type)
+	if !ok {
+		util.Log("DNS服务商 %s 不支持更新 %s 记录", dnsConf.DNS.Name, record.Type)
+		return nil
+	}
+	updater.AddUpdateRecord(record)
+	return record
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -49,14 +50,14 @@ func (s *Spaceship) AddUpdateDomainRecords() (domains config.Domains) {
 		for _, domain := range domains {
 			hasUpdated, err := s.updateRecord(recordType, ip, domain)
 			if err != nil {
-				util.Log("更新域名解析 %s 失败! 异常信息: %s", domain, err)
+				util.LogAttrs(slog.LevelError, domain.LogAttrs("update"), "更新域名解析 %s 失败! 异常信息: %s", domain, err)
 				domain.UpdateStatus = config.UpdatedFailed
 				continue
 			}
 			if !hasUpdated {
-				util.Log("你的IP %s 没有变化, 域名 %s", ip, domain)
+				util.LogAttrs(slog.LevelInfo, domain.LogAttrs("unchanged"), "你的IP %s 没有变化, 域名 %s", ip, domain)
 			} else {
-				util.Log("更新域名解析 %s 成功! IP: %s", domain, ip)
+				util.LogAttrs(slog.LevelInfo, domain.LogAttrs("update"), "更新域名解析 %s 成功! IP: %s", domain, ip)
 				domain.UpdateStatus = config.UpdatedSuccess
 			}
 		}
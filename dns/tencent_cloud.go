@@ -3,6 +3,7 @@ package dns
 import (
 	"bytes"
 	"encoding/json"
+	"log/slog"
 	"net/http"
 	"strconv"
 
@@ -98,32 +99,45 @@ func (tc *TencentCloud) addUpdateDomainRecords(recordType string) {
 	}
 
 	for _, domain := range domains {
-		result, err := tc.getRecordList(domain, recordType)
-		if err != nil {
-			util.Log("查询域名信息发生异常! %s", err)
-			domain.UpdateStatus = config.UpdatedFailed
+		if !tc.addUpdateRecord(domain, recordType, ipAddr) {
 			return
 		}
+	}
+}
 
-		if result.Response.RecordCountInfo.TotalCount > 0 {
-			// 默认第一个
-			recordSelected := result.Response.RecordList[0]
-			params := domain.GetCustomParams()
-			if params.Has("RecordId") {
-				for i := 0; i < result.Response.RecordCountInfo.TotalCount; i++ {
-					if strconv.FormatInt(result.Response.RecordList[i].RecordId, 10) == params.Get("RecordId") {
-						recordSelected = result.Response.RecordList[i]
-					}
+// AddUpdateRecord 添加或更新 A/AAAA 以外的记录
+func (tc *TencentCloud) AddUpdateRecord(record *config.Record) {
+	tc.addUpdateRecord(record.Domain, record.Type, record.Value)
+}
+
+// addUpdateRecord 添加或更新单条记录, 查询域名信息失败时返回 false
+func (tc *TencentCloud) addUpdateRecord(domain *config.Domain, recordType string, value string) bool {
+	result, err := tc.getRecordList(domain, recordType)
+	if err != nil {
+		util.LogAttrs(slog.LevelError, domain.LogAttrs("query"), "查询域名信息发生异常! %s", err)
+		domain.UpdateStatus = config.UpdatedFailed
+		return false
+	}
+
+	if result.Response.RecordCountInfo.TotalCount > 0 {
+		// 默认第一个
+		recordSelected := result.Response.RecordList[0]
+		params := domain.GetCustomParams()
+		if params.Has("RecordId") {
+			for i := 0; i < result.Response.RecordCountInfo.TotalCount; i++ {
+				if strconv.FormatInt(result.Response.RecordList[i].RecordId, 10) == params.Get("RecordId") {
+					recordSelected = result.Response.RecordList[i]
 				}
 			}
-
-			// 修改记录
-			tc.modify(recordSelected, domain, recordType, ipAddr)
-		} else {
-			// 添加记录
-			tc.create(domain, recordType, ipAddr)
 		}
+
+		// 修改记录
+		tc.modify(recordSelected, domain, recordType, value)
+	} else {
+		// 添加记录
+		tc.create(domain, recordType, value)
 	}
+	return true
 }
 
 // create 添加记录
@@ -146,16 +160,16 @@ func (tc *TencentCloud) create(domain *config.Domain, recordType string, ipAddr
 	)
 
 	if err != nil {
-		util.Log("新增域名解析 %s 失败! 异常信息: %s", domain, err)
+		util.LogAttrs(slog.LevelError, domain.LogAttrs("create"), "新增域名解析 %s 失败! 异常信息: %s", domain, err)
 		domain.UpdateStatus = config.UpdatedFailed
 		return
 	}
 
 	if status.Response.Error.Code == "" {
-		util.Log("新增域名解析 %s 成功! IP: %s", domain, ipAddr)
+		util.LogAttrs(slog.LevelInfo, domain.LogAttrs("create"), "新增域名解析 %s 成功! IP: %s", domain, ipAddr)
 		domain.UpdateStatus = config.UpdatedSuccess
 	} else {
-		util.Log("新增域名解析 %s 失败! 异常信息: %s", domain, status.Response.Error.Message)
+		util.LogAttrs(slog.LevelError, domain.LogAttrs("create"), "新增域名解析 %s 失败! 异常信息: %s", domain, status.Response.Error.Message)
 		domain.UpdateStatus = config.UpdatedFailed
 	}
 }
@@ -165,7 +179,7 @@ func (tc *TencentCloud) create(domain *config.Domain, recordType string, ipAddr
 func (tc *TencentCloud) modify(record TencentCloudRecord, domain *config.Domain, recordType string, ipAddr string) {
 	// 相同不修改
 	if record.Value == ipAddr {
-		util.Log("你的IP %s 没有变化, 域名 %s", ipAddr, domain)
+		util.LogAttrs(slog.LevelInfo, domain.LogAttrs("unchanged"), "你的IP %s 没有变化, 域名 %s", ipAddr, domain)
 		return
 	}
 	var status TencentCloudStatus
@@ -182,16 +196,16 @@ func (tc *TencentCloud) modify(record TencentCloudRecord, domain *config.Domain,
 	)
 
 	if err != nil {
-		util.Log("更新域名解析 %s 失败! 异常信息: %s", domain, err)
+		util.LogAttrs(slog.LevelError, domain.LogAttrs("update"), "更新域名解析 %s 失败! 异常信息: %s", domain, err)
 		domain.UpdateStatus = config.UpdatedFailed
 		return
 	}
 
 	if status.Response.Error.Code == "" {
-		util.Log("更新域名解析 %s 成功! IP: %s", domain, ipAddr)
+		util.LogAttrs(slog.LevelInfo, domain.LogAttrs("update"), "更新域名解析 %s 成功! IP: %s", domain, ipAddr)
 		domain.UpdateStatus = config.UpdatedSuccess
 	} else {
-		util.Log("更新域名解析 %s 失败! 异常信息: %s", domain, status.Response.Error.Message)
+		util.LogAttrs(slog.LevelError, domain.LogAttrs("update"), "更新域名解析 %s 失败! 异常信息: %s", domain, status.Response.Error.Message)
 		domain.UpdateStatus = config.UpdatedFailed
 	}
 }
@@ -2,6 +2,7 @@ package dns
 
 import (
 	"encoding/json"
+	"log/slog"
 	"net/http"
 	"strconv"
 
@@ -102,38 +103,44 @@ func (tr *TrafficRoute) addUpdateDomainRecords(recordType string) {
 	}
 
 	for _, domain := range domains {
-		resp := TrafficRouteListZonesResp{}
-		tr.getZID(domain, &resp)
-		zoneID := resp.ZID
-
-		var recordResp TrafficRouteResp
-		tr.request(
-			"GET",
-			"ListRecords",
-			map[string][]string{
-				"ZID":        {strconv.Itoa(zoneID)},
-				"Type":       {recordType},
-				"Host":       {domain.GetSubDomain()},
-				"SearchMode": {"exact"},
-				"PageNumber": {"1"},
-				"PageSize":   {"500"},
-			},
-			&recordResp,
-		)
-
-		found := false
-		for _, record := range recordResp.Result.Records {
-			if record.Type == recordType && record.Host == domain.GetSubDomain() {
-				tr.modify(record, domain, ipAddr)
-				found = true
-				break
-			}
-		}
+		tr.addUpdateRecord(domain, recordType, ipAddr)
+	}
+}
+
+// AddUpdateRecord 添加或更新 A/AAAA 以外的记录
+func (tr *TrafficRoute) AddUpdateRecord(record *config.Record) {
+	tr.addUpdateRecord(record.Domain, record.Type, record.Value)
+}
 
-		if !found {
-			tr.create(zoneID, domain, recordType, ipAddr)
+// addUpdateRecord 添加或更新单条记录
+func (tr *TrafficRoute) addUpdateRecord(domain *config.Domain, recordType string, value string) {
+	resp := TrafficRouteListZonesResp{}
+	tr.getZID(domain, &resp)
+	zoneID := resp.ZID
+
+	var recordResp TrafficRouteResp
+	tr.request(
+		"GET",
+		"ListRecords",
+		map[string][]string{
+			"ZID":        {strconv.Itoa(zoneID)},
+			"Type":       {recordType},
+			"Host":       {domain.GetSubDomain()},
+			"SearchMode": {"exact"},
+			"PageNumber": {"1"},
+			"PageSize":   {"500"},
+		},
+		&recordResp,
+	)
+
+	for _, record := range recordResp.Result.Records {
+		if record.Type == recordType && record.Host == domain.GetSubDomain() {
+			tr.modify(record, domain, value)
+			return
 		}
 	}
+
+	tr.create(zoneID, domain, recordType, value)
 }
 
 // getZID 获取域名的ZID
@@ -147,13 +154,13 @@ func (tr *TrafficRoute) getZID(domain *config.Domain, resp *TrafficRouteListZone
 	)
 
 	if err != nil {
-		util.Log("查询域名信息发生异常! %s", err)
+		util.LogAttrs(slog.LevelError, domain.LogAttrs("query"), "查询域名信息发生异常! %s", err)
 		domain.UpdateStatus = config.UpdatedFailed
 		return
 	}
 
 	if len(result.Result.Zones) == 0 {
-		util.Log("在DNS服务商中未找到域名: %s", domain.DomainName)
+		util.LogAttrs(slog.LevelError, domain.LogAttrs("query"), "在DNS服务商中未找到域名: %s", domain.DomainName)
 		domain.UpdateStatus = config.UpdatedFailed
 		return
 	}
@@ -186,16 +193,16 @@ func (tr *TrafficRoute) create(zoneID int, domain *config.Domain, recordType, ip
 	)
 
 	if err != nil {
-		util.Log("新增域名解析 %s 失败! 异常信息: %s", domain, err)
+		util.LogAttrs(slog.LevelError, domain.LogAttrs("create"), "新增域名解析 %s 失败! 异常信息: %s", domain, err)
 		domain.UpdateStatus = config.UpdatedFailed
 		return
 	}
 
 	if result.ResponseMetadata.Error.Code == "" {
-		util.Log("新增域名解析 %s 成功! IP: %s", domain, ipAddr)
+		util.LogAttrs(slog.LevelInfo, domain.LogAttrs("create"), "新增域名解析 %s 成功! IP: %s", domain, ipAddr)
 		domain.UpdateStatus = config.UpdatedSuccess
 	} else {
-		util.Log("新增域名解析 %s 失败! 异常信息: %s", domain, result.ResponseMetadata.Error.Message)
+		util.LogAttrs(slog.LevelError, domain.LogAttrs("create"), "新增域名解析 %s 失败! 异常信息: %s", domain, result.ResponseMetadata.Error.Message)
 		domain.UpdateStatus = config.UpdatedFailed
 	}
 }
@@ -203,7 +210,7 @@ func (tr *TrafficRoute) create(zoneID int, domain *config.Domain, recordType, ip
 // modify 修改解析记录
 func (tr *TrafficRoute) modify(record TrafficRouteMeta, domain *config.Domain, ipAddr string) {
 	if record.Value == ipAddr {
-		util.Log("IP %s 没有变化，域名 %s", ipAddr, domain)
+		util.LogAttrs(slog.LevelInfo, domain.LogAttrs("unchanged"), "IP %s 没有变化，域名 %s", ipAddr, domain)
 		domain.UpdateStatus = config.UpdatedNothing
 		return
 	}
@@ -220,16 +227,16 @@ func (tr *TrafficRoute) modify(record TrafficRouteMeta, domain *config.Domain, i
 	)
 
 	if err != nil {
-		util.Log("更新域名解析 %s 失败! 异常信息: %s", domain, err)
+		util.LogAttrs(slog.LevelError, domain.LogAttrs("update"), "更新域名解析 %s 失败! 异常信息: %s", domain, err)
 		domain.UpdateStatus = config.UpdatedFailed
 		return
 	}
 
 	if result.ResponseMetadata.Error.Code == "" {
-		util.Log("更新域名解析 %s 成功! IP: %s", domain, ipAddr)
+		util.LogAttrs(slog.LevelInfo, domain.LogAttrs("update"), "更新域名解析 %s 成功! IP: %s", domain, ipAddr)
 		domain.UpdateStatus = config.UpdatedSuccess
 	} else {
-		util.Log("更新域名解析 %s 失败! 异常信息: %s", domain, result.ResponseMetadata.Error.Message)
+		util.LogAttrs(slog.LevelError, domain.LogAttrs("update"), "更新域名解析 %s 失败! 异常信息: %s", domain, result.ResponseMetadata.Error.Message)
 		domain.UpdateStatus = config.UpdatedFailed
 	}
 }
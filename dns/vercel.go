@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"strconv"
 	"strings"
@@ -69,51 +70,61 @@ func (v *Vercel) addUpdateDomainRecords(recordType string) {
 		return
 	}
 
-	ipAddr = strings.ToLower(ipAddr)
-
-	var (
-		records []Record
-		err     error
-	)
 	for _, domain := range domains {
-		records, err = v.listExistingRecords(domain)
-		if err != nil {
-			util.Log("查询域名信息发生异常! %s", err)
-			continue
-		}
+		v.addUpdateRecord(domain, recordType, ipAddr)
+	}
+}
 
-		var targetRecord *Record
-		for _, record := range records {
-			if record.Name == domain.SubDomain {
-				targetRecord = &record
-				break
-			}
-		}
+// AddUpdateRecord 添加或更新 A/AAAA 以外的记录
+func (v *Vercel) AddUpdateRecord(record *config.Record) {
+	v.addUpdateRecord(record.Domain, record.Type, record.Value)
+}
 
-		if targetRecord == nil {
-			err = v.createRecord(domain, recordType, ipAddr)
-		} else {
-			if strings.ToLower(targetRecord.Value) == ipAddr {
-				util.Log("你的IP %s 没有变化, 域名 %s", ipAddr, domain)
-				domain.UpdateStatus = config.UpdatedNothing
-				continue
-			} else {
-				err = v.updateRecord(targetRecord, recordType, ipAddr)
-			}
-		}
+// addUpdateRecord 添加或更新单条记录
+func (v *Vercel) addUpdateRecord(domain *config.Domain, recordType string, value string) {
+	// IPv6地址不区分大小写
+	isAddr := recordType == "A" || recordType == "AAAA"
+	if isAddr {
+		value = strings.ToLower(value)
+	}
 
-		operation := "新增"
-		if targetRecord != nil {
-			operation = "更新"
+	records, err := v.listExistingRecords(domain)
+	if err != nil {
+		util.LogAttrs(slog.LevelError, domain.LogAttrs("query"), "查询域名信息发生异常! %s", err)
+		return
+	}
+
+	var targetRecord *Record
+	for _, record := range records {
+		if record.Name == domain.SubDomain && record.Type == recordType {
+			targetRecord = &record
+			break
 		}
-		if err == nil {
-			util.Log(operation+"域名解析 %s 成功! IP: %s", domain, ipAddr)
-			domain.UpdateStatus = config.UpdatedSuccess
+	}
+
+	if targetRecord == nil {
+		err = v.createRecord(domain, recordType, value)
+	} else {
+		if targetRecord.Value == value || isAddr && strings.ToLower(targetRecord.Value) == value {
+			util.LogAttrs(slog.LevelInfo, domain.LogAttrs("unchanged"), "你的IP %s 没有变化, 域名 %s", value, domain)
+			domain.UpdateStatus = config.UpdatedNothing
+			return
 		} else {
-			util.Log(operation+"域名解析 %s 失败! 异常信息: %s", domain, err)
-			domain.UpdateStatus = config.UpdatedFailed
+			err = v.updateRecord(targetRecord, recordType, value)
 		}
 	}
+
+	operation := "新增"
+	if targetRecord != nil {
+		operation = "更新"
+	}
+	if err == nil {
+		util.Log(operation+"域名解析 %s 成功! IP: %s", domain, value)
+		domain.UpdateStatus = config.UpdatedSuccess
+	} else {
+		util.Log(operation+"域名解析 %s 失败! 异常信息: %s", domain, err)
+		domain.UpdateStatus = config.UpdatedFailed
+	}
 }
 
 func (v *Vercel) listExistingRecords(domain *config.Domain) (records []Record, err error) {
@@ -0,0 +1,218 @@
+package dns
+
+import (
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jeessy2/ddns-go/v6/config"
+	"github.com/jeessy2/ddns-go/v6/util"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// 默认校验超时时间(秒)
+const defaultVerifyTimeout = 120
+
+// 校验间隔
+const verifyInterval = 5 * time.Second
+
+// runInterval 定时更新的间隔, 校验超时时间不超过其 1/5, 避免阻塞下次更新
+var runInterval time.Duration
+
+// verifyTarget 需要在权威DNS服务器上校验的一条记录
+type verifyTarget struct {
+	domain *config.Domain
+	qtype  dnsmessage.Type
+	// values 期望的记录值, 已统一格式
+	values []string
+}
+
+// verifyDomains 更新成功后, 向域名的权威DNS服务器查询, 直到所有服务器都返回新的记录或超时。
+// 所有域名及其它记录同时校验, 超时的将被标记为 UpdatedUnverified
+func verifyDomains(dnsConf *config.DnsConfig, domains *config.Domains, records []*config.Record) {
+	if !dnsConf.Verify || dnsConf.DNS.Name == "callback" {
+		return
+	}
+
+	var targets []verifyTarget
+	targets = appendDomainTargets(targets, domains.Ipv4Domains, "A", domains)
+	targets = appendDomainTargets(targets, domains.Ipv6Domains, "AAAA", domains)
+	for _, record := range records {
+		qtype, ok := recordQTypes[record.Type]
+		if !ok || record.Domain.UpdateStatus != config.UpdatedSuccess {
+			continue
+		}
+		targets = append(targets, verifyTarget{record.Domain, qtype, []string{normalizeValue(qtype, record.Value)}})
+	}
+	if len(targets) == 0 {
+		return
+	}
+
+	deadline := time.Now().Add(verifyTimeout(dnsConf))
+	// 同一根域名只查询一次权威DNS服务器
+	nameServers := map[string][]string{}
+	var wg sync.WaitGroup
+	for _, target := range targets {
+		zone := target.domain.DomainName
+		servers, ok := nameServers[zone]
+		if !ok {
+			var err error
+			servers, err = util.LookupNS(zone)
+			if err != nil || len(servers) == 0 {
+				util.Log("查询域名 %s 的权威DNS服务器失败! 异常信息: %s", zone, err)
+			}
+			nameServers[zone] = servers
+		}
+		if len(servers) == 0 {
+			target.domain.UpdateStatus = config.UpdatedUnverified
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if waitNameServers(servers, target.domain.ToASCII(), target.qtype, target.values, deadline) {
+				util.Log("域名 %s 已在权威DNS服务器生效", target.domain)
+			} else {
+				util.Log("域名 %s 在权威DNS服务器中未生效, 未返回新的记录: %s", target.domain, strings.Join(target.values, ", "))
+				target.domain.UpdateStatus = config.UpdatedUnverified
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// recordQTypes 可校验的其它记录类型
+var recordQTypes = map[string]dnsmessage.Type{
+	"A":     dnsmessage.TypeA,
+	"AAAA":  dnsmessage.TypeAAAA,
+	"TXT":   dnsmessage.TypeTXT,
+	"SRV":   dnsmessage.TypeSRV,
+	"SVCB":  dnsmessage.TypeSVCB,
+	"HTTPS": dnsmessage.TypeHTTPS,
+}
+
+// verifyTimeout 校验超时时间, 定时运行时不超过更新间隔的 1/5
+func verifyTimeout(dnsConf *config.DnsConfig) time.Duration {
+	seconds, err := strconv.Atoi(dnsConf.VerifyTimeout)
+	if err != nil || seconds <= 0 {
+		seconds = defaultVerifyTimeout
+	}
+	timeout := time.Duration(seconds) * time.Second
+	if limit := runInterval / 5; limit > 0 && timeout > limit {
+		timeout = limit
+	}
+	return timeout
+}
+
+// appendDomainTargets 添加本次更新成功的 A/AAAA 域名, 自定义了 IpAddrPool 的使用其中同类型的地址
+func appendDomainTargets(targets []verifyTarget, domainList []*config.Domain, recordType string, domains *config.Domains) []verifyTarget {
+	qtype := recordQTypes[recordType]
+	for _, domain := range domainList {
+		if domain.UpdateStatus != config.UpdatedSuccess {
+			continue
+		}
+		tuple := config.DomainTuple{RecordType: recordType, Primary: domain, Ipv4Addr: domains.Ipv4Addr, Ipv6Addr: domains.Ipv6Addr}
+		var values []string
+		for _, addr := range strings.Split(tuple.GetIpAddrPool(","), ",") {
+			ip := net.ParseIP(strings.TrimSpace(addr))
+			if ip != nil && (ip.To4() != nil) == (qtype == dnsmessage.TypeA) {
+				values = append(values, ip.String())
+			}
+		}
+		if len(values) > 0 {
+			targets = append(targets, verifyTarget{domain, qtype, values})
+		}
+	}
+	return targets
+}
+
+// waitNameServers 轮询所有权威DNS服务器, 直到都返回全部的 values 或超时
+func waitNameServers(nameServers []string, name string, qtype dnsmessage.Type, values []string, deadline time.Time) bool {
+	pending := nameServers
+	for {
+		var next []string
+		for _, ns := range pending {
+			answers, err := util.QueryDNS("udp", ns, name, qtype)
+			if err != nil || !containsAll(answers, qtype, values) {
+				next = append(next, ns)
+			}
+		}
+		pending = next
+
+		if len(pending) == 0 {
+			return true
+		}
+		if time.Now().Add(verifyInterval).After(deadline) {
+			return false
+		}
+		time.Sleep(verifyInterval)
+	}
+}
+
+// containsAll 应答中是否包含所有期望的值
+func containsAll(answers []string, qtype dnsmessage.Type, values []string) bool {
+	for i, answer := range answers {
+		answers[i] = normalizeValue(qtype, answer)
+	}
+	for _, value := range values {
+		if !slices.Contains(answers, value) {
+			return false
+		}
+	}
+	return true
+}
+
+// normalizeValue 统一记录值的格式, 用于比较配置的值与 util.QueryDNS 的应答。
+// 域名去掉末尾的点, HTTPS/SVCB 的参数排序且只比较 util.QueryDNS 能转换的参数
+func normalizeValue(qtype dnsmessage.Type, value string) string {
+	switch qtype {
+	case dnsmessage.TypeA, dnsmessage.TypeAAAA:
+		if ip := net.ParseIP(value); ip != nil {
+			return ip.String()
+		}
+	case dnsmessage.TypeSRV:
+		fields := strings.Fields(value)
+		if len(fields) == 4 {
+			fields[3] = trimDot(fields[3])
+		}
+		return strings.Join(fields, " ")
+	case dnsmessage.TypeSVCB, dnsmessage.TypeHTTPS:
+		fields := strings.Fields(value)
+		if len(fields) < 2 {
+			return value
+		}
+		parts := []string{fields[0], trimDot(fields[1])}
+		var params []string
+		for _, param := range fields[2:] {
+			key, val, _ := strings.Cut(param, "=")
+			switch key {
+			case "alpn", "no-default-alpn", "port":
+				params = append(params, param)
+			case "ipv4hint", "ipv6hint":
+				var ips []string
+				for _, addr := range strings.Split(val, ",") {
+					if ip := net.ParseIP(addr); ip != nil {
+						addr = ip.String()
+					}
+					ips = append(ips, addr)
+				}
+				params = append(params, key+"="+strings.Join(ips, ","))
+			}
+		}
+		slices.Sort(params)
+		return strings.Join(append(parts, params...), " ")
+	}
+	return value
+}
+
+// trimDot 去掉域名末尾的点, 根域名为 "."
+func trimDot(name string) string {
+	if trimmed := strings.TrimSuffix(name, "."); trimmed != "" {
+		return trimmed
+	}
+	return "."
+}
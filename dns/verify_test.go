@@ -0,0 +1,94 @@
+package dns
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jeessy2/ddns-go/v6/config"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// TestNormalizeValue 测试记录值统一格式
+func TestNormalizeValue(t *testing.T) {
+	tests := []struct {
+		qtype dnsmessage.Type
+		value string
+		want  string
+	}{
+		{dnsmessage.TypeA, "1.2.3.4", "1.2.3.4"},
+		{dnsmessage.TypeAAAA, "2001:0db8:0000::0001", "2001:db8::1"},
+		{dnsmessage.TypeSRV, "10  5 443 example.com.", "10 5 443 example.com"},
+		{dnsmessage.TypeHTTPS, "1 . port=443 alpn=h2", "1 . alpn=h2 port=443"},
+		{dnsmessage.TypeSVCB, "1 svc.example.com. ipv6hint=2001:db8:0::1 ech=abc", "1 svc.example.com ipv6hint=2001:db8::1"},
+		{dnsmessage.TypeHTTPS, "1", "1"},
+		{dnsmessage.TypeTXT, "hello world", "hello world"},
+	}
+	for _, tt := range tests {
+		if got := normalizeValue(tt.qtype, tt.value); got != tt.want {
+			t.Errorf("%s: 期待 %q, 得到 %q", tt.value, tt.want, got)
+		}
+	}
+}
+
+// TestContainsAll 测试应答中是否包含所有期望的值
+func TestContainsAll(t *testing.T) {
+	answers := []string{"2001:0db8::0001", "2001:db8::2"}
+	if !containsAll(answers, dnsmessage.TypeAAAA, []string{"2001:db8::1", "2001:db8::2"}) {
+		t.Error("期待包含所有的值")
+	}
+	if containsAll(answers, dnsmessage.TypeAAAA, []string{"2001:db8::1", "2001:db8::3"}) {
+		t.Error("期待不包含 2001:db8::3")
+	}
+	if containsAll(nil, dnsmessage.TypeA, []string{"1.2.3.4"}) {
+		t.Error("空应答期待不包含")
+	}
+}
+
+// TestAppendDomainTargets 测试只添加更新成功的域名, 并使用 IpAddrPool 中同类型的地址
+func TestAppendDomainTargets(t *testing.T) {
+	domains := &config.Domains{Ipv4Addr: "1.2.3.4", Ipv6Addr: "2001:db8::1"}
+	success := &config.Domain{DomainName: "example.com", SubDomain: "a", UpdateStatus: config.UpdatedSuccess}
+	failed := &config.Domain{DomainName: "example.com", SubDomain: "b", UpdateStatus: config.UpdatedFailed}
+	pool := &config.Domain{DomainName: "example.com", SubDomain: "c", UpdateStatus: config.UpdatedSuccess,
+		CustomParams: "IpAddrPool={ipv6Addr},2001:db8::2,5.6.7.8"}
+
+	targets := appendDomainTargets(nil, []*config.Domain{success, failed, pool}, "AAAA", domains)
+	if len(targets) != 2 {
+		t.Fatalf("期待 2 个校验目标, 得到 %d", len(targets))
+	}
+	if targets[0].domain != success || len(targets[0].values) != 1 || targets[0].values[0] != "2001:db8::1" {
+		t.Errorf("期待 %s 校验 2001:db8::1, 得到 %v", success, targets[0].values)
+	}
+	if targets[1].domain != pool || len(targets[1].values) != 2 || targets[1].values[1] != "2001:db8::2" {
+		t.Errorf("期待 %s 校验 [2001:db8::1 2001:db8::2], 得到 %v", pool, targets[1].values)
+	}
+	for _, target := range targets {
+		if target.qtype != dnsmessage.TypeAAAA {
+			t.Errorf("期待 AAAA, 得到 %s", target.qtype)
+		}
+	}
+}
+
+// TestVerifyTimeout 测试校验超时时间不超过更新间隔的 1/5
+func TestVerifyTimeout(t *testing.T) {
+	defer func(interval time.Duration) { runInterval = interval }(runInterval)
+
+	tests := []struct {
+		interval time.Duration
+		timeout  string
+		want     time.Duration
+	}{
+		{0, "", defaultVerifyTimeout * time.Second},
+		{0, "30", 30 * time.Second},
+		{0, "abc", defaultVerifyTimeout * time.Second},
+		{5 * time.Minute, "", time.Minute},
+		{5 * time.Minute, "30", 30 * time.Second},
+		{time.Hour, "600", 600 * time.Second},
+	}
+	for _, tt := range tests {
+		runInterval = tt.interval
+		if got := verifyTimeout(&config.DnsConfig{VerifyTimeout: tt.timeout}); got != tt.want {
+			t.Errorf("间隔 %s, 超时 %q: 期待 %s, 得到 %s", tt.interval, tt.timeout, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package dns
+
+import (
+	"sync"
+	"time"
+
+	"github.com/jeessy2/ddns-go/v6/config"
+	"github.com/jeessy2/ddns-go/v6/util"
+)
+
+// watchDebounce 网卡地址变化后等待的时间, 合并 PPPoE 重拨等产生的多个事件
+const watchDebounce = 3 * time.Second
+
+// addrWatcher 网卡地址变化时, 延迟运行使用该网卡的配置
+type addrWatcher struct {
+	mu     sync.Mutex
+	timers map[int]*time.Timer
+	// debounce 等待的时间, run 运行第 i 个配置, 测试时替换
+	debounce time.Duration
+	run      func(i int)
+}
+
+// newAddrWatcher 创建 addrWatcher
+func newAddrWatcher() *addrWatcher {
+	return &addrWatcher{timers: map[int]*time.Timer{}, debounce: watchDebounce, run: RunOnceFor}
+}
+
+// addrChanged 网卡 ifName 的IPv4/IPv6地址发生变化
+func (w *addrWatcher) addrChanged(ifName string, ipv6 bool) {
+	conf, err := config.GetConfigCached()
+	if err != nil {
+		return
+	}
+	w.trigger(conf.DnsConf, ifName, ipv6)
+}
+
+// trigger 等待 debounce 后运行使用该网卡的配置, 等待期间再次变化时重新计时
+func (w *addrWatcher) trigger(dnsConf []config.DnsConfig, ifName string, ipv6 bool) {
+	addrType := "IPv4"
+	if ipv6 {
+		addrType = "IPv6"
+	}
+
+	for i, dc := range dnsConf {
+		if !dc.UsesNetInterface(addrType, ifName) {
+			continue
+		}
+
+		w.mu.Lock()
+		if timer, ok := w.timers[i]; ok {
+			timer.Reset(w.debounce)
+		} else {
+			w.timers[i] = time.AfterFunc(w.debounce, func() {
+				w.mu.Lock()
+				delete(w.timers, i)
+				w.mu.Unlock()
+
+				util.Log("网卡 %s 的地址发生变化, 立即更新", ifName)
+				w.run(i)
+			})
+		}
+		w.mu.Unlock()
+	}
+}
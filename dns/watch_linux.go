@@ -0,0 +1,72 @@
+//go:build linux
+
+package dns
+
+import (
+	"encoding/binary"
+	"net"
+	"syscall"
+
+	"github.com/jeessy2/ddns-go/v6/util"
+)
+
+// rtnetlink 组播组, 见 linux/rtnetlink.h
+const (
+	rtmgrpIPv4IfAddr = 0x10
+	rtmgrpIPv6IfAddr = 0x100
+)
+
+// Watch 通过 rtnetlink 监听网卡地址的变化(RTM_NEWADDR/RTM_DELADDR), 立即更新使用该网卡的配置。
+// 定时更新仍会运行
+func Watch() {
+	fd, err := syscall.Socket(syscall.AF_NETLINK, syscall.SOCK_RAW|syscall.SOCK_CLOEXEC, syscall.NETLINK_ROUTE)
+	if err != nil {
+		util.Log("监听网卡地址变化失败! 异常信息: %s", err)
+		return
+	}
+	defer syscall.Close(fd)
+
+	sa := &syscall.SockaddrNetlink{
+		Family: syscall.AF_NETLINK,
+		Groups: rtmgrpIPv4IfAddr | rtmgrpIPv6IfAddr,
+	}
+	if err = syscall.Bind(fd, sa); err != nil {
+		util.Log("监听网卡地址变化失败! 异常信息: %s", err)
+		return
+	}
+	util.Log("开始监听网卡地址的变化")
+
+	w := newAddrWatcher()
+	buf := make([]byte, 65536)
+	for {
+		n, _, err := syscall.Recvfrom(fd, buf, 0)
+		if err != nil {
+			if err == syscall.EINTR || err == syscall.ENOBUFS {
+				continue
+			}
+			util.Log("监听网卡地址变化失败! 异常信息: %s", err)
+			return
+		}
+		msgs, err := syscall.ParseNetlinkMessage(buf[:n])
+		if err != nil {
+			continue
+		}
+
+		for _, msg := range msgs {
+			if msg.Header.Type != syscall.RTM_NEWADDR && msg.Header.Type != syscall.RTM_DELADDR {
+				continue
+			}
+			if len(msg.Data) < syscall.SizeofIfAddrmsg {
+				continue
+			}
+			// struct ifaddrmsg: family, prefixlen, flags, scope, index
+			family := msg.Data[0]
+			index := binary.NativeEndian.Uint32(msg.Data[4:8])
+			iface, err := net.InterfaceByIndex(int(index))
+			if err != nil {
+				continue
+			}
+			w.addrChanged(iface.Name, family == syscall.AF_INET6)
+		}
+	}
+}
@@ -0,0 +1,10 @@
+//go:build !linux
+
+package dns
+
+import "github.com/jeessy2/ddns-go/v6/util"
+
+// Watch 仅 Linux 支持监听网卡地址的变化
+func Watch() {
+	util.Log("仅 Linux 支持监听网卡地址的变化")
+}
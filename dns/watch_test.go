@@ -0,0 +1,55 @@
+package dns
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jeessy2/ddns-go/v6/config"
+)
+
+// TestAddrWatcherTrigger 测试多次变化只运行一次, 且只运行使用该网卡(包括备用来源)的配置
+func TestAddrWatcherTrigger(t *testing.T) {
+	var mu sync.Mutex
+	runs := map[int]int{}
+	w := newAddrWatcher()
+	w.debounce = 50 * time.Millisecond
+	w.run = func(i int) {
+		mu.Lock()
+		runs[i]++
+		mu.Unlock()
+	}
+
+	dnsConf := make([]config.DnsConfig, 4)
+	// 0: 获取方式为网卡
+	dnsConf[0].Ipv6.Enable = true
+	dnsConf[0].Ipv6.GetType = "netInterface"
+	dnsConf[0].Ipv6.NetInterface = "ppp0"
+	// 1: 备用来源为网卡
+	dnsConf[1].Ipv6.Enable = true
+	dnsConf[1].Ipv6.GetType = "url"
+	dnsConf[1].Ipv6.Fallback = []string{"cmd:5 ip -6 addr", "netInterface ppp0"}
+	// 2: 其它网卡
+	dnsConf[2].Ipv6.Enable = true
+	dnsConf[2].Ipv6.GetType = "netInterface"
+	dnsConf[2].Ipv6.NetInterface = "eth0"
+	// 3: 未启用IPv6
+	dnsConf[3].Ipv6.GetType = "netInterface"
+	dnsConf[3].Ipv6.NetInterface = "ppp0"
+
+	// PPPoE 重拨时的多个事件
+	for range 3 {
+		w.trigger(dnsConf, "ppp0", true)
+		time.Sleep(10 * time.Millisecond)
+	}
+	// IPv4 的变化不影响只启用IPv6的配置
+	w.trigger(dnsConf, "eth0", false)
+
+	time.Sleep(200 * time.Millisecond)
+	mu.Lock()
+	defer mu.Unlock()
+	expected := map[int]int{0: 1, 1: 1}
+	if len(runs) != len(expected) || runs[0] != 1 || runs[1] != 1 {
+		t.Errorf("期待运行 %v, 得到 %v", expected, runs)
+	}
+}
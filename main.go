@@ -58,9 +58,27 @@ var customDNS = flag.String("dns", "", "Custom DNS server address, example: 8.8.
 // 重置密码
 var newPassword = flag.String("resetPassword", "", "Reset password to the one entered")
 
+// 监听网卡地址的变化
+var watchFlag = flag.Bool("watch", false, "Update immediately when the address of the network interface changes (Linux only)")
+
 // 后台运行
 var daemonize = flag.Bool("d", false, "Run in background (daemon/detached)")
 
+// 发送日志到 syslog 服务器
+var syslogAddr = flag.String("syslog", "", "Send logs to a syslog server in RFC 5424 format, example: udp://192.168.1.2:514, tcp://192.168.1.2:514, unix:///dev/log")
+
+// 发送日志到 journald
+var journald = flag.Bool("journald", false, "Send logs to journald with structured fields (Linux only)")
+
+// JSON格式的日志文件
+var logFile = flag.String("logFile", "", "Write logs as JSON lines to the file")
+
+// JSON日志文件的最大大小
+var logFileSize = flag.Int("logFileSize", 10, "Rotate the JSON log file when it exceeds the size (MB), keeping 3 old files")
+
+// 日志级别
+var logLevel = flag.String("log-level", "info", "Log level (debug|info|warn|error), debug also prints sanitized HTTP requests and responses")
+
 //go:embed static
 var staticEmbeddedFiles embed.FS
 
@@ -96,6 +114,10 @@ func main() {
 	if _, err := net.ResolveTCPAddr("tcp", *listen); err != nil {
 		log.Fatalf("Parse listen address failed! Exception: %s", err)
 	}
+	// 设置日志级别
+	if err := util.SetLogLevel(*logLevel); err != nil {
+		log.Fatalf("Parse log level failed! Exception: %s", err)
+	}
 	// 设置版本号
 	os.Setenv(web.VersionEnv, version)
 	// 设置配置文件路径
@@ -158,6 +180,8 @@ func run() {
 	conf.CompatibleConfig()
 	// 初始化语言
 	util.InitLogLang(conf.Lang)
+	// 初始化日志输出
+	initLogSinks()
 
 	if !*noWebService {
 		go func() {
@@ -177,10 +201,40 @@ func run() {
 	// 等待网络连接
 	util.WaitInternet(dns.Addresses)
 
+	// 监听网卡地址的变化
+	if *watchFlag {
+		go dns.Watch()
+	}
+
 	// 定时运行
 	dns.RunTimer(time.Duration(*every) * time.Second)
 }
 
+// initLogSinks 根据参数添加 syslog/journald/JSON文件 日志输出
+func initLogSinks() {
+	if *syslogAddr != "" {
+		if sink, err := util.NewSyslogSink(*syslogAddr); err == nil {
+			util.AddLogSink(sink)
+		} else {
+			util.Log("日志输出初始化失败! 异常信息: %s", err)
+		}
+	}
+	if *journald {
+		if sink, err := util.NewJournaldSink(); err == nil {
+			util.AddLogSink(sink)
+		} else {
+			util.Log("日志输出初始化失败! 异常信息: %s", err)
+		}
+	}
+	if *logFile != "" {
+		if sink, err := util.NewJSONFileSink(*logFile, int64(*logFileSize)*1024*1024); err == nil {
+			util.AddLogSink(sink)
+		} else {
+			util.Log("日志输出初始化失败! 异常信息: %s", err)
+		}
+	}
+}
+
 func staticFsFunc(writer http.ResponseWriter, request *http.Request) {
 	http.FileServer(http.FS(staticEmbeddedFiles)).ServeHTTP(writer, request)
 }
@@ -201,6 +255,8 @@ func runWebServer() error {
 	http.HandleFunc("/logs", web.Auth(web.Logs))
 	http.HandleFunc("/clearLog", web.Auth(web.ClearLog))
 	http.HandleFunc("/webhookTest", web.Auth(web.WebhookTest))
+	http.HandleFunc("/webhookDeliveries", web.Auth(web.WebhookDeliveries))
+	http.HandleFunc("/history", web.Auth(web.History))
 	http.HandleFunc("/logout", web.Auth(web.Logout))
 
 	util.Log("监听 %s", *listen)
@@ -298,6 +354,27 @@ func getService() service.Service {
 		svcConfig.Arguments = append(svcConfig.Arguments, "-dns", *customDNS)
 	}
 
+	if *watchFlag {
+		svcConfig.Arguments = append(svcConfig.Arguments, "-watch")
+	}
+
+	if *syslogAddr != "" {
+		svcConfig.Arguments = append(svcConfig.Arguments, "-syslog", *syslogAddr)
+	}
+
+	if *journald {
+		svcConfig.Arguments = append(svcConfig.Arguments, "-journald")
+	}
+
+	if *logFile != "" {
+		logFilePath, _ := filepath.Abs(*logFile)
+		svcConfig.Arguments = append(svcConfig.Arguments, "-logFile", logFilePath, "-logFileSize", strconv.Itoa(*logFileSize))
+	}
+
+	if *logLevel != "info" {
+		svcConfig.Arguments = append(svcConfig.Arguments, "-log-level", *logLevel)
+	}
+
 	prg := &program{}
 	s, err := service.New(prg, svcConfig)
 	if err != nil {
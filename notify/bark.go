@@ -0,0 +1,37 @@
+package notify
+
+import "errors"
+
+// barkEndpoint Bark 官方服务器
+const barkEndpoint = "https://api.day.app"
+
+// Bark Token 为 Device Key, URL 可指定自建服务器
+type Bark struct {
+	Config
+}
+
+// Send 发送消息
+func (b *Bark) Send(title string, text string) error {
+	if err := required("Device Key", b.Token); err != nil {
+		return err
+	}
+	endpoint := b.URL
+	if endpoint == "" {
+		endpoint = barkEndpoint
+	}
+
+	var result struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	}
+	err := b.postJSON(endpoint+"/push", nil, map[string]string{
+		"device_key": b.Token,
+		"title":      title,
+		"body":       text,
+		"group":      "ddns-go",
+	}, &result)
+	if err == nil && result.Code != 200 {
+		err = errors.New(result.Message)
+	}
+	return err
+}
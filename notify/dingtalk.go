@@ -0,0 +1,54 @@
+package notify
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// DingTalk URL 为机器人的 Webhook 地址, Secret 为加签密钥
+type DingTalk struct {
+	Config
+}
+
+// dingTalkSign 钉钉加签: base64(HmacSHA256(timestamp+"\n"+secret, secret))
+func dingTalkSign(timestamp int64, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10) + "\n" + secret))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// Send 发送文本消息
+func (d *DingTalk) Send(title string, text string) error {
+	if err := required("URL", d.URL); err != nil {
+		return err
+	}
+	u, err := url.Parse(d.URL)
+	if err != nil {
+		return err
+	}
+	if d.Secret != "" {
+		timestamp := time.Now().UnixMilli()
+		q := u.Query()
+		q.Set("timestamp", strconv.FormatInt(timestamp, 10))
+		q.Set("sign", dingTalkSign(timestamp, d.Secret))
+		u.RawQuery = q.Encode()
+	}
+
+	var result struct {
+		ErrCode int    `json:"errcode"`
+		ErrMsg  string `json:"errmsg"`
+	}
+	err = d.postJSON(u.String(), nil, map[string]any{
+		"msgtype": "text",
+		"text":    map[string]string{"content": title + "\n" + text},
+	}, &result)
+	if err == nil && result.ErrCode != 0 {
+		err = errors.New(result.ErrMsg)
+	}
+	return err
+}
@@ -0,0 +1,25 @@
+package notify
+
+// discordMaxLength Discord 消息的最大长度
+const discordMaxLength = 2000
+
+// Discord URL 为 Webhook 地址
+type Discord struct {
+	Config
+}
+
+// Send 发送消息, 超出长度时截断
+func (d *Discord) Send(title string, text string) error {
+	if err := required("URL", d.URL); err != nil {
+		return err
+	}
+	content := []rune("**" + title + "**\n" + text)
+	if len(content) > discordMaxLength {
+		content = content[:discordMaxLength]
+	}
+	return d.postJSON(d.URL, nil, map[string]any{
+		"content": string(content),
+		// 不提及任何人
+		"allowed_mentions": map[string][]string{"parse": {}},
+	}, nil)
+}
@@ -0,0 +1,183 @@
+package notify
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/jeessy2/ddns-go/v6/util"
+)
+
+// emailTimeout 连接及发送邮件的默认超时时间
+const emailTimeout = 30 * time.Second
+
+// emailRootCAs 校验SMTP服务器证书的根证书, 为空则使用系统的根证书
+var emailRootCAs *x509.CertPool
+
+// Email URL 为SMTP服务器, 如 smtps://smtp.example.com:465 (TLS) 或 smtp://smtp.example.com:587 (STARTTLS),
+// 可通过参数 from 指定发件人, auth=login 使用 LOGIN 认证。
+// smtp:// 的服务器不支持STARTTLS时不发送, 除非指定参数 plaintext=true 明确允许不加密。
+// Token/Secret 为用户名/密码, Target 为收件人, 多个以逗号分割
+type Email struct {
+	Config
+}
+
+// Send 发送邮件, 内容以 < 开头时为HTML
+func (e *Email) Send(title string, text string) error {
+	if err := required("URL", e.URL, "To", e.Target); err != nil {
+		return err
+	}
+	u, err := url.Parse(e.URL)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "smtp" && u.Scheme != "smtps" {
+		return errors.New("the URL must start with smtp:// or smtps://")
+	}
+	host := u.Hostname()
+	addr := u.Host
+	if u.Port() == "" {
+		addr = net.JoinHostPort(host, map[string]string{"smtp": "587", "smtps": "465"}[u.Scheme])
+	}
+	from := u.Query().Get("from")
+	if from == "" {
+		from = e.Token
+	}
+	var to []string
+	for _, rcpt := range strings.Split(e.Target, ",") {
+		if rcpt = strings.TrimSpace(rcpt); rcpt != "" {
+			to = append(to, rcpt)
+		}
+	}
+	if from == "" || len(to) == 0 {
+		return errors.New("sender and recipients are required")
+	}
+
+	timeout := emailTimeout
+	if e.Timeout > 0 {
+		timeout = e.Timeout
+	}
+	tlsConfig := &tls.Config{ServerName: host, RootCAs: emailRootCAs, InsecureSkipVerify: util.IsInsecureSkipVerify()}
+	var conn net.Conn
+	dialer := &net.Dialer{Timeout: timeout}
+	if u.Scheme == "smtps" {
+		conn, err = tls.DialWithDialer(dialer, "tcp", addr, tlsConfig)
+	} else {
+		conn, err = dialer.Dial("tcp", addr)
+	}
+	if err != nil {
+		return err
+	}
+	conn.SetDeadline(time.Now().Add(timeout))
+
+	c, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer c.Close()
+
+	if u.Scheme == "smtp" {
+		if ok, _ := c.Extension("STARTTLS"); ok {
+			if err = c.StartTLS(tlsConfig); err != nil {
+				return err
+			}
+		} else if u.Query().Get("plaintext") != "true" {
+			return errors.New("the SMTP server does not support STARTTLS, add plaintext=true to the URL to send without encryption")
+		}
+	}
+	if e.Token != "" {
+		var auth smtp.Auth = smtp.PlainAuth("", e.Token, e.Secret, host)
+		if strings.EqualFold(u.Query().Get("auth"), "login") {
+			auth = &loginAuth{username: e.Token, password: e.Secret, host: host}
+		}
+		if err = c.Auth(auth); err != nil {
+			return err
+		}
+	}
+
+	if err = c.Mail(from); err != nil {
+		return err
+	}
+	for _, rcpt := range to {
+		if err = c.Rcpt(rcpt); err != nil {
+			return err
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err = w.Write(emailMessage(from, to, title, text)); err != nil {
+		return err
+	}
+	if err = w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+// emailMessage 生成邮件内容, 正文使用base64编码
+func emailMessage(from string, to []string, subject string, text string) []byte {
+	contentType := "text/plain"
+	if strings.HasPrefix(strings.TrimSpace(text), "<") {
+		contentType = "text/html"
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "From: %s\r\n", from)
+	fmt.Fprintf(&sb, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(&sb, "Subject: %s\r\n", mime.QEncoding.Encode("UTF-8", subject))
+	fmt.Fprintf(&sb, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	sb.WriteString("MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&sb, "Content-Type: %s; charset=UTF-8\r\n", contentType)
+	sb.WriteString("Content-Transfer-Encoding: base64\r\n\r\n")
+
+	body := base64.StdEncoding.EncodeToString([]byte(text))
+	for len(body) > 76 {
+		sb.WriteString(body[:76] + "\r\n")
+		body = body[76:]
+	}
+	sb.WriteString(body + "\r\n")
+	return []byte(sb.String())
+}
+
+// loginAuth LOGIN 认证, 与 smtp.PlainAuth 相同仅在TLS或本机时发送密码
+type loginAuth struct {
+	username, password, host string
+}
+
+func (a *loginAuth) Start(server *smtp.ServerInfo) (string, []byte, error) {
+	if !server.TLS && !isLocalhost(server.Name) {
+		return "", nil, errors.New("unencrypted connection")
+	}
+	if server.Name != a.host {
+		return "", nil, errors.New("wrong host name")
+	}
+	return "LOGIN", nil, nil
+}
+
+func (a *loginAuth) Next(fromServer []byte, more bool) ([]byte, error) {
+	if !more {
+		return nil, nil
+	}
+	switch strings.ToLower(strings.TrimSuffix(string(fromServer), ":")) {
+	case "username":
+		return []byte(a.username), nil
+	case "password":
+		return []byte(a.password), nil
+	}
+	return nil, fmt.Errorf("unexpected server challenge: %s", fromServer)
+}
+
+func isLocalhost(name string) bool {
+	return name == "localhost" || name == "127.0.0.1" || name == "::1"
+}
@@ -0,0 +1,207 @@
+package notify
+
+import (
+	"bufio"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"mime"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// smtpMail 测试SMTP服务器收到的邮件
+type smtpMail struct {
+	Auth []string
+	From string
+	To   []string
+	Data string
+	TLS  bool
+}
+
+// startTestSMTPServer 启动一个仅用于测试的SMTP服务器。
+// tlsConfig 不为空时, implicit 为 true 则使用TLS, 否则支持STARTTLS
+func startTestSMTPServer(t *testing.T, tlsConfig *tls.Config, implicit bool) (string, chan smtpMail) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if implicit {
+		listener = tls.NewListener(listener, tlsConfig)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	mails := make(chan smtpMail, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		mail := smtpMail{TLS: implicit}
+		tp := textproto.NewConn(conn)
+		tp.PrintfLine("220 localhost ESMTP")
+		for {
+			line, err := tp.ReadLine()
+			if err != nil {
+				return
+			}
+			cmd, arg, _ := strings.Cut(line, " ")
+			switch strings.ToUpper(cmd) {
+			case "EHLO":
+				tp.PrintfLine("250-localhost")
+				if tlsConfig != nil && !mail.TLS {
+					tp.PrintfLine("250-STARTTLS")
+				}
+				tp.PrintfLine("250 AUTH PLAIN LOGIN")
+			case "STARTTLS":
+				tp.PrintfLine("220 Ready to start TLS")
+				tlsConn := tls.Server(conn, tlsConfig)
+				if tlsConn.Handshake() != nil {
+					return
+				}
+				conn, tp, mail.TLS = tlsConn, textproto.NewConn(tlsConn), true
+			case "AUTH":
+				mechanism, initial, _ := strings.Cut(arg, " ")
+				mail.Auth = append(mail.Auth, mechanism)
+				if mechanism == "PLAIN" {
+					decoded, _ := base64.StdEncoding.DecodeString(initial)
+					mail.Auth = append(mail.Auth, strings.Split(string(decoded), "\x00")...)
+				} else {
+					for _, prompt := range []string{"Username:", "Password:"} {
+						tp.PrintfLine("334 %s", base64.StdEncoding.EncodeToString([]byte(prompt)))
+						resp, _ := tp.ReadLine()
+						decoded, _ := base64.StdEncoding.DecodeString(resp)
+						mail.Auth = append(mail.Auth, string(decoded))
+					}
+				}
+				tp.PrintfLine("235 Authentication successful")
+			case "MAIL":
+				mail.From = strings.Trim(strings.TrimPrefix(arg, "FROM:"), "<>")
+				tp.PrintfLine("250 OK")
+			case "RCPT":
+				mail.To = append(mail.To, strings.Trim(strings.TrimPrefix(arg, "TO:"), "<>"))
+				tp.PrintfLine("250 OK")
+			case "DATA":
+				tp.PrintfLine("354 End data with <CR><LF>.<CR><LF>")
+				lines, _ := tp.ReadDotLines()
+				mail.Data = strings.Join(lines, "\n")
+				tp.PrintfLine("250 OK")
+			case "QUIT":
+				tp.PrintfLine("221 Bye")
+				mails <- mail
+				return
+			default:
+				tp.PrintfLine("250 OK")
+			}
+		}
+	}()
+	return listener.Addr().String(), mails
+}
+
+// testTLSConfig 使用 httptest 的证书, 并信任该证书
+func testTLSConfig(t *testing.T) *tls.Config {
+	server := httptest.NewUnstartedServer(http.NotFoundHandler())
+	server.StartTLS()
+	t.Cleanup(server.Close)
+
+	pool := x509.NewCertPool()
+	pool.AddCert(server.Certificate())
+	emailRootCAs = pool
+	t.Cleanup(func() { emailRootCAs = nil })
+	return server.TLS
+}
+
+// readMailBody 解析邮件头及base64编码的正文
+func readMailBody(t *testing.T, data string) (textproto.MIMEHeader, string) {
+	reader := textproto.NewReader(bufio.NewReader(strings.NewReader(data + "\n")))
+	header, err := reader.ReadMIMEHeader()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var body strings.Builder
+	for {
+		line, err := reader.ReadLine()
+		if err != nil {
+			break
+		}
+		body.WriteString(line)
+	}
+	decoded, err := base64.StdEncoding.DecodeString(body.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return header, string(decoded)
+}
+
+func TestEmail(t *testing.T) {
+	// 不支持STARTTLS时需明确允许不加密
+	addr, _ := startTestSMTPServer(t, nil, false)
+	notifier, _ := New(Config{Type: "email", URL: "smtp://" + addr + "?from=ddns@example.com", Token: "user", Secret: "pass", Target: "a@example.com"})
+	if err := notifier.Send("title", "text"); err == nil {
+		t.Error("Expected error without STARTTLS")
+	}
+
+	addr, mails := startTestSMTPServer(t, nil, false)
+	notifier, _ = New(Config{Type: "email", URL: "smtp://" + addr + "?from=ddns@example.com&plaintext=true", Token: "user", Secret: "pass", Target: "a@example.com, b@example.com"})
+	if err := notifier.Send("ddns-go - 家", "IPv4 1.2.3.4"); err != nil {
+		t.Fatalf("Expected nil error, got %v", err)
+	}
+
+	mail := <-mails
+	if mail.From != "ddns@example.com" || strings.Join(mail.To, ",") != "a@example.com,b@example.com" {
+		t.Errorf("Unexpected envelope %s -> %v", mail.From, mail.To)
+	}
+	if strings.Join(mail.Auth, ",") != "PLAIN,,user,pass" {
+		t.Errorf("Unexpected auth %v", mail.Auth)
+	}
+	header, body := readMailBody(t, mail.Data)
+	subject, _ := new(mime.WordDecoder).DecodeHeader(header.Get("Subject"))
+	if subject != "ddns-go - 家" || header.Get("To") != "a@example.com, b@example.com" {
+		t.Errorf("Unexpected header %v", header)
+	}
+	if !strings.HasPrefix(header.Get("Content-Type"), "text/plain") || body != "IPv4 1.2.3.4" {
+		t.Errorf("Unexpected body %s: %q", header.Get("Content-Type"), body)
+	}
+}
+
+func TestEmailStartTLS(t *testing.T) {
+	addr, mails := startTestSMTPServer(t, testTLSConfig(t), false)
+	notifier, _ := New(Config{Type: "email", URL: "smtp://" + addr + "?auth=login", Token: "user@example.com", Secret: "pass", Target: "a@example.com"})
+	html := "<p>" + strings.Repeat("IPv4 1.2.3.4 ", 20) + "</p>"
+	if err := notifier.Send("title", html); err != nil {
+		t.Fatalf("Expected nil error, got %v", err)
+	}
+
+	mail := <-mails
+	if !mail.TLS || mail.From != "user@example.com" || strings.Join(mail.Auth, ",") != "LOGIN,user@example.com,pass" {
+		t.Errorf("Unexpected mail %v", mail)
+	}
+	header, body := readMailBody(t, mail.Data)
+	if !strings.HasPrefix(header.Get("Content-Type"), "text/html") || body != html {
+		t.Errorf("Unexpected body %s: %q", header.Get("Content-Type"), body)
+	}
+}
+
+func TestEmailImplicitTLS(t *testing.T) {
+	addr, mails := startTestSMTPServer(t, testTLSConfig(t), true)
+	u := url.URL{Scheme: "smtps", Host: addr}
+	notifier, _ := New(Config{Type: "email", URL: u.String(), Target: "a@example.com", Token: "user@example.com", Secret: "pass"})
+	if err := notifier.Send("title", "text"); err != nil {
+		t.Fatalf("Expected nil error, got %v", err)
+	}
+	if mail := <-mails; !mail.TLS || len(mail.To) != 1 {
+		t.Errorf("Unexpected mail %v", mail)
+	}
+
+	notifier, _ = New(Config{Type: "email", URL: "http://" + addr, Target: "a@example.com", Token: "user"})
+	if err := notifier.Send("title", "text"); err == nil {
+		t.Error("Expected error for non SMTP URL")
+	}
+}
@@ -0,0 +1,47 @@
+package notify
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"time"
+)
+
+// Feishu URL 为机器人的 Webhook 地址, Secret 为签名校验的密钥
+type Feishu struct {
+	Config
+}
+
+// feishuSign 飞书签名: base64(HmacSHA256("", timestamp+"\n"+secret))
+func feishuSign(timestamp int64, secret string) string {
+	mac := hmac.New(sha256.New, []byte(strconv.FormatInt(timestamp, 10)+"\n"+secret))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// Send 发送文本消息
+func (f *Feishu) Send(title string, text string) error {
+	if err := required("URL", f.URL); err != nil {
+		return err
+	}
+	payload := map[string]any{
+		"msg_type": "text",
+		"content":  map[string]string{"text": title + "\n" + text},
+	}
+	if f.Secret != "" {
+		timestamp := time.Now().Unix()
+		payload["timestamp"] = strconv.FormatInt(timestamp, 10)
+		payload["sign"] = feishuSign(timestamp, f.Secret)
+	}
+
+	var result struct {
+		Code int    `json:"code"`
+		Msg  string `json:"msg"`
+	}
+	err := f.postJSON(f.URL, nil, payload, &result)
+	if err == nil && result.Code != 0 {
+		err = errors.New(result.Msg)
+	}
+	return err
+}
@@ -0,0 +1,21 @@
+package notify
+
+import "net/http"
+
+// Gotify URL 为服务器地址, Token 为 App Token
+type Gotify struct {
+	Config
+}
+
+// Send 发送消息
+func (g *Gotify) Send(title string, text string) error {
+	if err := required("URL", g.URL, "Token", g.Token); err != nil {
+		return err
+	}
+	header := http.Header{}
+	header.Set("X-Gotify-Key", g.Token)
+	return g.postJSON(g.URL+"/message", header, map[string]string{
+		"title":   title,
+		"message": text,
+	}, nil)
+}
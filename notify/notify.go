@@ -0,0 +1,109 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/jeessy2/ddns-go/v6/util"
+)
+
+// Config 通知渠道的配置, 各字段的含义因渠道而异
+type Config struct {
+	// 渠道: telegram/slack/discord/dingtalk/feishu/wecom/ntfy/gotify/bark/email
+	Type string
+	// Webhook地址, 自建服务器地址或SMTP服务器
+	URL string
+	// Bot Token/App Token/Device Key/SMTP用户名
+	Token string
+	// Chat ID/Topic/收件人
+	Target string
+	// 加签密钥/SMTP密码
+	Secret string
+	// 超时时间, 为0则为30秒
+	Timeout time.Duration
+}
+
+// Notifier 通知渠道
+type Notifier interface {
+	Send(title string, text string) error
+}
+
+// New 根据类型创建通知渠道
+func New(conf Config) (Notifier, error) {
+	conf.URL = strings.TrimSuffix(strings.TrimSpace(conf.URL), "/")
+	var notifier Notifier
+	switch conf.Type {
+	case "telegram":
+		notifier = &Telegram{conf}
+	case "slack":
+		notifier = &Slack{conf}
+	case "discord":
+		notifier = &Discord{conf}
+	case "dingtalk":
+		notifier = &DingTalk{conf}
+	case "feishu":
+		notifier = &Feishu{conf}
+	case "wecom":
+		notifier = &WeCom{conf}
+	case "ntfy":
+		notifier = &Ntfy{conf}
+	case "gotify":
+		notifier = &Gotify{conf}
+	case "bark":
+		notifier = &Bark{conf}
+	case "email":
+		notifier = &Email{conf}
+	default:
+		return nil, errors.New("unknown notification type: " + conf.Type)
+	}
+	return notifier, nil
+}
+
+// IsNotifyType 是否为内置的通知渠道
+func IsNotifyType(notifyType string) bool {
+	_, err := New(Config{Type: notifyType})
+	return err == nil
+}
+
+// required 校验必填的字段
+func required(fields ...string) error {
+	for i := 0; i < len(fields); i += 2 {
+		if strings.TrimSpace(fields[i+1]) == "" {
+			return errors.New(fields[i] + " is required")
+		}
+	}
+	return nil
+}
+
+// postJSON 发送JSON, 返回的内容解析到 result
+func (conf Config) postJSON(url string, header http.Header, payload any, result any) error {
+	byt, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest("POST", url, bytes.NewReader(byt))
+	if err != nil {
+		return err
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := util.CreateHTTPClient()
+	if conf.Timeout > 0 {
+		client.Timeout = conf.Timeout
+	}
+	// URL中可能有Token, 如 Telegram
+	resp, err := client.Do(util.WithRedactedURL(req))
+	err = util.RedactURLError(err)
+	if result == nil {
+		_, err = util.GetHTTPResponseOrg(resp, err)
+		return err
+	}
+	return util.GetHTTPResponse(resp, err, result)
+}
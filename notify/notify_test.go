@@ -0,0 +1,136 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/jeessy2/ddns-go/v6/util"
+)
+
+// startTestServer 启动一个仅用于测试的服务器, 记录请求并返回 response
+func startTestServer(t *testing.T, response string) (*httptest.Server, *http.Request, *map[string]any) {
+	var last http.Request
+	payload := map[string]any{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		last = *r
+		body, _ := io.ReadAll(r.Body)
+		json.Unmarshal(body, &payload)
+		io.WriteString(w, response)
+	}))
+	t.Cleanup(server.Close)
+	return server, &last, &payload
+}
+
+func TestTelegram(t *testing.T) {
+	server, req, payload := startTestServer(t, `{"ok":true}`)
+	notifier, _ := New(Config{Type: "telegram", URL: server.URL + "/", Token: "123:abc", Target: "42"})
+	if err := notifier.Send("ddns-go - home", "IPv4 1.2.3.4 (a_b.example.com)"); err != nil {
+		t.Fatalf("Expected nil error, got %v", err)
+	}
+	if req.URL.Path != "/bot123:abc/sendMessage" {
+		t.Errorf("Unexpected path %s", req.URL.Path)
+	}
+	expected := "*ddns\\-go \\- home*\nIPv4 1\\.2\\.3\\.4 \\(a\\_b\\.example\\.com\\)"
+	if (*payload)["text"] != expected || (*payload)["parse_mode"] != "MarkdownV2" {
+		t.Errorf("Expected %q, got %v", expected, *payload)
+	}
+
+	failed, _, _ := startTestServer(t, `{"ok":false,"description":"chat not found"}`)
+	notifier, _ = New(Config{Type: "telegram", URL: failed.URL, Token: "123:abc", Target: "42"})
+	if err := notifier.Send("title", "text"); err == nil || err.Error() != "chat not found" {
+		t.Errorf("Expected chat not found, got %v", err)
+	}
+}
+
+// TestTelegramDebugLog 测试 debug 日志及异常信息中不输出路径中的Token
+func TestTelegramDebugLog(t *testing.T) {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+	util.SetLogLevel("debug")
+	t.Cleanup(func() { util.SetLogLevel("info") })
+
+	server, _, _ := startTestServer(t, `{"ok":true}`)
+	notifier, _ := New(Config{Type: "telegram", URL: server.URL, Token: "123:s3cret", Target: "42"})
+	if err := notifier.Send("title", "text"); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), server.URL+"/***") || strings.Contains(buf.String(), "s3cret") {
+		t.Errorf("Expected the token to be redacted, got %s", buf.String())
+	}
+
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+	notifier, _ = New(Config{Type: "telegram", URL: closed.URL, Token: "123:s3cret", Target: "42"})
+	if err := notifier.Send("title", "text"); err == nil || strings.Contains(err.Error(), "s3cret") {
+		t.Errorf("Expected an error without the token, got %v", err)
+	}
+}
+
+func TestDingTalk(t *testing.T) {
+	server, req, payload := startTestServer(t, `{"errcode":0}`)
+	notifier, _ := New(Config{Type: "dingtalk", URL: server.URL + "/robot/send?access_token=abc", Secret: "SEC123"})
+	if err := notifier.Send("title", "text"); err != nil {
+		t.Fatalf("Expected nil error, got %v", err)
+	}
+	q := req.URL.Query()
+	timestamp, _ := strconv.ParseInt(q.Get("timestamp"), 10, 64)
+	if q.Get("access_token") != "abc" || q.Get("sign") == "" || q.Get("sign") != dingTalkSign(timestamp, "SEC123") {
+		t.Errorf("Unexpected query %s", req.URL.RawQuery)
+	}
+	if (*payload)["msgtype"] != "text" {
+		t.Errorf("Unexpected payload %v", *payload)
+	}
+
+	failed, _, _ := startTestServer(t, `{"errcode":310000,"errmsg":"sign not match"}`)
+	notifier, _ = New(Config{Type: "dingtalk", URL: failed.URL, Secret: "SEC123"})
+	if err := notifier.Send("title", "text"); err == nil {
+		t.Error("Expected error, got nil")
+	}
+}
+
+func TestFeishu(t *testing.T) {
+	server, _, payload := startTestServer(t, `{"code":0}`)
+	notifier, _ := New(Config{Type: "feishu", URL: server.URL, Secret: "secret"})
+	if err := notifier.Send("title", "text"); err != nil {
+		t.Fatalf("Expected nil error, got %v", err)
+	}
+	timestamp, _ := strconv.ParseInt((*payload)["timestamp"].(string), 10, 64)
+	if (*payload)["sign"] != feishuSign(timestamp, "secret") {
+		t.Errorf("Unexpected payload %v", *payload)
+	}
+}
+
+func TestSign(t *testing.T) {
+	// 按官方文档的算法计算的结果
+	if sign := dingTalkSign(1700000000000, "SECxxx"); sign != "plK5HYD7pW0AMQz3PBPzNXBlZe9ZIHa2a52gMYB3lHs=" {
+		t.Errorf("Unexpected DingTalk sign %s", sign)
+	}
+	if sign := feishuSign(1700000000, "secret"); sign != "fiWS2+gh28DOydAv7hzONH/mDn9+b1Y4Y5ivXWXy8vA=" {
+		t.Errorf("Unexpected Feishu sign %s", sign)
+	}
+}
+
+func TestNew(t *testing.T) {
+	for _, notifyType := range []string{"telegram", "slack", "discord", "dingtalk", "feishu", "wecom", "ntfy", "gotify", "bark"} {
+		if !IsNotifyType(notifyType) {
+			t.Errorf("Expected %s to be a notify type", notifyType)
+		}
+	}
+	if IsNotifyType("webhook") {
+		t.Error("Expected webhook not to be a notify type")
+	}
+
+	notifier, _ := New(Config{Type: "gotify"})
+	if err := notifier.Send("title", "text"); err == nil {
+		t.Error("Expected error for missing URL, got nil")
+	}
+}
@@ -0,0 +1,31 @@
+package notify
+
+import "net/http"
+
+// ntfyEndpoint ntfy 公共服务器
+const ntfyEndpoint = "https://ntfy.sh"
+
+// Ntfy Target 为 Topic, URL 可指定自建服务器, Token 为访问令牌(可选)
+type Ntfy struct {
+	Config
+}
+
+// Send 发送消息
+func (n *Ntfy) Send(title string, text string) error {
+	if err := required("Topic", n.Target); err != nil {
+		return err
+	}
+	endpoint := n.URL
+	if endpoint == "" {
+		endpoint = ntfyEndpoint
+	}
+	header := http.Header{}
+	if n.Token != "" {
+		header.Set("Authorization", "Bearer "+n.Token)
+	}
+	return n.postJSON(endpoint, header, map[string]string{
+		"topic":   n.Target,
+		"title":   title,
+		"message": text,
+	}, nil)
+}
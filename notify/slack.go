@@ -0,0 +1,21 @@
+package notify
+
+import "strings"
+
+// Slack URL 为 Incoming Webhook 地址
+type Slack struct {
+	Config
+}
+
+// slackEscaper 转义 Slack 消息中的控制字符
+var slackEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// Send 发送消息, 标题加粗
+func (s *Slack) Send(title string, text string) error {
+	if err := required("URL", s.URL); err != nil {
+		return err
+	}
+	return s.postJSON(s.URL, nil, map[string]string{
+		"text": "*" + slackEscaper.Replace(title) + "*\n" + slackEscaper.Replace(text),
+	}, nil)
+}
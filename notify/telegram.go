@@ -0,0 +1,46 @@
+package notify
+
+import (
+	"errors"
+	"strings"
+)
+
+// telegramEndpoint Telegram Bot API
+const telegramEndpoint = "https://api.telegram.org"
+
+// Telegram Token 为 Bot Token, Target 为 Chat ID, URL 可指定 Bot API 的反向代理
+type Telegram struct {
+	Config
+}
+
+// telegramEscaper 转义 MarkdownV2 中的特殊字符
+var telegramEscaper = strings.NewReplacer(
+	`\`, `\\`, "_", `\_`, "*", `\*`, "[", `\[`, "]", `\]`, "(", `\(`, ")", `\)`,
+	"~", `\~`, "`", "\\`", ">", `\>`, "#", `\#`, "+", `\+`, "-", `\-`, "=", `\=`,
+	"|", `\|`, "{", `\{`, "}", `\}`, ".", `\.`, "!", `\!`,
+)
+
+// Send 发送消息, 标题加粗
+func (t *Telegram) Send(title string, text string) error {
+	if err := required("Token", t.Token, "Chat ID", t.Target); err != nil {
+		return err
+	}
+	endpoint := t.URL
+	if endpoint == "" {
+		endpoint = telegramEndpoint
+	}
+
+	var result struct {
+		Ok          bool   `json:"ok"`
+		Description string `json:"description"`
+	}
+	err := t.postJSON(endpoint+"/bot"+t.Token+"/sendMessage", nil, map[string]any{
+		"chat_id":    t.Target,
+		"text":       "*" + telegramEscaper.Replace(title) + "*\n" + telegramEscaper.Replace(text),
+		"parse_mode": "MarkdownV2",
+	}, &result)
+	if err == nil && !result.Ok {
+		err = errors.New(result.Description)
+	}
+	return err
+}
@@ -0,0 +1,28 @@
+package notify
+
+import "errors"
+
+// WeCom 企业微信, URL 为群机器人的 Webhook 地址
+type WeCom struct {
+	Config
+}
+
+// Send 发送文本消息
+func (w *WeCom) Send(title string, text string) error {
+	if err := required("URL", w.URL); err != nil {
+		return err
+	}
+
+	var result struct {
+		ErrCode int    `json:"errcode"`
+		ErrMsg  string `json:"errmsg"`
+	}
+	err := w.postJSON(w.URL, nil, map[string]any{
+		"msgtype": "text",
+		"text":    map[string]string{"content": title + "\n" + text},
+	}, &result)
+	if err == nil && result.ErrCode != 0 {
+		err = errors.New(result.ErrMsg)
+	}
+	return err
+}
@@ -282,6 +282,156 @@ const DNS_PROVIDERS = {
   },
 };
 
+// 通知渠道, label 为空时隐藏对应的输入框
+const NOTIFY_TYPES = {
+  webhook: {
+    name: {
+      "en": "Webhook",
+    },
+    urlLabel: "URL",
+    tokenLabel: "",
+    targetLabel: "",
+    secretLabel: "Secret",
+    headers: true,
+    helpHtml: {
+      "en": "",
+    }
+  },
+  telegram: {
+    name: {
+      "en": "Telegram",
+    },
+    urlLabel: "API URL",
+    tokenLabel: "Bot Token",
+    targetLabel: "Chat ID",
+    secretLabel: "",
+    helpHtml: {
+      "en": "API URL is optional, fill in only when using a Bot API proxy",
+      "zh-cn": "API URL 可选, 仅在使用 Bot API 反向代理时填写",
+    }
+  },
+  slack: {
+    name: {
+      "en": "Slack",
+    },
+    urlLabel: "Webhook URL",
+    tokenLabel: "",
+    targetLabel: "",
+    secretLabel: "",
+    helpHtml: {
+      "en": "<a target='_blank' href='https://api.slack.com/messaging/webhooks'>Create an Incoming Webhook</a>",
+      "zh-cn": "<a target='_blank' href='https://api.slack.com/messaging/webhooks'>创建 Incoming Webhook</a>",
+    }
+  },
+  discord: {
+    name: {
+      "en": "Discord",
+    },
+    urlLabel: "Webhook URL",
+    tokenLabel: "",
+    targetLabel: "",
+    secretLabel: "",
+    helpHtml: {
+      "en": "Server Settings - Integrations - Webhooks",
+      "zh-cn": "服务器设置 - 整合 - Webhooks",
+    }
+  },
+  dingtalk: {
+    name: {
+      "en": "DingTalk",
+      "zh-cn": "钉钉",
+    },
+    urlLabel: "Webhook URL",
+    tokenLabel: "",
+    targetLabel: "",
+    secretLabel: "Secret",
+    helpHtml: {
+      "en": "Secret is the signing secret of the robot, optional",
+      "zh-cn": "Secret 为机器人安全设置中的加签密钥, 可选",
+    }
+  },
+  feishu: {
+    name: {
+      "en": "Feishu / Lark",
+      "zh-cn": "飞书",
+    },
+    urlLabel: "Webhook URL",
+    tokenLabel: "",
+    targetLabel: "",
+    secretLabel: "Secret",
+    helpHtml: {
+      "en": "Secret is the signature verification secret of the bot, optional",
+      "zh-cn": "Secret 为机器人安全设置中签名校验的密钥, 可选",
+    }
+  },
+  wecom: {
+    name: {
+      "en": "WeCom",
+      "zh-cn": "企业微信",
+    },
+    urlLabel: "Webhook URL",
+    tokenLabel: "",
+    targetLabel: "",
+    secretLabel: "",
+    helpHtml: {
+      "en": "The Webhook URL of the group robot",
+      "zh-cn": "群机器人的 Webhook 地址",
+    }
+  },
+  ntfy: {
+    name: {
+      "en": "ntfy",
+    },
+    urlLabel: "Server URL",
+    tokenLabel: "Access Token",
+    targetLabel: "Topic",
+    secretLabel: "",
+    helpHtml: {
+      "en": "Server URL defaults to https://ntfy.sh, Access Token is optional",
+      "zh-cn": "Server URL 默认为 https://ntfy.sh, Access Token 可选",
+    }
+  },
+  gotify: {
+    name: {
+      "en": "Gotify",
+    },
+    urlLabel: "Server URL",
+    tokenLabel: "App Token",
+    targetLabel: "",
+    secretLabel: "",
+    helpHtml: {
+      "en": "",
+    }
+  },
+  bark: {
+    name: {
+      "en": "Bark",
+    },
+    urlLabel: "Server URL",
+    tokenLabel: "Device Key",
+    targetLabel: "",
+    secretLabel: "",
+    helpHtml: {
+      "en": "Server URL defaults to https://api.day.app",
+      "zh-cn": "Server URL 默认为 https://api.day.app",
+    }
+  },
+  email: {
+    name: {
+      "en": "Email",
+      "zh-cn": "邮件",
+    },
+    urlLabel: "SMTP URL",
+    tokenLabel: "Username",
+    targetLabel: "To",
+    secretLabel: "Password",
+    helpHtml: {
+      "en": "SMTP URL e.g. smtps://smtp.example.com:465 (TLS) or smtp://smtp.example.com:587 (STARTTLS). smtp:// requires STARTTLS unless plaintext=true is added to send without encryption. Append ?from=sender to set the sender (defaults to Username) and &auth=login to use LOGIN authentication. Separate multiple recipients with commas. A message starting with &lt; is sent as HTML",
+      "zh-cn": "SMTP URL 如 smtps://smtp.example.com:465 (TLS) 或 smtp://smtp.example.com:587 (STARTTLS)。smtp:// 要求支持STARTTLS, 加上 plaintext=true 才允许不加密发送。可加上 ?from=发件人 指定发件人 (默认为 Username), &auth=login 使用 LOGIN 认证。多个收件人以逗号分割。以 &lt; 开头的消息以HTML发送",
+    }
+  },
+};
+
 const SVG_CODE = {
   success: `<svg viewBox="64 64 896 896" focusable="false" data-icon="check-circle" width="1em" height="1em" fill="#52c41a" aria-hidden="true"><path d="M512 64C264.6 64 64 264.6 64 512s200.6 448 448 448 448-200.6 448-448S759.4 64 512 64zm193.5 301.7l-210.6 292a31.8 31.8 0 01-51.7 0L318.5 484.9c-3.8-5.3 0-12.7 6.5-12.7h46.9c10.2 0 19.9 4.9 25.9 13.3l71.2 98.8 157.2-218c6-8.3 15.6-13.3 25.9-13.3H699c6.5 0 10.3 7.4 6.5 12.7z"></path></svg>`,
   info: `<svg viewBox="64 64 896 896" focusable="false" data-icon="info-circle" width="1em" height="1em" fill="#1677ff" aria-hidden="true"><path d="M512 64C264.6 64 64 264.6 64 512s200.6 448 448 448 448-200.6 448-448S759.4 64 512 64zm32 664c0 4.4-3.6 8-8 8h-48c-4.4 0-8-3.6-8-8V456c0-4.4 3.6-8 8-8h48c4.4 0 8 3.6 8 8v272zm-32-344a48.01 48.01 0 010-96 48.01 48.01 0 010 96z"></path></svg>`,
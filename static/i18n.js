@@ -3,6 +3,26 @@ const I18N_MAP = {
     'en': 'Logs',
     'zh-cn': '日志'
   },
+  'History': {
+    'en': 'History',
+    'zh-cn': '更新历史'
+  },
+  'Filter by domain': {
+    'en': 'Filter by domain',
+    'zh-cn': '按域名筛选'
+  },
+  'No history yet': {
+    'en': 'No history yet',
+    'zh-cn': '暂无更新历史'
+  },
+  'Previous': {
+    'en': 'Previous',
+    'zh-cn': '上一页'
+  },
+  'Next': {
+    'en': 'Next',
+    'zh-cn': '下一页'
+  },
   'Save': {
     'en': 'Save',
     'zh-cn': '保存'
@@ -95,6 +115,42 @@ const I18N_MAP = {
     'en': 'By command',
     'zh-cn': '通过命令获取'
   },
+  "UrlQuorumPlaceholder": {
+    'en': 'Consensus (optional)',
+    'zh-cn': '共识数量(可选)'
+  },
+  "UrlQuorumHelp": {
+    'en': 'When filled in with K, query all APIs in parallel and use the IP only when at least K APIs return the same IP, otherwise the update is skipped. Prevents publishing a wrong IP returned by a cached or proxied API. When empty, the first successful API is used',
+    'zh-cn': '填写K时, 并行查询所有接口, 至少K个接口返回相同的IP时才使用, 否则不更新。防止发布被缓存或代理的接口返回的错误IP。为空时使用第一个成功的接口'
+  },
+  'By STUN': {
+    'en': 'By STUN',
+    'zh-cn': '通过STUN获取'
+  },
+  'By DNS query': {
+    'en': 'By DNS query',
+    'zh-cn': '通过DNS查询获取'
+  },
+  'By router': {
+    'en': 'By router',
+    'zh-cn': '通过路由器获取'
+  },
+  "RouterHelp": {
+    'en': 'Get the WAN address of the upstream router through UPnP IGD, NAT-PMP or PCP, the router needs to enable UPnP or NAT-PMP. A warning is logged when the WAN address is a private or carrier-grade NAT (100.64.0.0/10) address',
+    'zh-cn': '通过 UPnP IGD、NAT-PMP 或 PCP 获取上游路由器的WAN口地址, 路由器需开启 UPnP 或 NAT-PMP。WAN口地址为私有地址或运营商级NAT(100.64.0.0/10)地址时会在日志中提示'
+  },
+  "DnsQueryHelp": {
+    'en': 'Format: <code>DNS server domain [type]</code>, multiple queries are separated by <code>,</code> and tried in order. The type can be <code>A</code>, <code>AAAA</code> or <code>TXT</code>, the default is A for IPv4 and AAAA for IPv6. Such as <code>resolver1.opendns.com myip.opendns.com A</code>, <code>ns1.google.com o-o.myaddr.l.google.com TXT</code>',
+    'zh-cn': '格式: <code>DNS服务器 域名 [类型]</code>, 多个查询以 <code>,</code> 分割并依次尝试。类型可为 <code>A</code>、<code>AAAA</code>、<code>TXT</code>, IPv4默认为A, IPv6默认为AAAA。如 <code>resolver1.opendns.com myip.opendns.com A</code>、<code>ns1.google.com o-o.myaddr.l.google.com TXT</code>'
+  },
+  "StunConsensus": {
+    'en': 'Require two servers to agree',
+    'zh-cn': '需要两个服务器返回相同的地址'
+  },
+  "StunHelp": {
+    'en': 'STUN servers, separated by <code>,</code>, the default port is 3478. Send a Binding Request (RFC 5389) to the servers in order and use the XOR-MAPPED-ADDRESS of the response, does not depend on HTTP "what is my IP" services',
+    'zh-cn': 'STUN服务器, 多个以 <code>,</code> 分割, 默认端口 3478。依次向服务器发送 Binding Request(RFC 5389), 使用响应中的 XOR-MAPPED-ADDRESS, 不依赖HTTP查询IP的接口'
+  },
   'domainsHelp': {
     'en': `
       Enter one domain per line.
@@ -108,13 +164,79 @@ const I18N_MAP = {
       支持<a target="blank" href="https://github.com/jeessy2/ddns-go/wiki/传递自定义参数">自定义参数</a>
     `
   },
+  'ipv6DomainsHelp': {
+    'en': `
+      Enter one domain per line.
+      If the domain is unregistrable, manually separate it into a subdomain and a root domain by using a colon. e.g. <code>www:domain.example.com</code><br />
+      For LAN hosts, the address can be composed of the current IPv6 prefix and a fixed interface identifier: <code>nas.example.com?Ipv6Suffix=::1234:5678:9abc:def0</code>, or EUI-64 from the MAC address <code>pc.example.com?Ipv6Mac=00:11:22:33:44:55</code>. The prefix length is 64 by default and can be changed with <code>&Ipv6PrefixLen=56</code>. Supported by all DNS providers except Aliesa, Callback, Dynadot, EdgeOne, Eranet, NameCheap, NameSilo, Nowcn and Spaceship<br />
+      Support for <a target="blank" href="https://github.com/jeessy2/ddns-go/wiki/传递自定义参数">custom parameters</a> (Simplified Chinese)
+    `,
+    'zh-cn': `
+      每行一个域名。
+      如果域名不可注册，请使用冒号手动将其分为子域名和根域名。如 <code>www:domain.example.com</code><br />
+      局域网主机的地址可由当前的IPv6前缀与固定的接口标识组成: <code>nas.example.com?Ipv6Suffix=::1234:5678:9abc:def0</code>, 或由MAC地址生成EUI-64 <code>pc.example.com?Ipv6Mac=00:11:22:33:44:55</code>。前缀长度默认为64, 可通过 <code>&Ipv6PrefixLen=56</code> 修改。除阿里云ESA、Callback、Dynadot、EdgeOne、Eranet、NameCheap、NameSilo、Nowcn、Spaceship外均支持<br />
+      支持<a target="blank" href="https://github.com/jeessy2/ddns-go/wiki/传递自定义参数">自定义参数</a>
+    `
+  },
+  'Fallback sources': {
+    'en': 'Fallback sources',
+    'zh-cn': '备用来源'
+  },
+  'FallbackPlaceholder': {
+    'en': 'One per line, such as url:10 https://api.ipify.org',
+    'zh-cn': '每行一个, 如 url:10 https://api.ipify.org'
+  },
+  'FallbackHelp': {
+    'en': 'Tried in order when the IP cannot be got by the method above or the address is rejected. Format: <code>type[:timeout seconds] [parameter]</code>, type is one of <code>url</code> <code>netInterface</code> <code>cmd</code> <code>stun</code> <code>dns</code> <code>router</code>(IPv4 only), the parameter defaults to the one of that method above. The timeout defaults to 60 seconds, also for the method above. The source, such as <code>url https://api.ipify.org</code>, is available as <code>#{ipv4Source}</code> <code>#{ipv6Source}</code> in Webhook',
+    'zh-cn': '通过上面的方式未能获取IP或地址被拒绝时按顺序尝试。格式: <code>类型[:超时秒数] [参数]</code>, 类型为 <code>url</code> <code>netInterface</code> <code>cmd</code> <code>stun</code> <code>dns</code> <code>router</code>(仅IPv4), 参数默认使用上面对应方式的参数。超时时间默认为60秒, 上面的方式也是如此。Webhook中可通过 <code>#{ipv4Source}</code> <code>#{ipv6Source}</code> 获取来源, 如 <code>url https://api.ipify.org</code>'
+  },
+  'Address filter': {
+    'en': 'Address filter',
+    'zh-cn': '地址过滤'
+  },
+  'Allow private addresses': {
+    'en': 'Allow private addresses',
+    'zh-cn': '允许私有地址'
+  },
+  'AllowCIDRPlaceholder': {
+    'en': 'Allowed CIDRs, such as 203.0.113.0/24, 2001:db8::/32',
+    'zh-cn': '允许的地址段, 如 203.0.113.0/24, 2001:db8::/32'
+  },
+  'DenyCIDRPlaceholder': {
+    'en': 'Denied CIDRs, such as 198.51.100.0/24',
+    'zh-cn': '拒绝的地址段, 如 198.51.100.0/24'
+  },
+  'AddrFilterHelp': {
+    'en': 'Only public addresses are published by default, private (such as 10.0.0.0/8) and carrier-grade NAT (100.64.0.0/10) addresses are rejected unless allowed. Multiple CIDRs are separated by <code>,</code>. Denied CIDRs take precedence, and when allowed CIDRs are filled in only addresses in them are published. A rejected address is treated as a failure to get the IP',
+    'zh-cn': '默认仅发布公网地址, 除非允许, 否则私有地址(如 10.0.0.0/8)及运营商级NAT地址(100.64.0.0/10)将被拒绝。多个地址段以 <code>,</code> 分割。拒绝的地址段优先, 填写了允许的地址段时仅发布其中的地址。被拒绝的地址视为获取IP失败'
+  },
   'Regular exp.': {
     'en': 'Regular exp.',
     'zh-cn': '匹配正则表达式'
   },
   'regHelp': {
-    'en': 'You can use @1 to specify the first IPv6 address, @2 to specify the second IPv6 address... You can also use regular expressions to match the specified IPv6 address, leave it blank to disable it',
-    'zh-cn': '可使用 @1 指定第一个IPv6地址, @2 指定第二个IPv6地址... 也可使用正则表达式匹配指定的IPv6地址, 留空则不启用'
+    'en': 'You can use @1 to specify the first IPv6 address, @2 to specify the second IPv6 address... You can also use a CIDR such as 2001:db8::/32 or regular expressions to match the specified IPv6 address, leave it blank to disable it',
+    'zh-cn': '可使用 @1 指定第一个IPv6地址, @2 指定第二个IPv6地址... 也可使用CIDR(如 2001:db8::/32)或正则表达式匹配指定的IPv6地址, 留空则不启用'
+  },
+  'Address policy': {
+    'en': 'Address policy',
+    'zh-cn': '地址选择策略'
+  },
+  'All addresses': {
+    'en': 'All addresses',
+    'zh-cn': '所有地址'
+  },
+  'Stable only': {
+    'en': 'Stable only',
+    'zh-cn': '仅稳定地址'
+  },
+  'Longest preferred lifetime': {
+    'en': 'Longest preferred lifetime',
+    'zh-cn': '首选生存期最长'
+  },
+  'AddrPolicyHelp': {
+    'en': 'Linux only. Skip temporary (privacy), deprecated and DAD failed addresses, then use the regular exp. above. Longest preferred lifetime sorts the remaining addresses so that the one that stays valid the longest comes first',
+    'zh-cn': '仅支持 Linux。跳过临时(隐私)、已弃用及DAD失败的地址后再使用上面的匹配正则表达式。首选生存期最长会将剩余地址排序, 有效时间最长的地址排在最前'
   },
   'Others': {
     'en': 'Others',
@@ -140,6 +262,14 @@ const I18N_MAP = {
     'en': 'If you need to change the password, please enter it here',
     'zh-cn': '如需修改密码，请在此处输入新密码'
   },
+  'History retention': {
+    'en': 'History retention',
+    'zh-cn': '历史保留'
+  },
+  'HistoryRetentionHelp': {
+    'en': 'Days and number of update history entries to keep, 90 days and 10000 entries by default. The history is saved to .ddns_go_history.jsonl next to the config file',
+    'zh-cn': '更新历史保留的天数及条数, 默认 90 天、10000 条。历史保存在配置文件所在目录的 .ddns_go_history.jsonl 中'
+  },
   'Password': {
     'en': 'Password',
     'zh-cn': '密码'
@@ -161,13 +291,153 @@ const I18N_MAP = {
     `
   },
   'WebhookRequestBodyHelp': {
-    'en': 'If RequestBody is empty, it is a GET request, otherwise it is a POST request. Supported variables are the same as above',
-    'zh-cn': '如果 RequestBody 为空, 则为 GET 请求, 否则为 POST 请求。支持的变量同上'
+    'en': 'If RequestBody is empty, it is a GET request, otherwise it is a POST request. Supported variables are the same as above. In template mode the URL and RequestBody are Go templates such as <code>{{.Ipv4.OldAddr}} -> {{.Ipv4.Addr}}</code>, see the README for the data and functions',
+    'zh-cn': '如果 RequestBody 为空, 则为 GET 请求, 否则为 POST 请求。支持的变量同上。模板模式下 URL 及 RequestBody 为 Go 模板, 如 <code>{{.Ipv4.OldAddr}} -> {{.Ipv4.Addr}}</code>, 数据及函数请参考 README'
   },
   'WebhookHeadersHelp': {
     'en': 'One header per line, such as: Authorization: Bearer API_KEY',
     'zh-cn': '一行一个Header, 如: Authorization: Bearer API_KEY'
   },
+  'Message': {
+    'en': 'Message',
+    'zh-cn': '消息'
+  },
+  'NotifyMessageHelp': {
+    'en': 'The message to send, supports the same variables as Webhook and template mode. A summary of the update results is sent if empty',
+    'zh-cn': '发送的消息, 支持与Webhook相同的变量及模板模式。为空则发送更新结果的摘要'
+  },
+  'Template mode': {
+    'en': 'Template mode',
+    'zh-cn': '模板模式'
+  },
+  'Webhook:': {
+    'en': 'Webhook:',
+    'zh-cn': 'Webhook:'
+  },
+  'Events': {
+    'en': 'Events',
+    'zh-cn': '事件'
+  },
+  'Success': {
+    'en': 'Success',
+    'zh-cn': '成功'
+  },
+  'Failed': {
+    'en': 'Failed',
+    'zh-cn': '失败'
+  },
+  'Unverified': {
+    'en': 'Unverified',
+    'zh-cn': '未验证'
+  },
+  'IP changed': {
+    'en': 'IP changed',
+    'zh-cn': 'IP改变'
+  },
+  'IP lookup failed': {
+    'en': 'IP lookup failed',
+    'zh-cn': '获取IP失败'
+  },
+  'Drift': {
+    'en': 'Drift',
+    'zh-cn': '漂移'
+  },
+  'WebhookEventsHelp': {
+    'en': 'The events that trigger this Webhook, defaults to success, failed, unverified and recovered if none is selected. Recovered is sent on the first run without failure after a failure was notified',
+    'zh-cn': '触发该Webhook的事件, 不选择时默认为成功、失败、未验证、恢复。通知过失败后, 第一次未失败时发送恢复'
+  },
+  'Recovered': {
+    'en': 'Recovered',
+    'zh-cn': '恢复'
+  },
+  'Alert after': {
+    'en': 'Alert after',
+    'zh-cn': '失败通知'
+  },
+  'Remind every': {
+    'en': 'Remind every',
+    'zh-cn': '提醒间隔'
+  },
+  'WebhookAlertHelp': {
+    'en': 'Each config counts its failures separately for this Webhook. Notify once after this many consecutive failures (3 by default), then remind every given hours while still failing (0 to never remind)',
+    'zh-cn': '每个配置对该Webhook单独计算失败次数。连续失败该次数后通知一次 (默认3次), 仍然失败时每隔填写的小时数提醒一次 (0则不提醒)'
+  },
+  'Apply to': {
+    'en': 'Apply to',
+    'zh-cn': '适用于'
+  },
+  'WebhookDnsConfPlaceholder': {
+    'en': 'Name of the config, all configs if empty',
+    'zh-cn': '配置的名称, 为空则适用于全部配置'
+  },
+  'MqttURLHelp': {
+    'en': 'Publish the IP and update status of each config and domain as retained messages after every run, leave blank to disable. Use mqtts:// for TLS. The status topic is offline when disconnected',
+    'zh-cn': '每次运行后以保留消息发布每个配置及域名的IP和更新状态, 为空则不发布。TLS 请使用 mqtts://。断开连接时状态主题为 offline'
+  },
+  'Topic prefix': {
+    'en': 'Topic prefix',
+    'zh-cn': '主题前缀'
+  },
+  'MqttTopicPrefixHelp': {
+    'en': 'Topics: prefix/status, prefix/config/state and prefix/config/ipv4/domain/state, the config is the lowercased name',
+    'zh-cn': '主题: 前缀/status、前缀/配置/state 及 前缀/配置/ipv4/域名/state, 配置为小写的名称'
+  },
+  'Discovery prefix': {
+    'en': 'Discovery prefix',
+    'zh-cn': '自动发现前缀'
+  },
+  'MqttDiscoveryPrefixHelp': {
+    'en': 'Home Assistant MQTT discovery prefix, enter - to disable discovery',
+    'zh-cn': 'Home Assistant 的 MQTT 自动发现前缀, 输入 - 则不发布自动发现配置'
+  },
+  'WebhookSecretHelp': {
+    'en': 'Optional. When filled in, each request carries the header X-DDNS-Timestamp and X-DDNS-Signature: sha256=HMAC-SHA256(Secret, timestamp + "." + RequestBody) in hex, so the receiver can verify it',
+    'zh-cn': '可选。填写后每次请求都会带上 X-DDNS-Timestamp 及 X-DDNS-Signature: sha256=HMAC-SHA256(Secret, 时间戳 + "." + RequestBody) 的十六进制, 接收方可据此校验'
+  },
+  'Retries': {
+    'en': 'Retries',
+    'zh-cn': '重试次数'
+  },
+  'Timeout': {
+    'en': 'Timeout',
+    'zh-cn': '超时时间'
+  },
+  'WebhookRetriesHelp': {
+    'en': 'Retry up to 10 times on network errors, 5xx and 429 responses, waiting 1, 2, 4... seconds (at most 30) between attempts. The timeout of each attempt is in seconds, 30 by default',
+    'zh-cn': '网络异常、5xx 及 429 时最多重试 10 次, 间隔 1、2、4... 秒 (最长 30 秒)。超时时间为每次发送的秒数, 默认 30 秒'
+  },
+  'Delivery history': {
+    'en': 'Delivery history',
+    'zh-cn': '发送记录'
+  },
+  'Time': {
+    'en': 'Time',
+    'zh-cn': '时间'
+  },
+  'Channel': {
+    'en': 'Channel',
+    'zh-cn': '渠道'
+  },
+  'Attempts': {
+    'en': 'Attempts',
+    'zh-cn': '尝试次数'
+  },
+  'Status': {
+    'en': 'Status',
+    'zh-cn': '状态'
+  },
+  'Latency': {
+    'en': 'Latency',
+    'zh-cn': '耗时'
+  },
+  'Response': {
+    'en': 'Response',
+    'zh-cn': '返回内容'
+  },
+  'No deliveries yet': {
+    'en': 'No deliveries yet',
+    'zh-cn': '暂无发送记录'
+  },
   'Try it': {
     'en': 'Try it',
     'zh-cn': '模拟测试Webhook'
@@ -197,16 +467,20 @@ const I18N_MAP = {
     'zh-cn': "如不指定匹配正则表达式，将默认使用第一个 IPv6 地址"
   },
   "Ipv4CmdHelp": {
-    'en': "Get IPv4 through command, only use the first matching IPv4 address of standard output(stdout). Such as: ip -4 addr show eth1",
+    'en': 'Get IPv4 through command, only use the first matching IPv4 address of standard output(stdout), or of standard error(stderr) if stdout has none. Such as: ip -4 addr show eth1. If stdout is JSON such as <code>{"ipv4":[],"ipv6":[],"ttl":60}</code>, it is parsed strictly, the array must contain only one address and ttl overrides the TTL. The command times out after 30 seconds, and <code>DDNS_GO_NAME</code> <code>DDNS_GO_DNS</code> <code>DDNS_GO_ADDR_TYPE</code> <code>DDNS_GO_IPV4_DOMAINS</code> etc. are available as environment variables',
     'zh-cn': `
-      通过命令获取IPv4, 仅使用标准输出(stdout)的第一个匹配的 IPv4 地址。如: ip -4 addr show eth1
+      通过命令获取IPv4, 仅使用标准输出(stdout)的第一个匹配的 IPv4 地址, 标准输出中没有时使用标准错误(stderr)中的。如: ip -4 addr show eth1。
+      标准输出为JSON(如 <code>{"ipv4":[],"ipv6":[],"ttl":60}</code>)时将严格解析, 数组中只能有一个地址, ttl 会覆盖TTL。
+      命令超时时间为30秒, 可使用环境变量 <code>DDNS_GO_NAME</code> <code>DDNS_GO_DNS</code> <code>DDNS_GO_ADDR_TYPE</code> <code>DDNS_GO_IPV4_DOMAINS</code> 等。
       <a target="blank" href="https://github.com/jeessy2/ddns-go/wiki/通过命令获取IP参考">点击参考更多</a>
     `
   },
   "Ipv6CmdHelp": {
-    'en': "Get IPv6 through command, only use the first matching IPv6 address of standard output(stdout). Such as: ip -6 addr show eth1",
+    'en': 'Get IPv6 through command, only use the first matching IPv6 address of standard output(stdout), or of standard error(stderr) if stdout has none. Such as: ip -6 addr show eth1. If stdout is JSON such as <code>{"ipv4":[],"ipv6":[],"ttl":60}</code>, it is parsed strictly, the array must contain only one address and ttl overrides the TTL. The command times out after 30 seconds, and <code>DDNS_GO_NAME</code> <code>DDNS_GO_DNS</code> <code>DDNS_GO_ADDR_TYPE</code> <code>DDNS_GO_IPV6_DOMAINS</code> etc. are available as environment variables',
     'zh-cn': `
-      通过命令获取IPv6, 仅使用标准输出(stdout)的第一个匹配的 IPv6 地址。如: ip -6 addr show eth1
+      通过命令获取IPv6, 仅使用标准输出(stdout)的第一个匹配的 IPv6 地址, 标准输出中没有时使用标准错误(stderr)中的。如: ip -6 addr show eth1。
+      标准输出为JSON(如 <code>{"ipv4":[],"ipv6":[],"ttl":60}</code>)时将严格解析, 数组中只能有一个地址, ttl 会覆盖TTL。
+      命令超时时间为30秒, 可使用环境变量 <code>DDNS_GO_NAME</code> <code>DDNS_GO_DNS</code> <code>DDNS_GO_ADDR_TYPE</code> <code>DDNS_GO_IPV6_DOMAINS</code> 等。
       <a target="blank" href="https://github.com/jeessy2/ddns-go/wiki/通过命令获取IP参考">点击参考更多</a>
     `
   },
@@ -226,6 +500,54 @@ const I18N_MAP = {
     'en': 'Bind HTTP requests to a specific network interface (similar to curl --interface). Leave empty to use the default.',
     'zh-cn': '发送 HTTP 请求时绑定指定网卡（类似 curl --interface）。留空则使用默认网卡。'
   },
+  "Verify": {
+    'en': 'Verify',
+    'zh-cn': '生效校验'
+  },
+  "VerifyTimeoutPlaceholder": {
+    'en': 'Timeout (seconds), default 120',
+    'zh-cn': '超时时间(秒), 默认 120'
+  },
+  "VerifyHelp": {
+    'en': 'After a successful update, query the authoritative name servers of the domain until the new IP and the other updated records (SRV/HTTPS/SVCB, LAN hosts) are returned. If they are not returned before the timeout, the result will be <code>unverified</code> and the Webhook will be triggered. The timeout is at most 1/5 of the update interval',
+    'zh-cn': '更新成功后, 直接查询域名的权威DNS服务器, 直到返回新的IP及本次更新的其它记录(SRV/HTTPS/SVCB、局域网主机)。超时仍未返回时, 更新结果为<code>未验证</code>并触发Webhook。超时时间最多为更新间隔的 1/5'
+  },
+  "Drift detection": {
+    'en': 'Drift detection',
+    'zh-cn': '漂移检测'
+  },
+  "Disabled": {
+    'en': 'Disabled',
+    'zh-cn': '不启用'
+  },
+  "By DNS resolver": {
+    'en': 'By DNS resolver',
+    'zh-cn': '通过DNS服务器'
+  },
+  "By authoritative name servers": {
+    'en': 'By authoritative name servers',
+    'zh-cn': '通过权威DNS服务器'
+  },
+  "DriftDetectHelp": {
+    'en': 'When the IP has not changed, resolve the domains through DNS in every cycle. If the record was modified elsewhere, it will be compared with the DNS provider immediately and <code>#{ipv4Drift}</code> <code>#{ipv6Drift}</code> can be used in the Webhook. The DNS resolver (customizable with -dns) may return cached records, so it is only queried once the TTL has passed since the last comparison with the DNS provider (600 seconds when the TTL is not set), the authoritative name servers are recommended',
+    'zh-cn': 'IP未变化时, 每次都通过DNS查询域名的实际解析。如果解析在别处被修改, 将立即与DNS服务商比对, Webhook中可使用 <code>#{ipv4Drift}</code> <code>#{ipv6Drift}</code>。DNS服务器(可通过 -dns 自定义)可能返回缓存的记录, 因此在与DNS服务商比对后超过TTL(未设置时为600秒)才会查询, 推荐使用权威DNS服务器'
+  },
+  "Other records": {
+    'en': 'Other records',
+    'zh-cn': '其它记录'
+  },
+  "SvcbHelp": {
+    'en': 'One record per line, format: <code>type domain priority target [params...]</code>. <code>ipv4hint</code>/<code>ipv6hint</code> are generated from the new IPs and updated together with them. Supported by Cloudflare and TrafficRoute',
+    'zh-cn': '一行一条记录, 格式: <code>类型 域名 优先级 目标 [参数...]</code>。<code>ipv4hint</code>/<code>ipv6hint</code> 由新的IP生成并随IP一起更新。支持 Cloudflare、火山引擎'
+  },
+  "SrvHelp": {
+    'en': 'One record per line, format: <code>domain priority weight target port</code>. The port can be a number, <code>cmd:command</code> (the first number of stdout), <code>upnp:internal port</code> or <code>natpmp:internal port</code> (the external port mapped by the router). The protocol is taken from <code>_tcp</code>/<code>_udp</code> in the domain. Updated together with the IPs, and also when the port changes',
+    'zh-cn': '一行一条记录, 格式: <code>域名 优先级 权重 目标 端口</code>。端口可为数字、<code>cmd:命令</code>(标准输出中的第一个数字)、<code>upnp:内部端口</code>或<code>natpmp:内部端口</code>(路由器映射的外部端口)。协议取自域名中的 <code>_tcp</code>/<code>_udp</code>。随IP一起更新, 端口变化时也会更新'
+  },
+  "TxtRecordHelp": {
+    'en': 'Heartbeat TXT record written after every successful update cycle, so external monitors can alert on stale timestamps. The first line is the domain, the second is the content template (supports the Webhook variables, e.g. <code>#{timestamp}</code> Unix timestamp, <code>#{version}</code> ddns-go version). Supported by all DNS providers except Aliesa, Callback, Dynadot, EdgeOne, Eranet, NameCheap, NameSilo, Nowcn and Spaceship',
+    'zh-cn': '每次成功更新周期后写入的心跳TXT记录, 外部监控可在时间戳过期时告警。第一行为域名, 第二行为内容模板(支持Webhook中的变量, 如 <code>#{timestamp}</code> Unix时间戳, <code>#{version}</code> ddns-go版本)。阿里云ESA、Callback、Dynadot、EdgeOne、Eranet、NameCheap、NameSilo、时代互联、Spaceship 不支持'
+  },
   "Login": {
     'en': 'Login',
     'zh-cn': '登录'
@@ -0,0 +1,221 @@
+package util
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// dnsQueryTimeout 单次DNS查询的超时时间
+const dnsQueryTimeout = 5 * time.Second
+
+// QueryDNS 直接向指定的DNS服务器发送查询, 返回应答中与查询类型一致的记录值。
+// network 可为 udp/udp4/udp6, 应答被截断时会使用对应的 tcp 重试。
+// server 可不带端口, 默认 53。
+//
+// 返回值格式: A/AAAA 为IP地址, NS/CNAME 为不带末尾点的域名, TXT 为拼接后的字符串,
+// SRV 为 "优先级 权重 端口 目标", HTTPS/SVCB 为 "优先级 目标 参数..." (见 [svcbValue]),
+// 其它类型为 [dnsmessage.ResourceBody.GoString] 的结果。
+func QueryDNS(network, server, name string, qtype dnsmessage.Type) ([]string, error) {
+	if _, _, err := net.SplitHostPort(server); err != nil {
+		server = net.JoinHostPort(strings.Trim(server, "[]"), "53")
+	}
+
+	msg, err := exchangeDNS(network, server, name, qtype)
+	if err != nil {
+		return nil, err
+	}
+	if msg.Header.Truncated {
+		msg, err = exchangeDNS(strings.Replace(network, "udp", "tcp", 1), server, name, qtype)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if msg.Header.RCode != dnsmessage.RCodeSuccess {
+		return nil, fmt.Errorf("%s %s: %s", name, qtype, msg.Header.RCode)
+	}
+
+	var values []string
+	for _, answer := range msg.Answers {
+		if answer.Header.Type != qtype {
+			continue
+		}
+		switch body := answer.Body.(type) {
+		case *dnsmessage.AResource:
+			values = append(values, net.IP(body.A[:]).String())
+		case *dnsmessage.AAAAResource:
+			values = append(values, net.IP(body.AAAA[:]).String())
+		case *dnsmessage.NSResource:
+			values = append(values, strings.TrimSuffix(body.NS.String(), "."))
+		case *dnsmessage.CNAMEResource:
+			values = append(values, strings.TrimSuffix(body.CNAME.String(), "."))
+		case *dnsmessage.TXTResource:
+			values = append(values, strings.Join(body.TXT, ""))
+		case *dnsmessage.SRVResource:
+			values = append(values, fmt.Sprintf("%d %d %d %s", body.Priority, body.Weight, body.Port, dnsNameValue(body.Target)))
+		case *dnsmessage.SVCBResource:
+			values = append(values, svcbValue(body))
+		case *dnsmessage.HTTPSResource:
+			values = append(values, svcbValue(&body.SVCBResource))
+		default:
+			values = append(values, body.GoString())
+		}
+	}
+	return values, nil
+}
+
+// dnsNameValue 不带末尾点的域名, 根域名为 "."
+func dnsNameValue(name dnsmessage.Name) string {
+	if value := strings.TrimSuffix(name.String(), "."); value != "" {
+		return value
+	}
+	return "."
+}
+
+// svcbValue 以区域文件的格式输出, 如 1 . alpn=h2,h3 port=443 ipv4hint=192.0.2.1。
+// 仅转换 alpn/no-default-alpn/port/ipv4hint/ipv6hint, 其它参数为 keyN
+func svcbValue(r *dnsmessage.SVCBResource) string {
+	parts := []string{strconv.Itoa(int(r.Priority)), dnsNameValue(r.Target)}
+	for _, param := range r.Params {
+		switch param.Key {
+		case dnsmessage.SVCParamALPN:
+			var alpn []string
+			for value := param.Value; len(value) > 0 && int(value[0]) < len(value); value = value[1+value[0]:] {
+				alpn = append(alpn, string(value[1:1+value[0]]))
+			}
+			parts = append(parts, "alpn="+strings.Join(alpn, ","))
+		case dnsmessage.SVCParamNoDefaultALPN:
+			parts = append(parts, "no-default-alpn")
+		case dnsmessage.SVCParamPort:
+			if len(param.Value) == 2 {
+				parts = append(parts, "port="+strconv.Itoa(int(binary.BigEndian.Uint16(param.Value))))
+			}
+		case dnsmessage.SVCParamIPv4Hint, dnsmessage.SVCParamIPv6Hint:
+			size, key := net.IPv4len, "ipv4hint="
+			if param.Key == dnsmessage.SVCParamIPv6Hint {
+				size, key = net.IPv6len, "ipv6hint="
+			}
+			var ips []string
+			for value := param.Value; len(value) >= size; value = value[size:] {
+				ips = append(ips, net.IP(value[:size]).String())
+			}
+			parts = append(parts, key+strings.Join(ips, ","))
+		default:
+			parts = append(parts, fmt.Sprintf("key%d", param.Key))
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// exchangeDNS 发送一次查询并解析应答
+func exchangeDNS(network, server, name string, qtype dnsmessage.Type) (*dnsmessage.Message, error) {
+	if !strings.HasSuffix(name, ".") {
+		name += "."
+	}
+	qname, err := dnsmessage.NewName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	id := uint16(rand.N(1 << 16))
+	query := dnsmessage.Message{
+		Header: dnsmessage.Header{ID: id},
+		Questions: []dnsmessage.Question{
+			{Name: qname, Type: qtype, Class: dnsmessage.ClassINET},
+		},
+	}
+	packed, err := query.Pack()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), dnsQueryTimeout)
+	defer cancel()
+	conn, err := dialer.DialContext(ctx, network, server)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	deadline, _ := ctx.Deadline()
+	conn.SetDeadline(deadline)
+
+	var resp []byte
+	if strings.HasPrefix(network, "tcp") {
+		// TCP 需添加2字节长度前缀
+		buf := binary.BigEndian.AppendUint16(make([]byte, 0, len(packed)+2), uint16(len(packed)))
+		if _, err = conn.Write(append(buf, packed...)); err != nil {
+			return nil, err
+		}
+		var length [2]byte
+		if _, err = io.ReadFull(conn, length[:]); err != nil {
+			return nil, err
+		}
+		resp = make([]byte, binary.BigEndian.Uint16(length[:]))
+		if _, err = io.ReadFull(conn, resp); err != nil {
+			return nil, err
+		}
+	} else {
+		if _, err = conn.Write(packed); err != nil {
+			return nil, err
+		}
+		resp = make([]byte, 4096)
+		n, err := conn.Read(resp)
+		if err != nil {
+			return nil, err
+		}
+		resp = resp[:n]
+	}
+
+	var msg dnsmessage.Message
+	if err = msg.Unpack(resp); err != nil {
+		return nil, err
+	}
+	if msg.Header.ID != id || !msg.Header.Response {
+		return nil, errors.New("invalid DNS response")
+	}
+	return &msg, nil
+}
+
+// LookupNS 使用当前的DNS服务器(可通过 -dns 自定义)查询域名的NS记录
+func LookupNS(zone string) ([]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dnsQueryTimeout)
+	defer cancel()
+
+	nss, err := dialer.Resolver.LookupNS(ctx, zone)
+	if err != nil {
+		return nil, err
+	}
+
+	hosts := make([]string, 0, len(nss))
+	for _, ns := range nss {
+		hosts = append(hosts, strings.TrimSuffix(ns.Host, "."))
+	}
+	return hosts, nil
+}
+
+// LookupIP 使用当前的DNS服务器(可通过 -dns 自定义)查询域名的IP地址。
+// network 为 ip4 或 ip6
+func LookupIP(network, host string) ([]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dnsQueryTimeout)
+	defer cancel()
+
+	ips, err := dialer.Resolver.LookupIP(ctx, network, host)
+	if err != nil {
+		return nil, err
+	}
+
+	addrs := make([]string, 0, len(ips))
+	for _, ip := range ips {
+		addrs = append(addrs, ip.String())
+	}
+	return addrs, nil
+}
@@ -0,0 +1,100 @@
+package util
+
+import (
+	"net"
+	"reflect"
+	"testing"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// startTestDNSServer 启动一个仅用于测试的UDP DNS服务器
+func startTestDNSServer(t *testing.T, answer func(q dnsmessage.Question) []dnsmessage.Resource) string {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	go func() {
+		buf := make([]byte, 512)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			var req dnsmessage.Message
+			if err := req.Unpack(buf[:n]); err != nil {
+				continue
+			}
+			resp := dnsmessage.Message{
+				Header:    dnsmessage.Header{ID: req.Header.ID, Response: true, Authoritative: true},
+				Questions: req.Questions,
+				Answers:   answer(req.Questions[0]),
+			}
+			packed, _ := resp.Pack()
+			conn.WriteTo(packed, addr)
+		}
+	}()
+
+	return conn.LocalAddr().String()
+}
+
+func TestQueryDNS(t *testing.T) {
+	server := startTestDNSServer(t, func(q dnsmessage.Question) []dnsmessage.Resource {
+		header := dnsmessage.ResourceHeader{Name: q.Name, Type: q.Type, Class: dnsmessage.ClassINET, TTL: 60}
+		switch q.Type {
+		case dnsmessage.TypeA:
+			return []dnsmessage.Resource{
+				{Header: header, Body: &dnsmessage.AResource{A: [4]byte{192, 0, 2, 1}}},
+				{Header: header, Body: &dnsmessage.AResource{A: [4]byte{192, 0, 2, 2}}},
+			}
+		case dnsmessage.TypeTXT:
+			return []dnsmessage.Resource{
+				{Header: header, Body: &dnsmessage.TXTResource{TXT: []string{"ts=1;", "v4=192.0.2.1"}}},
+			}
+		case dnsmessage.TypeSRV:
+			return []dnsmessage.Resource{
+				{Header: header, Body: &dnsmessage.SRVResource{Priority: 0, Weight: 5, Port: 25565, Target: dnsmessage.MustNewName("mc.example.com.")}},
+			}
+		case dnsmessage.TypeHTTPS:
+			return []dnsmessage.Resource{
+				{Header: header, Body: &dnsmessage.HTTPSResource{SVCBResource: dnsmessage.SVCBResource{
+					Priority: 1,
+					Target:   dnsmessage.MustNewName("."),
+					Params: []dnsmessage.SVCParam{
+						{Key: dnsmessage.SVCParamALPN, Value: []byte("\x02h2\x02h3")},
+						{Key: dnsmessage.SVCParamPort, Value: []byte{0x01, 0xbb}},
+						{Key: dnsmessage.SVCParamIPv4Hint, Value: []byte{192, 0, 2, 1}},
+						{Key: dnsmessage.SVCParamECH, Value: []byte{0x01}},
+					},
+				}}},
+			}
+		}
+		return nil
+	})
+
+	tests := []struct {
+		name     string
+		qtype    dnsmessage.Type
+		expected []string
+	}{
+		{"A", dnsmessage.TypeA, []string{"192.0.2.1", "192.0.2.2"}},
+		{"TXT", dnsmessage.TypeTXT, []string{"ts=1;v4=192.0.2.1"}},
+		{"SRV", dnsmessage.TypeSRV, []string{"0 5 25565 mc.example.com"}},
+		{"HTTPS", dnsmessage.TypeHTTPS, []string{"1 . alpn=h2,h3 port=443 ipv4hint=192.0.2.1 key5"}},
+		{"No answer", dnsmessage.TypeAAAA, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, err := QueryDNS("udp", server, "www.example.com", tt.qtype)
+			if err != nil {
+				t.Fatalf("Expected nil error, got %v", err)
+			}
+			if !reflect.DeepEqual(values, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, values)
+			}
+		})
+	}
+}
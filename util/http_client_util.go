@@ -14,6 +14,11 @@ var dialer = &net.Dialer{
 	KeepAlive: 30 * time.Second,
 }
 
+// DialContext 使用全局的 dialer 建立连接, 域名使用 -dns 指定的DNS服务器解析
+func DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	return dialer.DialContext(ctx, network, address)
+}
+
 var defaultTransport = &http.Transport{
 	// from http.DefaultTransport
 	Proxy: http.ProxyFromEnvironment,
@@ -34,7 +39,7 @@ var insecureSkipVerify bool
 func CreateHTTPClient() *http.Client {
 	return &http.Client{
 		Timeout:   30 * time.Second,
-		Transport: defaultTransport,
+		Transport: &debugTransport{defaultTransport},
 	}
 }
 
@@ -88,7 +93,7 @@ func CreateHTTPClientWithInterface(ifaceName string) *http.Client {
 	}
 	return &http.Client{
 		Timeout:   30 * time.Second,
-		Transport: transport,
+		Transport: &debugTransport{transport},
 	}
 }
 
@@ -129,16 +134,21 @@ func CreateNoProxyHTTPClient(network string) *http.Client {
 	if network == "tcp6" {
 		return &http.Client{
 			Timeout:   30 * time.Second,
-			Transport: noProxyTcp6Transport,
+			Transport: &debugTransport{noProxyTcp6Transport},
 		}
 	}
 
 	return &http.Client{
 		Timeout:   30 * time.Second,
-		Transport: noProxyTcp4Transport,
+		Transport: &debugTransport{noProxyTcp4Transport},
 	}
 }
 
+// IsInsecureSkipVerify 是否跳过证书验证
+func IsInsecureSkipVerify() bool {
+	return insecureSkipVerify
+}
+
 // SetInsecureSkipVerify 将所有 http.Transport 的 InsecureSkipVerify 设置为 true
 func SetInsecureSkipVerify() {
 	insecureSkipVerify = true
@@ -0,0 +1,165 @@
+package util
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// httpDebugBodyLimit debug 日志中请求体及响应体的最大字节数
+const httpDebugBodyLimit = 4096
+
+// sensitiveNames 名称包含以下内容的请求头、参数及JSON字段会被替换为 ***
+var sensitiveNames = []string{"auth", "token", "secret", "password", "passwd", "key", "sign", "cookie", "credential", "session"}
+
+var (
+	sensitiveJSON = regexp.MustCompile(`(?i)("[^"]*(?:` + strings.Join(sensitiveNames, "|") + `)[^"]*"\s*:\s*)"(?:[^"\\]|\\.)*"`)
+	sensitiveForm = regexp.MustCompile(`(?i)((?:^|&)[^=&]*(?:` + strings.Join(sensitiveNames, "|") + `)[^=&]*=)[^&]*`)
+)
+
+// debugTransport 日志级别为 debug 时, 输出脱敏后的HTTP请求及响应
+type debugTransport struct {
+	next http.RoundTripper
+}
+
+func (t *debugTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !IsDebug() {
+		return t.next.RoundTrip(req)
+	}
+
+	var reqBody []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		var err error
+		reqBody, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = io.NopCloser(bytes.NewReader(reqBody))
+	}
+	reqURL := sanitizeURL(req.URL)
+	if req.Context().Value(redactURLKey{}) != nil {
+		reqURL = RedactURL(req.URL)
+	}
+	attrs := []slog.Attr{slog.String("action", "http"), slog.String("method", req.Method), slog.String("url", reqURL)}
+	LogAttrs(slog.LevelDebug, attrs, "HTTP请求:\n%s", dumpHTTP(req.Method+" "+reqURL, req.Header, reqBody))
+
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		LogAttrs(slog.LevelDebug, attrs, "HTTP请求失败! 耗时: %s, 异常信息: %s", time.Since(start).Round(time.Millisecond), err)
+		return resp, err
+	}
+
+	// 只读取前 httpDebugBodyLimit 字节, 其余部分仍由调用者读取
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, httpDebugBodyLimit))
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(respBody), resp.Body), resp.Body}
+	attrs = append(attrs, slog.Int("httpStatus", resp.StatusCode))
+	LogAttrs(slog.LevelDebug, attrs, "HTTP响应, 耗时: %s\n%s", time.Since(start).Round(time.Millisecond), dumpHTTP(resp.Proto+" "+resp.Status, resp.Header, respBody))
+	return resp, nil
+}
+
+// dumpHTTP 首行、请求头及请求体, 敏感信息已替换为 ***
+func dumpHTTP(firstLine string, header http.Header, body []byte) string {
+	var sb strings.Builder
+	sb.WriteString(firstLine + "\n")
+	keys := make([]string, 0, len(header))
+	for key := range header {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		value := strings.Join(header[key], ", ")
+		if isSensitive(key) {
+			value = "***"
+		}
+		fmt.Fprintf(&sb, "%s: %s\n", key, value)
+	}
+	if len(body) > 0 {
+		sb.WriteString("\n" + sanitizeBody(body))
+		if len(body) >= httpDebugBodyLimit {
+			sb.WriteString("...")
+		}
+	}
+	return strings.TrimRight(sb.String(), "\n")
+}
+
+// sanitizeURL 隐藏URL中的用户名密码及敏感参数
+func sanitizeURL(u *url.URL) string {
+	u2 := *u
+	if u2.User != nil {
+		u2.User = url.User("***")
+	}
+	if u2.RawQuery != "" {
+		query := u2.Query()
+		for key := range query {
+			if isSensitive(key) {
+				query.Set(key, "***")
+			}
+		}
+		u2.RawQuery = query.Encode()
+	}
+	return u2.String()
+}
+
+// redactURLKey 请求的 context 中有该值时, debug 日志中隐藏URL的路径及参数
+type redactURLKey struct{}
+
+// WithRedactedURL 标记请求的URL路径中可能包含Token, 如Webhook及通知渠道
+func WithRedactedURL(req *http.Request) *http.Request {
+	return req.WithContext(context.WithValue(req.Context(), redactURLKey{}, true))
+}
+
+// RedactURL 只保留URL的协议及主机名, 路径及参数替换为 ***, 如 Telegram 的 /bot<token>/sendMessage
+func RedactURL(u *url.URL) string {
+	s := (&url.URL{Scheme: u.Scheme, Host: u.Host}).String()
+	if u.Path != "" && u.Path != "/" {
+		s += "/***"
+	}
+	if u.RawQuery != "" {
+		s += "?***"
+	}
+	return s
+}
+
+// RedactURLError 将请求异常中的URL替换为 RedactURL 的结果
+func RedactURLError(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		if u, parseErr := url.Parse(urlErr.URL); parseErr == nil {
+			urlErr.URL = RedactURL(u)
+		}
+	}
+	return err
+}
+
+// sanitizeBody 隐藏JSON及表单中的敏感字段
+func sanitizeBody(body []byte) string {
+	s := string(body)
+	if trimmed := strings.TrimSpace(s); strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[") {
+		return sensitiveJSON.ReplaceAllString(s, `$1"***"`)
+	}
+	return sensitiveForm.ReplaceAllString(s, "$1***")
+}
+
+func isSensitive(name string) bool {
+	name = strings.ToLower(name)
+	for _, s := range sensitiveNames {
+		if strings.Contains(name, s) {
+			return true
+		}
+	}
+	return false
+}
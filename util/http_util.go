@@ -2,7 +2,6 @@ package util
 
 import (
 	"encoding/json"
-	"fmt"
 	"io"
 	"net/http"
 )
@@ -38,8 +37,18 @@ func GetHTTPResponseOrg(resp *http.Response, err error) ([]byte, error) {
 
 	// 300及以上状态码都算异常
 	if resp.StatusCode >= 300 {
-		err = fmt.Errorf("%s", LogStr("返回内容: %s ,返回状态码: %d", string(body), resp.StatusCode))
+		err = &HTTPStatusError{StatusCode: resp.StatusCode, Body: string(body)}
 	}
 
 	return body, err
 }
+
+// HTTPStatusError 返回的状态码为300及以上
+type HTTPStatusError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *HTTPStatusError) Error() string {
+	return LogStr("返回内容: %s ,返回状态码: %d", e.Body, e.StatusCode)
+}
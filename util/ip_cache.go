@@ -3,15 +3,17 @@ package util
 import (
 	"os"
 	"strconv"
+	"time"
 )
 
 const IPCacheTimesENV = "DDNS_IP_CACHE_TIMES"
 
 // IpCache 上次IP缓存
 type IpCache struct {
-	Addr          string // 缓存地址
-	Times         int    // 剩余次数
-	TimesFailedIP int    // 获取ip失败的次数
+	Addr          string    // 缓存地址
+	Times         int       // 剩余次数
+	TimesFailedIP int       // 获取ip失败的次数
+	ComparedAt    time.Time // 上次与DNS服务商比对的时间
 }
 
 var ForceCompareGlobal = true
@@ -28,6 +30,7 @@ func (d *IpCache) Check(newAddr string) bool {
 		}
 		d.Addr = newAddr
 		d.Times = IPCacheTimes + 1
+		d.ComparedAt = time.Now()
 		return true
 	}
 	d.Addr = newAddr
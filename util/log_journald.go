@@ -0,0 +1,82 @@
+package util
+
+import (
+	"bytes"
+	"encoding/binary"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// journaldSocket systemd-journald 原生协议的socket
+var journaldSocket = "/run/systemd/journal/socket"
+
+// JournaldSink 使用 journald 原生协议发送, 字段名为 DDNS_ 加大写的字段名, 如 DDNS_CONFIG
+type JournaldSink struct {
+	conn net.Conn
+}
+
+// NewJournaldSink 连接 journald
+func NewJournaldSink() (*JournaldSink, error) {
+	conn, err := net.Dial("unixgram", journaldSocket)
+	if err != nil {
+		return nil, err
+	}
+	return &JournaldSink{conn: conn}, nil
+}
+
+// Write 发送一条日志
+func (sink *JournaldSink) Write(entry LogEntry) error {
+	_, err := sink.conn.Write(journaldMessage(entry))
+	return err
+}
+
+// Close 关闭连接
+func (sink *JournaldSink) Close() error {
+	return sink.conn.Close()
+}
+
+// journaldMessage 每行一个字段 KEY=value, 值包含换行时使用 KEY\n + 64位小端长度 + value
+func journaldMessage(entry LogEntry) []byte {
+	var buf bytes.Buffer
+	writeField := func(key string, value string) {
+		if !strings.Contains(value, "\n") {
+			buf.WriteString(key + "=" + value + "\n")
+			return
+		}
+		buf.WriteString(key + "\n")
+		binary.Write(&buf, binary.LittleEndian, uint64(len(value)))
+		buf.WriteString(value + "\n")
+	}
+
+	writeField("MESSAGE", entry.Message)
+	writeField("PRIORITY", strconv.Itoa(entry.severity()))
+	writeField("SYSLOG_IDENTIFIER", "ddns-go")
+	for key, value := range entry.Fields {
+		if value != "" {
+			writeField("DDNS_"+journaldFieldName(key), value)
+		}
+	}
+	return buf.Bytes()
+}
+
+// journaldFieldName 字段名只能包含大写字母、数字及下划线, 如 recordType 转为 RECORD_TYPE
+func journaldFieldName(key string) string {
+	var sb strings.Builder
+	for i, r := range key {
+		switch {
+		case r >= 'A' && r <= 'Z':
+			if i > 0 {
+				sb.WriteByte('_')
+			}
+			sb.WriteRune(r)
+		case r >= 'a' && r <= 'z':
+			sb.WriteRune(r - 'a' + 'A')
+		case r >= '0' && r <= '9':
+			sb.WriteRune(r)
+		default:
+			sb.WriteByte('_')
+		}
+	}
+	return sb.String()
+}
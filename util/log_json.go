@@ -0,0 +1,92 @@
+package util
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// jsonLogBackups 轮转时保留的旧文件数量, 如 ddns-go.log.1 ~ ddns-go.log.3
+const jsonLogBackups = 3
+
+// JSONFileSink 以JSON Lines写入文件, 超过 MaxSize 后轮转
+type JSONFileSink struct {
+	Path string
+	// 单个文件的最大字节数, 为0则不轮转
+	MaxSize int64
+
+	file *os.File
+	size int64
+}
+
+// NewJSONFileSink 打开或创建日志文件
+func NewJSONFileSink(path string, maxSize int64) (*JSONFileSink, error) {
+	sink := &JSONFileSink{Path: path, MaxSize: maxSize}
+	if err := sink.open(); err != nil {
+		return nil, err
+	}
+	return sink, nil
+}
+
+func (sink *JSONFileSink) open() error {
+	file, err := os.OpenFile(sink.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	sink.file, sink.size = file, info.Size()
+	return nil
+}
+
+// Write 写入一行, 字段与 time/level/msg 同级
+func (sink *JSONFileSink) Write(entry LogEntry) error {
+	line := make(map[string]string, len(entry.Fields)+2)
+	for key, value := range entry.Fields {
+		if value != "" {
+			line[key] = value
+		}
+	}
+	line["time"] = entry.Time.Format(time.RFC3339Nano)
+	line["level"] = strings.ToLower(entry.Level.String())
+	line["msg"] = entry.Message
+	byt, err := json.Marshal(line)
+	if err != nil {
+		return err
+	}
+	byt = append(byt, '\n')
+
+	var rotateErr error
+	if sink.MaxSize > 0 && sink.size > 0 && sink.size+int64(len(byt)) > sink.MaxSize {
+		// 轮转失败时仍写入当前文件
+		rotateErr = sink.rotate()
+	}
+	n, err := sink.file.Write(byt)
+	sink.size += int64(n)
+	return errors.Join(rotateErr, err)
+}
+
+// rotate 依次重命名旧文件, 并创建新文件。
+// 重命名失败时重新打开原文件继续写入, 下次写入时再轮转
+func (sink *JSONFileSink) rotate() error {
+	sink.file.Close()
+	for i := jsonLogBackups - 1; i > 0; i-- {
+		os.Rename(fmt.Sprintf("%s.%d", sink.Path, i), fmt.Sprintf("%s.%d", sink.Path, i+1))
+	}
+	renameErr := os.Rename(sink.Path, sink.Path+".1")
+	if err := sink.open(); err != nil {
+		return err
+	}
+	return renameErr
+}
+
+// Close 关闭文件
+func (sink *JSONFileSink) Close() error {
+	return sink.file.Close()
+}
@@ -0,0 +1,130 @@
+package util
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"sync"
+	"time"
+)
+
+// LogEntry 写入日志输出的一条日志
+type LogEntry struct {
+	Time    time.Time
+	Level   slog.Level
+	Message string
+	// 结构化字段, 如 config/provider/domain/recordType/action/httpStatus/status
+	Fields map[string]string
+}
+
+// LogSink 日志输出, 如 syslog、journald、JSON文件
+type LogSink interface {
+	Write(entry LogEntry) error
+	Close() error
+}
+
+// logQueueSize 等待写入日志输出的最大条数, 超过时丢弃
+const logQueueSize = 1000
+
+// logSinks 日志在后台写入, 日志输出较慢或不可用时不阻塞调用者
+var logSinks = struct {
+	sync.Mutex
+	sinks []LogSink
+	// 等待写入的日志, 有日志输出时不为 nil
+	queue chan LogEntry
+	// 后台写入结束时关闭
+	done chan struct{}
+	// 队列已满时丢弃的条数
+	dropped int
+}{}
+
+// AddLogSink 添加日志输出
+func AddLogSink(sink LogSink) {
+	logSinks.Lock()
+	defer logSinks.Unlock()
+	logSinks.sinks = append(logSinks.sinks, sink)
+	if logSinks.queue == nil {
+		logSinks.queue = make(chan LogEntry, logQueueSize)
+		logSinks.done = make(chan struct{})
+		go runLogSinks(logSinks.queue, logSinks.done)
+	}
+}
+
+// CloseLogSinks 写入队列中剩余的日志后关闭所有日志输出
+func CloseLogSinks() {
+	logSinks.Lock()
+	queue, done := logSinks.queue, logSinks.done
+	logSinks.queue = nil
+	logSinks.Unlock()
+	if queue != nil {
+		close(queue)
+		<-done
+	}
+
+	logSinks.Lock()
+	defer logSinks.Unlock()
+	for _, sink := range logSinks.sinks {
+		sink.Close()
+	}
+	logSinks.sinks = nil
+}
+
+// Record 仅写入日志输出, 不在控制台及网页中显示, 用于记录结构化的结果
+func Record(fields map[string]string, key string, args ...interface{}) {
+	if !logger.Enabled(context.Background(), slog.LevelInfo) {
+		return
+	}
+	writeSinks(LogEntry{Time: time.Now(), Message: LogStr(key, args...), Fields: fields})
+}
+
+// writeSinks 加入写入队列, 队列已满时丢弃
+func writeSinks(entry LogEntry) {
+	logSinks.Lock()
+	defer logSinks.Unlock()
+	if logSinks.queue == nil {
+		return
+	}
+
+	select {
+	case logSinks.queue <- entry:
+	default:
+		logSinks.dropped++
+	}
+}
+
+// runLogSinks 在后台写入所有日志输出, 出错时输出到 stderr
+func runLogSinks(queue <-chan LogEntry, done chan<- struct{}) {
+	defer close(done)
+	for entry := range queue {
+		logSinks.Lock()
+		sinks := slices.Clone(logSinks.sinks)
+		dropped := logSinks.dropped
+		logSinks.dropped = 0
+		logSinks.Unlock()
+
+		if dropped > 0 {
+			fmt.Fprintf(os.Stderr, "log sink: dropped %d entries\n", dropped)
+		}
+		for _, sink := range sinks {
+			if err := sink.Write(entry); err != nil {
+				fmt.Fprintf(os.Stderr, "log sink: %s\n", err)
+			}
+		}
+	}
+}
+
+// severity 日志级别对应的 syslog 严重程度, journald 的 PRIORITY 与之相同
+func (entry LogEntry) severity() int {
+	switch {
+	case entry.Level >= slog.LevelError:
+		return 3
+	case entry.Level >= slog.LevelWarn:
+		return 4
+	case entry.Level >= slog.LevelInfo:
+		return 6
+	default:
+		return 7
+	}
+}
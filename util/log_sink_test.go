@@ -0,0 +1,277 @@
+package util
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testSink 记录写入的日志
+type testSink struct {
+	entries []LogEntry
+}
+
+func (sink *testSink) Write(entry LogEntry) error {
+	sink.entries = append(sink.entries, entry)
+	return nil
+}
+
+func (sink *testSink) Close() error { return nil }
+
+func TestLogSinkFields(t *testing.T) {
+	sink := &testSink{}
+	AddLogSink(sink)
+	t.Cleanup(CloseLogSinks)
+
+	LogAttrs(slog.LevelInfo, []slog.Attr{slog.String("config", "home"), slog.String("provider", "cloudflare")}, "监听 %s", ":9876")
+	Record(map[string]string{"domain": "www.example.com", "provider": "alidns"}, "监听 %s", ":9877")
+	Log("监听 %s", ":9878")
+
+	// 等待后台写入
+	CloseLogSinks()
+	if len(sink.entries) != 3 {
+		t.Fatalf("Expected 3 entries, got %d", len(sink.entries))
+	}
+	if f := sink.entries[0].Fields; f["config"] != "home" || f["provider"] != "cloudflare" || sink.entries[0].Message != LogStr("监听 %s", ":9876") {
+		t.Errorf("Unexpected entry %+v", sink.entries[0])
+	}
+	if f := sink.entries[1].Fields; f["provider"] != "alidns" || f["domain"] != "www.example.com" {
+		t.Errorf("Unexpected entry %+v", sink.entries[1])
+	}
+	if len(sink.entries[2].Fields) != 0 {
+		t.Errorf("Expected no fields, got %v", sink.entries[2].Fields)
+	}
+}
+
+// blockingSink 写入时阻塞, 直到 release 关闭
+type blockingSink struct {
+	testSink
+	release chan struct{}
+}
+
+func (sink *blockingSink) Write(entry LogEntry) error {
+	<-sink.release
+	return sink.testSink.Write(entry)
+}
+
+func TestLogSinkNonBlocking(t *testing.T) {
+	sink := &blockingSink{release: make(chan struct{})}
+	AddLogSink(sink)
+	t.Cleanup(CloseLogSinks)
+
+	// 日志输出阻塞时不阻塞调用者, 超过队列长度的被丢弃
+	start := time.Now()
+	for i := 0; i < logQueueSize+10; i++ {
+		Record(nil, "监听 %s", ":9876")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected writes not to block, took %s", elapsed)
+	}
+	close(sink.release)
+	CloseLogSinks()
+	if n := len(sink.entries); n < logQueueSize || n > logQueueSize+1 {
+		t.Errorf("Expected about %d entries, got %d", logQueueSize, n)
+	}
+}
+
+func TestJSONFileSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ddns-go.log")
+	sink, err := NewJSONFileSink(path, 200)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sink.Close()
+
+	for i := 0; i < 5; i++ {
+		err = sink.Write(LogEntry{Time: time.Now(), Message: strings.Repeat("x", 50), Fields: map[string]string{"domain": "www.example.com", "status": ""}})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	byt, _ := os.ReadFile(path)
+	line := map[string]string{}
+	if err = json.Unmarshal(bytes.Split(byt, []byte("\n"))[0], &line); err != nil {
+		t.Fatal(err)
+	}
+	if line["domain"] != "www.example.com" || line["msg"] != strings.Repeat("x", 50) || line["time"] == "" {
+		t.Errorf("Unexpected line %v", line)
+	}
+	if _, ok := line["status"]; ok {
+		t.Error("Expected empty fields to be omitted")
+	}
+	for _, name := range []string{path, path + ".1", path + ".2"} {
+		if info, err := os.Stat(name); err != nil || info.Size() > 200 {
+			t.Errorf("Expected rotated file %s within 200 bytes: %v", name, err)
+		}
+	}
+}
+
+func TestJSONFileSinkRotateFailed(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ddns-go.log")
+	// 旧文件都为非空目录时重命名失败
+	for i := 1; i <= jsonLogBackups; i++ {
+		if err := os.MkdirAll(filepath.Join(fmt.Sprintf("%s.%d", path, i), "x"), 0700); err != nil {
+			t.Fatal(err)
+		}
+	}
+	sink, err := NewJSONFileSink(path, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sink.Close()
+
+	for i := 0; i < 3; i++ {
+		err = sink.Write(LogEntry{Time: time.Now(), Message: strings.Repeat("x", 50)})
+		if i > 0 && err == nil {
+			t.Error("Expected rotate error")
+		}
+	}
+	byt, _ := os.ReadFile(path)
+	if n := bytes.Count(byt, []byte("\n")); n != 3 {
+		t.Errorf("Expected 3 lines written after rotate failed, got %d", n)
+	}
+}
+
+func TestSyslogMessage(t *testing.T) {
+	entry := LogEntry{
+		Time:    time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Message: "Updated domain www.example.com successfully!",
+		Fields:  map[string]string{"provider": "cloudflare", "config": `a"b]`, "status": ""},
+	}
+	expected := regexp.MustCompile(`^<30>1 2024-01-02T03:04:05.000000Z \S+ ddns-go \d+ - \[ddns-go@32473 config="a\\"b\\]" provider="cloudflare"\] Updated domain www.example.com successfully!$`)
+	if msg := syslogMessage(entry); !expected.MatchString(msg) {
+		t.Errorf("Unexpected message %s", msg)
+	}
+	nano := time.Date(2024, 1, 2, 3, 4, 5, 123456789, time.FixedZone("", 8*3600))
+	if msg := syslogMessage(LogEntry{Time: nano, Message: "msg"}); !strings.HasPrefix(msg, "<30>1 2024-01-02T03:04:05.123456+08:00 ") {
+		t.Errorf("Expected at most 6 fractional digits, got %s", msg)
+	}
+	if msg := syslogMessage(LogEntry{Time: entry.Time, Message: "msg"}); !strings.Contains(msg, " - - msg") {
+		t.Errorf("Expected nil structured data, got %s", msg)
+	}
+	if msg := syslogMessage(LogEntry{Time: entry.Time, Level: slog.LevelError, Message: "msg"}); !strings.HasPrefix(msg, "<27>1 ") {
+		t.Errorf("Expected severity error, got %s", msg)
+	}
+}
+
+func TestSyslogSink(t *testing.T) {
+	udp, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer udp.Close()
+	sink, err := NewSyslogSink("udp://" + udp.LocalAddr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sink.Close()
+	sink.Write(LogEntry{Time: time.Now(), Message: "hello"})
+	buf := make([]byte, 1024)
+	udp.SetReadDeadline(time.Now().Add(time.Second))
+	n, _, err := udp.ReadFrom(buf)
+	if err != nil || !strings.HasSuffix(string(buf[:n]), " - hello") {
+		t.Errorf("Unexpected datagram %q: %v", buf[:n], err)
+	}
+
+	tcp, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tcp.Close()
+	sink, err = NewSyslogSink("tcp://" + tcp.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sink.Close()
+	conn, err := tcp.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	sink.Write(LogEntry{Time: time.Now(), Message: "hello"})
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	frame, err := bufio.NewReader(conn).ReadString(' ')
+	if err != nil || !strings.HasSuffix(frame, " ") || frame[0] < '1' || frame[0] > '9' {
+		t.Errorf("Expected octet counting frame, got %q: %v", frame, err)
+	}
+
+	if _, err = NewSyslogSink("http://127.0.0.1:514"); err == nil {
+		t.Error("Expected error for unsupported scheme")
+	}
+}
+
+func TestJournaldMessage(t *testing.T) {
+	msg := journaldMessage(LogEntry{
+		Message: "line1\nline2",
+		Fields:  map[string]string{"recordType": "AAAA"},
+	})
+	var expected bytes.Buffer
+	expected.WriteString("MESSAGE\n")
+	binary.Write(&expected, binary.LittleEndian, uint64(len("line1\nline2")))
+	expected.WriteString("line1\nline2\nPRIORITY=6\nSYSLOG_IDENTIFIER=ddns-go\nDDNS_RECORD_TYPE=AAAA\n")
+	if !bytes.Equal(msg, expected.Bytes()) {
+		t.Errorf("Expected %q, got %q", expected.Bytes(), msg)
+	}
+}
+
+func TestJournaldSink(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "journal.socket")
+	conn, err := net.ListenPacket("unixgram", socket)
+	if err != nil {
+		t.Skip("unixgram is not supported:", err)
+	}
+	defer conn.Close()
+	journaldSocket = socket
+	t.Cleanup(func() { journaldSocket = "/run/systemd/journal/socket" })
+
+	sink, err := NewJournaldSink()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sink.Close()
+	sink.Write(LogEntry{Message: "hello", Fields: map[string]string{"config": "home"}})
+
+	buf := make([]byte, 1024)
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	n, _, err := conn.ReadFrom(buf)
+	if err != nil || !strings.Contains(string(buf[:n]), "MESSAGE=hello\n") || !strings.Contains(string(buf[:n]), "DDNS_CONFIG=home\n") {
+		t.Errorf("Unexpected datagram %q: %v", buf[:n], err)
+	}
+}
+
+func TestSyslogSinkBackoff(t *testing.T) {
+	tcp, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sink, err := NewSyslogSink("tcp://" + tcp.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sink.Close()
+	conn, _ := tcp.Accept()
+	conn.Close()
+	tcp.Close()
+
+	// 服务器不可用时只重新连接一次, 之后的日志被丢弃
+	var errs int
+	for i := 0; i < 10; i++ {
+		if sink.Write(LogEntry{Time: time.Now(), Message: "hello"}) != nil {
+			errs++
+		}
+	}
+	if errs == 0 || errs > 2 || sink.dropped < 8 || sink.retryAt.IsZero() {
+		t.Errorf("Expected to back off, got %d errors, %d dropped", errs, sink.dropped)
+	}
+}
@@ -0,0 +1,142 @@
+package util
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// syslogFacility facility daemon(3), PRI 为 facility * 8 + severity
+	syslogFacility = 3
+	// syslogSDID 结构化数据的ID, 32473 为 RFC 5612 中用于示例的企业号
+	syslogSDID = "ddns-go@32473"
+	// maxSyslogBackoff 重新连接的最大间隔
+	maxSyslogBackoff = time.Minute
+)
+
+// SyslogSink 以 RFC 5424 格式发送到 syslog 服务器, TCP 使用 RFC 6587 的长度前缀分帧
+type SyslogSink struct {
+	network string
+	addr    string
+	conn    net.Conn
+	// 连接失败后, 在 retryAt 之前不重新连接, 间隔从 1 秒开始翻倍
+	backoff time.Duration
+	retryAt time.Time
+	// 等待重新连接时丢弃的条数
+	dropped int
+}
+
+// NewSyslogSink 地址如 udp://192.168.1.2:514、tcp://192.168.1.2:514、unix:///dev/log
+func NewSyslogSink(address string) (*SyslogSink, error) {
+	u, err := url.Parse(address)
+	if err != nil {
+		return nil, err
+	}
+	sink := &SyslogSink{network: u.Scheme, addr: u.Host}
+	switch u.Scheme {
+	case "udp", "tcp":
+		if u.Port() == "" {
+			sink.addr = net.JoinHostPort(u.Hostname(), "514")
+		}
+	case "unix":
+		sink.network, sink.addr = "unixgram", u.Path
+	default:
+		return nil, errors.New("syslog address must start with udp://, tcp:// or unix://")
+	}
+	if err = sink.dial(); err != nil {
+		return nil, err
+	}
+	return sink, nil
+}
+
+func (sink *SyslogSink) dial() (err error) {
+	sink.conn, err = net.DialTimeout(sink.network, sink.addr, 5*time.Second)
+	return
+}
+
+// Write 发送一条日志, 连接断开时重新连接。
+// 连接失败后等待一段时间再重新连接, 期间的日志将被丢弃, 避免每条日志都等待连接超时
+func (sink *SyslogSink) Write(entry LogEntry) error {
+	msg := syslogMessage(entry)
+	if sink.network == "tcp" {
+		msg = strconv.Itoa(len(msg)) + " " + msg
+	}
+	if sink.conn != nil {
+		if _, err := sink.conn.Write([]byte(msg)); err == nil {
+			return nil
+		}
+		sink.conn.Close()
+		sink.conn = nil
+	}
+	if time.Now().Before(sink.retryAt) {
+		sink.dropped++
+		return nil
+	}
+	if err := sink.dial(); err != nil {
+		sink.conn = nil
+		sink.backoff = min(max(sink.backoff*2, time.Second), maxSyslogBackoff)
+		sink.retryAt = time.Now().Add(sink.backoff)
+		return err
+	}
+	sink.backoff = 0
+	if _, err := sink.conn.Write([]byte(msg)); err != nil {
+		return err
+	}
+	if dropped := sink.dropped; dropped > 0 {
+		sink.dropped = 0
+		return fmt.Errorf("dropped %d entries while %s was unavailable", dropped, sink.addr)
+	}
+	return nil
+}
+
+// Close 关闭连接
+func (sink *SyslogSink) Close() error {
+	if sink.conn == nil {
+		return nil
+	}
+	return sink.conn.Close()
+}
+
+// syslogMessage RFC 5424 格式: <PRI>1 TIMESTAMP HOSTNAME APP-NAME PROCID MSGID [SD] MSG
+func syslogMessage(entry LogEntry) string {
+	hostname, _ := os.Hostname()
+	if hostname == "" {
+		hostname = "-"
+	}
+
+	sd := "-"
+	if len(entry.Fields) > 0 {
+		keys := make([]string, 0, len(entry.Fields))
+		for key, value := range entry.Fields {
+			if value != "" {
+				keys = append(keys, key)
+			}
+		}
+		slices.Sort(keys)
+		if len(keys) > 0 {
+			var sb strings.Builder
+			sb.WriteString("[" + syslogSDID)
+			for _, key := range keys {
+				fmt.Fprintf(&sb, ` %s="%s"`, key, syslogEscaper.Replace(entry.Fields[key]))
+			}
+			sb.WriteString("]")
+			sd = sb.String()
+		}
+	}
+
+	return fmt.Sprintf("<%d>1 %s %s ddns-go %d - %s %s",
+		syslogFacility*8+entry.severity(), entry.Time.Format(syslogTimeLayout), hostname, os.Getpid(), sd, entry.Message)
+}
+
+// syslogTimeLayout RFC 5424 的时间, 秒的小数部分最多6位
+const syslogTimeLayout = "2006-01-02T15:04:05.000000Z07:00"
+
+// syslogEscaper 转义结构化数据中的 " \ ]
+var syslogEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, `]`, `\]`)
@@ -0,0 +1,121 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"log"
+	"log/slog"
+	"strings"
+)
+
+// logLevel 日志级别, 由 -log-level 设置, 默认 info
+var logLevel = new(slog.LevelVar)
+
+// logger 基于 log/slog, 消息使用 i18n 翻译后的文本, 属性写入日志输出(syslog/journald/JSON)
+var logger = slog.New(&logHandler{})
+
+// SetLogLevel 设置日志级别, 可选 debug/info/warn/error
+func SetLogLevel(level string) error {
+	var l slog.Level
+	if err := l.UnmarshalText([]byte(level)); err != nil {
+		return err
+	}
+	logLevel.Set(l)
+	return nil
+}
+
+// IsDebug 是否为 debug 级别
+func IsDebug() bool {
+	return logLevel.Level() <= slog.LevelDebug
+}
+
+// Log 以 info 级别输出日志
+func Log(key string, args ...interface{}) {
+	LogAttrs(slog.LevelInfo, nil, key, args...)
+}
+
+// LogAttrs 输出带属性的日志, 如 provider/domain/recordType/action。
+// 参数中包含 HTTP 状态码异常时, 自动添加 httpStatus 属性
+func LogAttrs(level slog.Level, attrs []slog.Attr, key string, args ...interface{}) {
+	ctx := context.Background()
+	if !logger.Enabled(ctx, level) {
+		return
+	}
+	attrs = attrs[:len(attrs):len(attrs)]
+	for _, arg := range args {
+		var statusErr *HTTPStatusError
+		if err, ok := arg.(error); ok && errors.As(err, &statusErr) {
+			attrs = append(attrs, slog.Int("httpStatus", statusErr.StatusCode))
+		}
+	}
+	logger.LogAttrs(ctx, level, LogStr(key, args...), attrs...)
+}
+
+func LogStr(key string, args ...interface{}) string {
+	return logPrinter.Sprintf(key, args...)
+}
+
+// logHandler 在控制台及网页中只显示消息, 属性及级别写入日志输出
+type logHandler struct {
+	attrs []slog.Attr
+	group string
+}
+
+func (h *logHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= logLevel.Level()
+}
+
+func (h *logHandler) Handle(_ context.Context, record slog.Record) error {
+	log.Println(record.Message)
+
+	fields := map[string]string{}
+	for _, attr := range h.attrs {
+		addLogField(fields, "", attr)
+	}
+	record.Attrs(func(attr slog.Attr) bool {
+		addLogField(fields, h.group, attr)
+		return true
+	})
+	writeSinks(LogEntry{Time: record.Time, Level: record.Level, Message: record.Message, Fields: fields})
+	return nil
+}
+
+func (h *logHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	h2 := *h
+	h2.attrs = make([]slog.Attr, 0, len(h.attrs)+len(attrs))
+	h2.attrs = append(h2.attrs, h.attrs...)
+	for _, attr := range attrs {
+		if h.group != "" {
+			attr.Key = h.group + attr.Key
+		}
+		h2.attrs = append(h2.attrs, attr)
+	}
+	return &h2
+}
+
+func (h *logHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	h2 := *h
+	h2.group = h.group + name + "."
+	return &h2
+}
+
+// addLogField 将属性转为字段, 组内的属性使用 . 连接, 如 http.status
+func addLogField(fields map[string]string, prefix string, attr slog.Attr) {
+	attr.Value = attr.Value.Resolve()
+	if attr.Value.Kind() == slog.KindGroup {
+		if attr.Key != "" {
+			prefix += attr.Key + "."
+		}
+		for _, a := range attr.Value.Group() {
+			addLogField(fields, prefix, a)
+		}
+		return
+	}
+	if attr.Key == "" {
+		return
+	}
+	fields[prefix+attr.Key] = strings.TrimSpace(attr.Value.String())
+}
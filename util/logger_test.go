@@ -0,0 +1,84 @@
+package util
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestLogAttrs(t *testing.T) {
+	sink := &testSink{}
+	AddLogSink(sink)
+	t.Cleanup(CloseLogSinks)
+	t.Cleanup(func() { logLevel.Set(slog.LevelInfo) })
+
+	if err := SetLogLevel("warn"); err != nil {
+		t.Fatal(err)
+	}
+	Log("监听 %s", ":9876")
+	LogAttrs(slog.LevelError, []slog.Attr{slog.String("domain", "www.example.com"), slog.Group("http", slog.Int("status", 500))},
+		"异常信息: %s", &HTTPStatusError{StatusCode: 404, Body: "not found"})
+
+	// 等待后台写入
+	CloseLogSinks()
+	if len(sink.entries) != 1 {
+		t.Fatalf("Expected 1 entry, got %d", len(sink.entries))
+	}
+	entry := sink.entries[0]
+	if entry.Level != slog.LevelError || entry.Fields["domain"] != "www.example.com" || entry.Fields["http.status"] != "500" || entry.Fields["httpStatus"] != "404" {
+		t.Errorf("Unexpected entry %+v", entry)
+	}
+	if entry.Message != LogStr("异常信息: %s", LogStr("返回内容: %s ,返回状态码: %d", "not found", 404)) {
+		t.Errorf("Unexpected message %s", entry.Message)
+	}
+
+	if err := SetLogLevel("verbose"); err == nil {
+		t.Error("Expected error for unknown level")
+	}
+}
+
+func TestDebugTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Set-Cookie", "session=abc")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":"1","api_token":"t0ken"}`))
+	}))
+	defer server.Close()
+
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+	logLevel.Set(slog.LevelDebug)
+	t.Cleanup(func() { logLevel.Set(slog.LevelInfo) })
+
+	req, _ := http.NewRequest("POST", server.URL+"/update?hostname=www&password=p4ss", strings.NewReader("user=me&secretKey=s3cret"))
+	req.Header.Set("Authorization", "Bearer abc")
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := CreateHTTPClient().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	if string(body) != `{"id":"1","api_token":"t0ken"}` {
+		t.Errorf("Expected the full body for the caller, got %s", body)
+	}
+	out := buf.String()
+	for _, secret := range []string{"p4ss", "Bearer", "s3cret", "t0ken", "session=abc"} {
+		if strings.Contains(out, secret) {
+			t.Errorf("Expected %s to be redacted:\n%s", secret, out)
+		}
+	}
+	for _, expected := range []string{"hostname=www", "user=me", `"id":"1"`, "201 Created"} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("Expected %s in the dump:\n%s", expected, out)
+		}
+	}
+}
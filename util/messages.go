@@ -1,7 +1,6 @@
 package util
 
 import (
-	"log"
 	"strings"
 
 	"golang.org/x/text/language"
@@ -17,6 +16,8 @@ func init() {
 	message.SetString(language.English, "可使用 sudo ./ddns-go -s install 安装服务运行", "You can use 'sudo ./ddns-go -s install' to install service")
 	message.SetString(language.English, "监听 %s", "Listening on %s")
 	message.SetString(language.English, "配置文件已保存在: %s", "Config file has been saved to: %s")
+	message.SetString(language.English, "日志输出初始化失败! 异常信息: %s", "Failed to initialize the log output! Exception: %s")
+	message.SetString(language.English, "更新历史保存失败! 异常信息: %s", "Failed to save the update history! Exception: %s")
 
 	message.SetString(language.English, "你的IP %s 没有变化, 域名 %s", "Your's IP %s has not changed! Domain: %s")
 	message.SetString(language.English, "新增域名解析 %s 成功! IP: %s", "Added domain %s successfully! IP: %s")
@@ -24,6 +25,7 @@ func init() {
 
 	message.SetString(language.English, "更新域名解析 %s 成功! IP: %s", "Updated domain %s successfully! IP: %s")
 	message.SetString(language.English, "更新域名解析 %s 失败! 异常信息: %s", "Failed to updated domain %s! Result: %s")
+	message.SetString(language.English, "域名 %s 的更新结果: %s", "Update result of %s: %s")
 
 	message.SetString(language.English, "你的IPv4未变化, 未触发 %s 请求", "Your's IPv4 has not changed, %s request has not been triggered")
 	message.SetString(language.English, "你的IPv6未变化, 未触发 %s 请求", "Your's IPv6 has not changed, %s request has not been triggered")
@@ -35,19 +37,72 @@ func init() {
 	message.SetString(language.English, "异常信息: %s", "Exception: %s")
 	message.SetString(language.English, "查询域名信息发生异常! %s", "Failed to query domain info! %s")
 	message.SetString(language.English, "返回内容: %s ,返回状态码: %d", "Response body: %s ,Response status code: %d")
+	message.SetString(language.English, "HTTP请求:\n%s", "HTTP request:\n%s")
+	message.SetString(language.English, "HTTP请求失败! 耗时: %s, 异常信息: %s", "HTTP request failed! Duration: %s, Exception: %s")
+	message.SetString(language.English, "HTTP响应, 耗时: %s\n%s", "HTTP response, duration: %s\n%s")
 	message.SetString(language.English, "通过接口获取IPv4失败! 接口地址: %s", "Failed to get IPv4 from %s")
 	message.SetString(language.English, "通过接口获取IPv6失败! 接口地址: %s", "Failed to get IPv6 from %s")
-	message.SetString(language.English, "将不会触发Webhook, 仅在第 3 次失败时触发一次Webhook, 当前失败次数：%d", "Webhook will not be triggered, only trigger once when the third failure, current failure times: %d")
+	message.SetString(language.English, "通过接口获取%s失败! 接口地址: %s", "Failed to get %s from %s")
+	message.SetString(language.English, "接口返回的%s不一致: %s", "The %s returned by the APIs are inconsistent: %s")
+	message.SetString(language.English, "仅有 %d 个接口返回相同的%s, 少于要求的 %d 个, 将不会更新", "Only %d APIs returned the same %s, less than the required %d, will not update")
+	message.SetString(language.English, "返回不同%s的接口数量相同(各 %d 个), 无法确定地址, 将不会更新", "The same number of APIs (%[2]d each) returned different %[1]s, cannot decide the address, will not update")
+	message.SetString(language.English, "域名 %s 的IPv6后缀不正确! 异常信息: %s", "The IPv6 suffix of domain %s is incorrect! Exception: %s")
+	message.SetString(language.English, "读取IPv6地址的状态失败, 将不使用地址选择策略! 异常信息: %s", "Failed to read the state of IPv6 addresses, the address policy will not be used! Exception: %s")
+	message.SetString(language.English, "网卡 %s 没有符合地址选择策略的IPv6地址", "Network interface %s has no IPv6 address matching the address policy")
+	message.SetString(language.English, "获取到的%s地址 %s 不允许发布! 原因: %s", "The %s address %s is not allowed to be published! Reason: %s")
+	message.SetString(language.English, "命令输出的JSON不正确! 命令: %s, 错误: %s", "The JSON output of the command is invalid! Command: %s, Error: %s")
+	message.SetString(language.English, "命令返回了多个%s地址, 每个域名只能使用一个! 命令: %s, 地址: %s", "The command returned multiple %s addresses, only one can be used for each domain! Command: %s, Addresses: %s")
+	message.SetString(language.English, "命令的标准输出中没有%s地址, 使用标准错误中的 %s", "No %s address in the stdout of the command, using %s from stderr")
+	message.SetString(language.English, "备用来源不正确: %s", "Invalid fallback source: %s")
+	message.SetString(language.English, "尝试通过备用来源 %s 获取%s", "Trying fallback source %s to get %s")
+	message.SetString(language.English, "通过 %s 获取%s超时", "Timed out getting %[2]s via %[1]s")
+	message.SetString(language.English, "%s地址 %s 来自: %s", "%s address %s comes from: %s")
+	message.SetString(language.English, "监听网卡地址变化失败! 异常信息: %s", "Failed to watch the address changes of network interfaces! Exception: %s")
+	message.SetString(language.English, "开始监听网卡地址的变化", "Start watching the address changes of network interfaces")
+	message.SetString(language.English, "网卡 %s 的地址发生变化, 立即更新", "The address of network interface %s has changed, update immediately")
+	message.SetString(language.English, "仅 Linux 支持监听网卡地址的变化", "Watching the address changes of network interfaces is only supported on Linux")
+	message.SetString(language.English, "通过STUN服务器获取%s失败! 服务器: %s", "Failed to get %s from STUN server %s")
+	message.SetString(language.English, "没有两个STUN服务器返回相同的%s! 返回值: %s", "No two STUN servers returned the same %s! Results: %s")
+	message.SetString(language.English, "通过DNS查询获取%s失败! 查询: %s", "Failed to get %s by DNS query %s")
+	message.SetString(language.English, "从路由器获得IPv4失败! 异常信息: %s", "Failed to get IPv4 from the router! Exception: %s")
+	message.SetString(language.English, "通过UPnP获取路由器的外部地址失败, 将尝试NAT-PMP/PCP! 异常信息: %s", "Failed to get the external address of the router through UPnP, will try NAT-PMP/PCP! Exception: %s")
+	message.SetString(language.English, "路由器的外部地址 %s 为私有地址或运营商级NAT地址, 可能存在多层NAT, 外网可能无法访问", "The external address %s of the router is a private or carrier-grade NAT address, there may be multiple layers of NAT and it may not be accessible from the internet")
+	message.SetString(language.English, "将不会触发Webhook, 连续失败 %d 次后触发, 当前失败次数：%d", "Webhook will not be triggered until %d consecutive failures, current failure times: %d")
+	message.SetString(language.English, "将不会触发Webhook, 每 %d 小时提醒一次, 当前失败次数：%d", "Webhook will not be triggered, reminding every %d hours, current failure times: %d")
+	message.SetString(language.English, "将不会触发Webhook, 已通知过本次失败, 当前失败次数：%d", "Webhook will not be triggered, this failure has been notified, current failure times: %d")
 	message.SetString(language.English, "在DNS服务商中未找到根域名: %s", "Root domain not found in DNS provider: %s")
 
 	// webhook
 	message.SetString(language.English, "Webhook配置中的URL不正确", "Webhook url is incorrect")
 	message.SetString(language.English, "Webhook中的 RequestBody JSON 无效", "Webhook RequestBody JSON is invalid")
+	message.SetString(language.English, "通知发送成功! 渠道: %s", "Notification sent successfully! Channel: %s")
+	message.SetString(language.English, "通知发送失败! 渠道: %s, 异常信息：%s", "Failed to send notification! Channel: %s, Exception: %s")
+	message.SetString(language.English, "Webhook模板不正确! 异常信息：%s", "The Webhook template is invalid! Exception: %s")
 	message.SetString(language.English, "Webhook调用成功! 返回数据：%s", "Successfully called Webhook! Response body: %s")
 	message.SetString(language.English, "Webhook调用失败! 异常信息：%s", "Failed to call Webhook! Exception: %s")
+	message.SetString(language.English, "发送失败, 将在 %s 后重试(%d/%d)! 异常信息：%s", "Failed to send, retrying in %s (%d/%d)! Exception: %s")
+	message.SetString(language.English, "等待发送的Webhook过多, 将丢弃本次发送", "Too many Webhooks are waiting to be sent, this one will be dropped")
+	message.SetString(language.English, "MQTT连接失败! 异常信息：%s", "Failed to connect to the MQTT broker! Exception: %s")
+	message.SetString(language.English, "MQTT发布失败! 异常信息：%s", "Failed to publish to MQTT! Exception: %s")
 	message.SetString(language.English, "Webhook Header不正确: %s", "Webhook header is invalid: %s")
 	message.SetString(language.English, "请输入Webhook的URL", "Please enter the Webhook url")
 
+	// verify
+	message.SetString(language.English, "查询域名 %s 的权威DNS服务器失败! 异常信息: %s", "Failed to query the authoritative name servers of %s! Exception: %s")
+	message.SetString(language.English, "域名 %s 已在权威DNS服务器生效", "Domain %s is live on the authoritative name servers")
+	message.SetString(language.English, "域名 %s 在权威DNS服务器中未生效, 未返回新的记录: %s", "Domain %s is not live on the authoritative name servers, new record %s was not returned")
+
+	// drift
+	message.SetString(language.English, "漂移检测查询域名 %s 失败! 异常信息: %s", "Drift detection failed to query domain %s! Exception: %s")
+	message.SetString(language.English, "检测到域名 %s 的解析被修改! 期望: %s, 实际: %s", "Detected that the record of %s was modified! Expected: %s, Actual: %s")
+
+	message.SetString(language.English, "无记录", "no record")
+
+	// records
+	message.SetString(language.English, "记录: %s 不正确", "The record %s is incorrect")
+	message.SetString(language.English, "DNS服务商 %s 不支持更新 %s 记录", "DNS provider %s does not support updating %s records")
+	message.SetString(language.English, "获取 SRV 记录 %s 的端口失败! 异常信息: %s", "Failed to get the port of SRV record %s! Exception: %s")
+
 	// callback
 	message.SetString(language.English, "Callback的URL不正确", "Callback url is incorrect")
 	message.SetString(language.English, "Callback调用成功, 域名: %s, IP: %s, 返回数据: %s", "Successfully called Callback! Domain: %s, IP: %s, Response body: %s")
@@ -58,6 +113,7 @@ func init() {
 	message.SetString(language.English, "密码不安全！尝试使用更复杂的密码", "Password is not secure! Try using a more complex password")
 	message.SetString(language.English, "数据解析失败, 请刷新页面重试", "Data parsing failed, please refresh the page and try again")
 	message.SetString(language.English, "第 %s 个配置未填写域名", "The %s config does not fill in the domain")
+	message.SetString(language.English, "第 %s 个配置的DNS服务商 %s 不支持局域网主机(Ipv6Suffix/Ipv6Mac)", "The DNS provider %[2]s of the %[1]s config does not support LAN hosts (Ipv6Suffix/Ipv6Mac)")
 
 	// config
 	message.SetString(language.English, "从网卡获得IPv4失败", "Failed to get IPv4 from network card")
@@ -65,6 +121,7 @@ func init() {
 	message.SetString(language.English, "获取IPv4结果失败! 接口: %s ,返回值: %s", "Failed to get IPv4 result! Interface: %s ,Result: %s")
 	message.SetString(language.English, "获取%s结果失败! 未能成功执行命令：%s, 错误：%q, 退出状态码：%s", "Failed to get %s result! Command: %s, Error: %q, Exit status code: %s")
 	message.SetString(language.English, "获取%s结果失败! 命令: %s, 标准输出: %q", "Failed to get %s result! Command: %s, Stdout: %q")
+	message.SetString(language.English, "获取%s结果失败! 标准输出及标准错误中都没有匹配的地址, 命令: %s, 标准输出: %q, 标准错误: %q", "Failed to get %s result! No matching address in stdout or stderr, Command: %s, Stdout: %q, Stderr: %q")
 	message.SetString(language.English, "从网卡获得IPv6失败", "Failed to get IPv6 from network card")
 	message.SetString(language.English, "从网卡中获得IPv6失败! 网卡名: %s", "Failed to get IPv6 from network card! Network card name: %s")
 	message.SetString(language.English, "获取IPv6结果失败! 接口: %s ,返回值: %s", "Failed to get IPv6 result! Interface: %s ,Result: %s")
@@ -103,6 +160,7 @@ func init() {
 	message.SetString(language.English, "未改变", "no changed")
 	message.SetString(language.English, "失败", "failed")
 	message.SetString(language.English, "成功", "success")
+	message.SetString(language.English, "未验证", "unverified")
 
 	// Login
 	message.SetString(language.English, "%q 配置文件为空, 超过3小时禁止从公网访问", "%q configuration file is empty, public network access is prohibited for more than 3 hours")
@@ -117,14 +175,6 @@ func init() {
 
 }
 
-func Log(key string, args ...interface{}) {
-	log.Println(LogStr(key, args...))
-}
-
-func LogStr(key string, args ...interface{}) string {
-	return logPrinter.Sprintf(key, args...)
-}
-
 func InitLogLang(lang string) string {
 	newLang := language.English
 	if strings.HasPrefix(lang, "zh") {
@@ -0,0 +1,312 @@
+// Package mqtt 仅用于发布消息的 MQTT 3.1.1 客户端, 支持TLS、用户名密码认证及遗嘱消息
+package mqtt
+
+import (
+	"bufio"
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// 控制报文类型
+const (
+	packetConnect    byte = 0x10
+	packetConnack    byte = 0x20
+	packetPublish    byte = 0x30
+	packetPingreq    byte = 0xc0
+	packetDisconnect byte = 0xe0
+)
+
+// dialTimeout 连接及等待 CONNACK 的超时时间
+const dialTimeout = 10 * time.Second
+
+// ErrClosed 连接已断开
+var ErrClosed = errors.New("mqtt: connection closed")
+
+// Message 消息, 均使用 QoS 0 发布
+type Message struct {
+	Topic   string
+	Payload []byte
+	// 保留消息, 新订阅者会收到最后一条
+	Retain bool
+}
+
+// Options 连接参数
+type Options struct {
+	// mqtt://host:1883 或 mqtts://host:8883, 也支持 tcp:// ssl:// tls://
+	Broker   string
+	ClientID string
+	Username string
+	Password string
+	// 遗嘱消息, 连接异常断开时由服务器发布
+	Will *Message
+	// 心跳间隔, 为0则为60秒
+	KeepAlive time.Duration
+	// 为空时使用默认配置
+	TLSConfig *tls.Config
+}
+
+// Client 客户端
+type Client struct {
+	conn net.Conn
+	// 写入时加锁, 心跳与发布可能同时进行
+	mu   sync.Mutex
+	done chan struct{}
+	once sync.Once
+}
+
+// Dial 连接服务器, 并等待服务器接受连接
+func Dial(opts Options) (*Client, error) {
+	u, err := url.Parse(opts.Broker)
+	if err != nil {
+		return nil, err
+	}
+	useTLS := false
+	port := "1883"
+	switch u.Scheme {
+	case "mqtt", "tcp":
+	case "mqtts", "ssl", "tls":
+		useTLS, port = true, "8883"
+	default:
+		return nil, fmt.Errorf("mqtt: unsupported scheme %q", u.Scheme)
+	}
+	addr := u.Host
+	if u.Port() == "" {
+		addr = net.JoinHostPort(u.Hostname(), port)
+	}
+
+	dialer := &net.Dialer{Timeout: dialTimeout}
+	var conn net.Conn
+	if useTLS {
+		tlsConfig := opts.TLSConfig
+		if tlsConfig == nil {
+			tlsConfig = &tls.Config{}
+		}
+		if tlsConfig.ServerName == "" {
+			tlsConfig = tlsConfig.Clone()
+			tlsConfig.ServerName = u.Hostname()
+		}
+		conn, err = tls.DialWithDialer(dialer, "tcp", addr, tlsConfig)
+	} else {
+		conn, err = dialer.Dial("tcp", addr)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	keepAlive := opts.KeepAlive
+	if keepAlive <= 0 {
+		keepAlive = 60 * time.Second
+	}
+	conn.SetDeadline(time.Now().Add(dialTimeout))
+	if _, err = conn.Write(connectPacket(opts, keepAlive)); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	reader := bufio.NewReader(conn)
+	if err = readConnack(reader); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	conn.SetDeadline(time.Time{})
+
+	c := &Client{conn: conn, done: make(chan struct{})}
+	go c.readLoop(reader)
+	go c.pingLoop(keepAlive)
+	return c, nil
+}
+
+// Publish 发布消息
+func (c *Client) Publish(msgs ...Message) error {
+	for _, msg := range msgs {
+		if err := c.write(publishPacket(msg)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Closed 连接是否已断开
+func (c *Client) Closed() bool {
+	select {
+	case <-c.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// Close 断开连接, 正常断开时服务器不会发布遗嘱消息
+func (c *Client) Close() error {
+	c.write([]byte{packetDisconnect, 0})
+	c.shutdown()
+	return c.conn.Close()
+}
+
+func (c *Client) shutdown() {
+	c.once.Do(func() { close(c.done) })
+}
+
+func (c *Client) write(packet []byte) error {
+	if c.Closed() {
+		return ErrClosed
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.conn.SetWriteDeadline(time.Now().Add(dialTimeout))
+	if _, err := c.conn.Write(packet); err != nil {
+		c.shutdown()
+		c.conn.Close()
+		return err
+	}
+	return nil
+}
+
+// readLoop 丢弃服务器发送的报文(PINGRESP), 出错时标记连接已断开
+func (c *Client) readLoop(reader *bufio.Reader) {
+	defer c.shutdown()
+	for {
+		if _, _, err := readPacket(reader); err != nil {
+			c.conn.Close()
+			return
+		}
+	}
+}
+
+// pingLoop 定时发送心跳
+func (c *Client) pingLoop(keepAlive time.Duration) {
+	ticker := time.NewTicker(keepAlive / 2)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.done:
+			return
+		case <-ticker.C:
+			if c.write([]byte{packetPingreq, 0}) != nil {
+				return
+			}
+		}
+	}
+}
+
+// connectPacket CONNECT 报文, 使用 clean session
+func connectPacket(opts Options, keepAlive time.Duration) []byte {
+	flags := byte(0x02)
+	var payload []byte
+	payload = appendString(payload, opts.ClientID)
+	if opts.Will != nil {
+		flags |= 0x04
+		if opts.Will.Retain {
+			flags |= 0x20
+		}
+		payload = appendString(payload, opts.Will.Topic)
+		payload = appendBytes(payload, opts.Will.Payload)
+	}
+	if opts.Username != "" {
+		flags |= 0x80
+		payload = appendString(payload, opts.Username)
+		if opts.Password != "" {
+			flags |= 0x40
+			payload = appendString(payload, opts.Password)
+		}
+	}
+
+	body := appendString(nil, "MQTT")
+	body = append(body, 4, flags)
+	body = binary.BigEndian.AppendUint16(body, uint16(keepAlive/time.Second))
+	return packet(packetConnect, append(body, payload...))
+}
+
+// publishPacket QoS 0 的 PUBLISH 报文
+func publishPacket(msg Message) []byte {
+	header := packetPublish
+	if msg.Retain {
+		header |= 0x01
+	}
+	return packet(header, append(appendString(nil, msg.Topic), msg.Payload...))
+}
+
+// readConnack 读取 CONNACK, 返回码不为0时返回错误
+func readConnack(reader *bufio.Reader) error {
+	header, body, err := readPacket(reader)
+	if err != nil {
+		return err
+	}
+	if header&0xf0 != packetConnack || len(body) != 2 {
+		return errors.New("mqtt: unexpected packet, expected CONNACK")
+	}
+	switch body[1] {
+	case 0:
+		return nil
+	case 1:
+		return errors.New("mqtt: connection refused, unacceptable protocol version")
+	case 2:
+		return errors.New("mqtt: connection refused, identifier rejected")
+	case 3:
+		return errors.New("mqtt: connection refused, server unavailable")
+	case 4:
+		return errors.New("mqtt: connection refused, bad user name or password")
+	case 5:
+		return errors.New("mqtt: connection refused, not authorized")
+	}
+	return fmt.Errorf("mqtt: connection refused, code %d", body[1])
+}
+
+// readPacket 读取一个报文, 返回固定报头的第一个字节及剩余部分
+func readPacket(reader *bufio.Reader) (byte, []byte, error) {
+	header, err := reader.ReadByte()
+	if err != nil {
+		return 0, nil, err
+	}
+	length, multiplier := 0, 1
+	for i := 0; ; i++ {
+		b, err := reader.ReadByte()
+		if err != nil {
+			return 0, nil, err
+		}
+		length += int(b&0x7f) * multiplier
+		if b&0x80 == 0 {
+			break
+		}
+		if i == 3 {
+			return 0, nil, errors.New("mqtt: malformed remaining length")
+		}
+		multiplier *= 128
+	}
+	body := make([]byte, length)
+	_, err = io.ReadFull(reader, body)
+	return header, body, err
+}
+
+// packet 加上固定报头
+func packet(header byte, body []byte) []byte {
+	buf := []byte{header}
+	length := len(body)
+	for {
+		b := byte(length % 128)
+		length /= 128
+		if length > 0 {
+			b |= 0x80
+		}
+		buf = append(buf, b)
+		if length == 0 {
+			break
+		}
+	}
+	return append(buf, body...)
+}
+
+func appendString(buf []byte, s string) []byte {
+	return appendBytes(buf, []byte(s))
+}
+
+func appendBytes(buf []byte, b []byte) []byte {
+	buf = binary.BigEndian.AppendUint16(buf, uint16(len(b)))
+	return append(buf, b...)
+}
@@ -0,0 +1,128 @@
+package mqtt
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testPacket 测试服务器收到的报文
+type testPacket struct {
+	Header byte
+	Body   []byte
+}
+
+// startTestBroker 启动一个仅用于测试的服务器, 以 returnCode 回复 CONNECT, 并记录收到的报文
+func startTestBroker(t *testing.T, returnCode byte) (string, chan testPacket) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	packets := make(chan testPacket, 16)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		defer close(packets)
+
+		reader := bufio.NewReader(conn)
+		for {
+			header, body, err := readPacket(reader)
+			if err != nil {
+				return
+			}
+			packets <- testPacket{header, body}
+			if header == packetConnect {
+				conn.Write([]byte{packetConnack, 2, 0, returnCode})
+			}
+		}
+	}()
+	return "mqtt://" + listener.Addr().String(), packets
+}
+
+// readString 读取长度前缀的字符串
+func readString(t *testing.T, body *[]byte) string {
+	if len(*body) < 2 {
+		t.Fatalf("Unexpected end of packet")
+	}
+	n := int(binary.BigEndian.Uint16(*body))
+	s := string((*body)[2 : 2+n])
+	*body = (*body)[2+n:]
+	return s
+}
+
+func TestClient(t *testing.T) {
+	broker, packets := startTestBroker(t, 0)
+	client, err := Dial(Options{
+		Broker:    broker,
+		ClientID:  "ddns-go-test",
+		Username:  "user",
+		Password:  "pass",
+		Will:      &Message{Topic: "ddns-go/status", Payload: []byte("offline"), Retain: true},
+		KeepAlive: 30 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("Expected nil error, got %v", err)
+	}
+
+	connect := <-packets
+	body := connect.Body
+	if readString(t, &body) != "MQTT" || body[0] != 4 {
+		t.Fatalf("Unexpected protocol %v", connect.Body)
+	}
+	// username, password, will retain, will, clean session
+	if body[1] != 0xe6 || binary.BigEndian.Uint16(body[2:]) != 30 {
+		t.Errorf("Unexpected flags %x or keep alive", body[1:4])
+	}
+	body = body[4:]
+	fields := []string{readString(t, &body), readString(t, &body), readString(t, &body), readString(t, &body), readString(t, &body)}
+	if strings.Join(fields, ",") != "ddns-go-test,ddns-go/status,offline,user,pass" {
+		t.Errorf("Unexpected payload %v", fields)
+	}
+
+	payload := bytes.Repeat([]byte("a"), 200)
+	if err = client.Publish(Message{Topic: "ddns-go/home/state", Payload: payload, Retain: true}); err != nil {
+		t.Fatalf("Expected nil error, got %v", err)
+	}
+	publish := <-packets
+	body = publish.Body
+	if publish.Header != packetPublish|0x01 || readString(t, &body) != "ddns-go/home/state" || !bytes.Equal(body, payload) {
+		t.Errorf("Unexpected publish %x %q", publish.Header, publish.Body)
+	}
+
+	client.Close()
+	if disconnect := <-packets; disconnect.Header != packetDisconnect {
+		t.Errorf("Expected DISCONNECT, got %x", disconnect.Header)
+	}
+	if !client.Closed() || client.Publish(Message{Topic: "t"}) != ErrClosed {
+		t.Error("Expected closed client")
+	}
+}
+
+func TestDialRefused(t *testing.T) {
+	broker, _ := startTestBroker(t, 5)
+	if _, err := Dial(Options{Broker: broker, ClientID: "ddns-go-test"}); err == nil || !strings.Contains(err.Error(), "not authorized") {
+		t.Errorf("Expected not authorized, got %v", err)
+	}
+	if _, err := Dial(Options{Broker: "http://127.0.0.1:1883"}); err == nil {
+		t.Error("Expected error for unsupported scheme")
+	}
+}
+
+func TestPacketLength(t *testing.T) {
+	for _, length := range []int{0, 127, 128, 16383, 16384} {
+		p := packet(packetPublish, make([]byte, length))
+		header, body, err := readPacket(bufio.NewReader(bytes.NewReader(p)))
+		if err != nil || header != packetPublish || len(body) != length {
+			t.Errorf("Expected length %d, got %d (%v)", length, len(body), err)
+		}
+	}
+}
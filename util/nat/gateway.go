@@ -0,0 +1,90 @@
+// Package nat 查询上游路由器(网关)的端口映射及外部地址, 支持 UPnP IGD、NAT-PMP、PCP。
+// 也可通过 STUN 服务器获得NAT后的公网地址
+package nat
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"net"
+	"os"
+	"strings"
+)
+
+// DefaultGateway 获得默认网关的IPv4地址。
+// Linux 读取 /proc/net/route, 其它系统使用默认出口地址所在网段的 .1 地址
+func DefaultGateway() (net.IP, error) {
+	if gw, err := gatewayFromProcRoute(); err == nil {
+		return gw, nil
+	}
+
+	localIP, err := outboundIPv4()
+	if err != nil {
+		return nil, err
+	}
+	gw := make(net.IP, net.IPv4len)
+	copy(gw, localIP)
+	gw[3] = 1
+	return gw, nil
+}
+
+// gatewayFromProcRoute 从 /proc/net/route 中读取默认路由的网关
+func gatewayFromProcRoute() (net.IP, error) {
+	file, err := os.Open("/proc/net/route")
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		// Iface Destination Gateway Flags ...
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 3 || fields[1] != "00000000" {
+			continue
+		}
+		b, err := hex.DecodeString(fields[2])
+		if err != nil || len(b) != net.IPv4len {
+			continue
+		}
+		// 小端序
+		gw := make(net.IP, net.IPv4len)
+		binary.BigEndian.PutUint32(gw, binary.LittleEndian.Uint32(b))
+		if !gw.IsUnspecified() {
+			return gw, nil
+		}
+	}
+	return nil, errors.New("default gateway not found")
+}
+
+// outboundIPv4 获得访问公网时使用的本机IPv4地址, UDP 不会真正发送数据
+func outboundIPv4() (net.IP, error) {
+	conn, err := net.Dial("udp4", "8.8.8.8:53")
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	return conn.LocalAddr().(*net.UDPAddr).IP.To4(), nil
+}
+
+// localIPFor 获得访问 host 时使用的本机地址
+func localIPFor(host string) (net.IP, error) {
+	conn, err := net.Dial("udp4", net.JoinHostPort(host, "9"))
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	return conn.LocalAddr().(*net.UDPAddr).IP, nil
+}
+
+// normalizeProtocol 统一协议名称为 TCP/UDP
+func normalizeProtocol(protocol string) (string, error) {
+	switch strings.ToUpper(strings.TrimPrefix(protocol, "_")) {
+	case "TCP":
+		return "TCP", nil
+	case "UDP":
+		return "UDP", nil
+	}
+	return "", errors.New("unsupported protocol: " + protocol)
+}
@@ -0,0 +1,109 @@
+package nat
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+)
+
+// natpmpPort NAT-PMP 服务端口, RFC 6886
+var natpmpPort = 5351
+
+// natpmpResultCodes NAT-PMP 结果码
+var natpmpResultCodes = map[uint16]string{
+	1: "unsupported version",
+	2: "not authorized/refused",
+	3: "network failure",
+	4: "out of resources",
+	5: "unsupported opcode",
+}
+
+// errUnsupportedVersion 网关不支持请求使用的协议版本, 如仅支持 NAT-PMP 的网关收到 PCP 请求
+var errUnsupportedVersion = errors.New("unsupported version")
+
+// gatewayCall 向网关发送 NAT-PMP/PCP 请求, 按 RFC 6886 的间隔重试, 返回操作码一致的应答
+func gatewayCall(gateway net.IP, request []byte, responseLen int) ([]byte, error) {
+	conn, err := net.Dial("udp4", net.JoinHostPort(gateway.String(), strconv.Itoa(natpmpPort)))
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	resp := make([]byte, 1100)
+	// 初始等待250ms, 每次翻倍, 共重试4次
+	wait := 250 * time.Millisecond
+	for range 4 {
+		if _, err = conn.Write(request); err != nil {
+			return nil, err
+		}
+		conn.SetReadDeadline(time.Now().Add(wait))
+		n, err := conn.Read(resp)
+		wait *= 2
+		if err != nil {
+			if ne, ok := err.(net.Error); ok && ne.Timeout() {
+				continue
+			}
+			return nil, err
+		}
+		if n >= 2 && resp[0] != request[0] {
+			return nil, errUnsupportedVersion
+		}
+		// NAT-PMP 及 PCP 应答的操作码均为请求的操作码+128
+		if n < responseLen || resp[1] != request[1]|0x80 {
+			continue
+		}
+		return resp[:n], nil
+	}
+	return nil, errors.New("no response from " + gateway.String())
+}
+
+// natpmpCall 发送 NAT-PMP 请求, 返回校验过结果码的应答
+func natpmpCall(gateway net.IP, request []byte, responseLen int) ([]byte, error) {
+	resp, err := gatewayCall(gateway, request, responseLen)
+	if err != nil {
+		return nil, fmt.Errorf("NAT-PMP: %w", err)
+	}
+	if code := binary.BigEndian.Uint16(resp[2:4]); code != 0 {
+		return nil, fmt.Errorf("NAT-PMP: %s", natpmpResultCodes[code])
+	}
+	return resp, nil
+}
+
+// NatpmpExternalAddress 通过 NAT-PMP 获得网关的外部IPv4地址
+func NatpmpExternalAddress(gateway net.IP) (net.IP, error) {
+	// version 0, opcode 0
+	resp, err := natpmpCall(gateway, []byte{0, 0}, 12)
+	if err != nil {
+		return nil, err
+	}
+	return net.IP(resp[8:12]), nil
+}
+
+// NatpmpMapPort 通过 NAT-PMP 请求(或续期)端口映射, 返回网关分配的外部端口。
+// lifetime 为映射的有效期(秒)
+func NatpmpMapPort(gateway net.IP, protocol string, internalPort int, lifetime int) (int, error) {
+	protocol, err := normalizeProtocol(protocol)
+	if err != nil {
+		return 0, err
+	}
+
+	request := make([]byte, 12)
+	// version 0, opcode 1: UDP, 2: TCP
+	request[1] = 1
+	if protocol == "TCP" {
+		request[1] = 2
+	}
+	binary.BigEndian.PutUint16(request[4:6], uint16(internalPort))
+	// 建议的外部端口与内部端口一致
+	binary.BigEndian.PutUint16(request[6:8], uint16(internalPort))
+	binary.BigEndian.PutUint32(request[8:12], uint32(lifetime))
+
+	resp, err := natpmpCall(gateway, request, 16)
+	if err != nil {
+		return 0, err
+	}
+	return int(binary.BigEndian.Uint16(resp[10:12])), nil
+}
@@ -0,0 +1,93 @@
+package nat
+
+import (
+	"encoding/binary"
+	"net"
+	"testing"
+)
+
+// startTestNatpmpServer 启动一个仅用于测试的 NAT-PMP/PCP 服务, 外部地址为 203.0.113.1,
+// 将内部端口映射为 内部端口+1000
+func startTestNatpmpServer(t *testing.T) {
+	conn, err := net.ListenPacket("udp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	oldPort := natpmpPort
+	natpmpPort = conn.LocalAddr().(*net.UDPAddr).Port
+	t.Cleanup(func() { natpmpPort = oldPort })
+
+	externalIP := net.IPv4(203, 0, 113, 1)
+	go func() {
+		buf := make([]byte, 1100)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			var resp []byte
+			switch {
+			case n == 2 && buf[0] == 0 && buf[1] == 0:
+				// NAT-PMP 外部地址
+				resp = make([]byte, 12)
+				resp[1] = 128
+				copy(resp[8:12], externalIP.To4())
+			case n == 12 && buf[0] == 0:
+				// NAT-PMP 端口映射
+				resp = make([]byte, 16)
+				resp[1] = buf[1] + 128
+				copy(resp[8:10], buf[4:6])
+				binary.BigEndian.PutUint16(resp[10:12], binary.BigEndian.Uint16(buf[4:6])+1000)
+				copy(resp[12:16], buf[8:12])
+			case n == pcpMapLen && buf[0] == pcpVersion && buf[1] == pcpOpMap:
+				// PCP MAP
+				resp = make([]byte, pcpMapLen)
+				resp[0] = pcpVersion
+				resp[1] = pcpOpMap | 0x80
+				copy(resp[4:8], buf[4:8])
+				copy(resp[24:44], buf[24:44])
+				copy(resp[44:60], externalIP.To16())
+			default:
+				continue
+			}
+			conn.WriteTo(resp, addr)
+		}
+	}()
+}
+
+func TestNatpmpMapPort(t *testing.T) {
+	startTestNatpmpServer(t)
+
+	port, err := NatpmpMapPort(net.IPv4(127, 0, 0, 1), "_udp", 25565, 7200)
+	if err != nil {
+		t.Fatalf("Expected nil error, got %v", err)
+	}
+	if port != 26565 {
+		t.Errorf("Expected 26565, got %d", port)
+	}
+
+	if _, err := NatpmpMapPort(net.IPv4(127, 0, 0, 1), "sctp", 25565, 7200); err == nil {
+		t.Error("Expected error, got nil")
+	}
+}
+
+func TestGatewayExternalAddress(t *testing.T) {
+	startTestNatpmpServer(t)
+
+	ip, err := NatpmpExternalAddress(net.IPv4(127, 0, 0, 1))
+	if err != nil {
+		t.Fatalf("Expected nil error, got %v", err)
+	}
+	if ip.String() != "203.0.113.1" {
+		t.Errorf("Expected 203.0.113.1, got %s", ip)
+	}
+
+	ip, err = PcpExternalAddress(net.IPv4(127, 0, 0, 1))
+	if err != nil {
+		t.Fatalf("Expected nil error, got %v", err)
+	}
+	if ip.String() != "203.0.113.1" {
+		t.Errorf("Expected 203.0.113.1, got %s", ip)
+	}
+}
@@ -0,0 +1,88 @@
+package nat
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"net"
+)
+
+const (
+	// pcpVersion PCP 协议版本, RFC 6887
+	pcpVersion = 2
+	// pcpOpMap MAP 操作码
+	pcpOpMap = 1
+	// pcpMapLen MAP 请求及应答的长度
+	pcpMapLen = 60
+	// pcpProbePort 用于获取外部地址的临时映射的内部端口(discard)
+	pcpProbePort = 9
+)
+
+// pcpResultCodes PCP 结果码
+var pcpResultCodes = map[byte]string{
+	1:  "unsupported version",
+	2:  "not authorized",
+	3:  "malformed request",
+	4:  "unsupported opcode",
+	5:  "unsupported option",
+	6:  "malformed option",
+	7:  "network failure",
+	8:  "no resources",
+	9:  "unsupported protocol",
+	10: "user exceeded quota",
+	11: "cannot provide external",
+	12: "address mismatch",
+	13: "excessive remote peers",
+}
+
+// pcpMap 发送 PCP MAP 请求, 返回网关分配的外部地址
+func pcpMap(gateway net.IP, nonce []byte, lifetime uint32) (net.IP, error) {
+	clientIP, err := localIPFor(gateway.String())
+	if err != nil {
+		return nil, err
+	}
+
+	request := make([]byte, pcpMapLen)
+	request[0] = pcpVersion
+	request[1] = pcpOpMap
+	binary.BigEndian.PutUint32(request[4:8], lifetime)
+	copy(request[8:24], clientIP.To16())
+	copy(request[24:36], nonce)
+	// UDP
+	request[36] = 17
+	binary.BigEndian.PutUint16(request[40:42], pcpProbePort)
+	binary.BigEndian.PutUint16(request[42:44], pcpProbePort)
+	// 建议的外部地址为 ::ffff:0.0.0.0, 由网关分配
+	copy(request[44:60], net.IPv4zero.To16())
+
+	resp, err := gatewayCall(gateway, request, pcpMapLen)
+	if err != nil {
+		return nil, fmt.Errorf("PCP: %w", err)
+	}
+	if code := resp[3]; code != 0 {
+		return nil, fmt.Errorf("PCP: %s", pcpResultCodes[code])
+	}
+	if string(resp[24:36]) != string(nonce) {
+		return nil, fmt.Errorf("PCP: nonce mismatch")
+	}
+	return net.IP(resp[44:60]), nil
+}
+
+// PcpExternalAddress 通过 PCP 获得网关的外部地址。
+// PCP 没有单独查询外部地址的操作, 将请求一个临时的 UDP 映射, 获得地址后立即删除
+func PcpExternalAddress(gateway net.IP) (net.IP, error) {
+	nonce := make([]byte, 12)
+	rand.Read(nonce)
+
+	ip, err := pcpMap(gateway, nonce, 120)
+	if err != nil {
+		return nil, err
+	}
+	// lifetime 为 0 时删除映射
+	pcpMap(gateway, nonce, 0)
+
+	if ip4 := ip.To4(); ip4 != nil {
+		return ip4, nil
+	}
+	return ip, nil
+}
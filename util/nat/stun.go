@@ -0,0 +1,140 @@
+package nat
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/jeessy2/ddns-go/v6/util"
+)
+
+const (
+	// stunMagicCookie RFC 5389 固定的 magic cookie
+	stunMagicCookie = 0x2112A442
+	// stunBindingRequest Binding Request 消息类型
+	stunBindingRequest = 0x0001
+	// stunBindingSuccess Binding Success Response 消息类型
+	stunBindingSuccess = 0x0101
+	// stunMappedAddress MAPPED-ADDRESS 属性, 兼容 RFC 3489 的服务器
+	stunMappedAddress = 0x0001
+	// stunXorMappedAddress XOR-MAPPED-ADDRESS 属性
+	stunXorMappedAddress = 0x0020
+)
+
+// StunMappedAddress 向 STUN 服务器发送 Binding Request(RFC 5389),
+// 返回服务器看到的地址, 即NAT后的公网地址。
+// network 为 udp4 或 udp6, server 可不带端口, 默认 3478
+func StunMappedAddress(network, server string, timeout time.Duration) (net.IP, error) {
+	if _, _, err := net.SplitHostPort(server); err != nil {
+		server = net.JoinHostPort(strings.Trim(server, "[]"), "3478")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	conn, err := util.DialContext(ctx, network, server)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	request := make([]byte, 20)
+	binary.BigEndian.PutUint16(request[0:2], stunBindingRequest)
+	binary.BigEndian.PutUint32(request[4:8], stunMagicCookie)
+	rand.Read(request[8:20])
+
+	resp := make([]byte, 1500)
+	deadline, _ := ctx.Deadline()
+	// 初始等待500ms, 每次翻倍, 直到超时
+	wait := 500 * time.Millisecond
+	for time.Now().Before(deadline) {
+		if _, err = conn.Write(request); err != nil {
+			return nil, err
+		}
+		readDeadline := time.Now().Add(wait)
+		if readDeadline.After(deadline) {
+			readDeadline = deadline
+		}
+		conn.SetReadDeadline(readDeadline)
+		wait *= 2
+		n, err := conn.Read(resp)
+		if err != nil {
+			if ne, ok := err.(net.Error); ok && ne.Timeout() {
+				continue
+			}
+			return nil, err
+		}
+		if ip, err := parseStunResponse(resp[:n], request[8:20]); err == nil {
+			return ip, nil
+		}
+	}
+	return nil, errors.New("STUN: no response from " + server)
+}
+
+// parseStunResponse 校验 Binding Success Response 并解析其中的地址。
+// 优先使用 XOR-MAPPED-ADDRESS
+func parseStunResponse(resp []byte, transactionID []byte) (net.IP, error) {
+	if len(resp) < 20 ||
+		binary.BigEndian.Uint16(resp[0:2]) != stunBindingSuccess ||
+		binary.BigEndian.Uint32(resp[4:8]) != stunMagicCookie ||
+		string(resp[8:20]) != string(transactionID) {
+		return nil, errors.New("STUN: invalid response")
+	}
+
+	var mapped net.IP
+	attrs := resp[20:]
+	if length := int(binary.BigEndian.Uint16(resp[2:4])); length < len(attrs) {
+		attrs = attrs[:length]
+	}
+	for len(attrs) >= 4 {
+		attrType := binary.BigEndian.Uint16(attrs[0:2])
+		attrLen := int(binary.BigEndian.Uint16(attrs[2:4]))
+		if 4+attrLen > len(attrs) {
+			break
+		}
+		value := attrs[4 : 4+attrLen]
+		switch attrType {
+		case stunXorMappedAddress:
+			if ip := parseStunAddress(value, resp[4:20]); ip != nil {
+				return ip, nil
+			}
+		case stunMappedAddress:
+			mapped = parseStunAddress(value, nil)
+		}
+		// 属性按4字节对齐
+		attrs = attrs[min(4+(attrLen+3)&^3, len(attrs)):]
+	}
+	if mapped != nil {
+		return mapped, nil
+	}
+	return nil, errors.New("STUN: no mapped address in response")
+}
+
+// parseStunAddress 解析地址属性, xor 为 magic cookie + transaction ID, 为 nil 时不做异或
+func parseStunAddress(value []byte, xor []byte) net.IP {
+	if len(value) < 4 {
+		return nil
+	}
+	var ip net.IP
+	switch value[1] {
+	case 0x01:
+		ip = make(net.IP, net.IPv4len)
+	case 0x02:
+		ip = make(net.IP, net.IPv6len)
+	default:
+		return nil
+	}
+	if len(value) < 4+len(ip) {
+		return nil
+	}
+	copy(ip, value[4:])
+	for i := range xor {
+		if i < len(ip) {
+			ip[i] ^= xor[i]
+		}
+	}
+	return ip
+}
@@ -0,0 +1,86 @@
+package nat
+
+import (
+	"encoding/binary"
+	"net"
+	"testing"
+	"time"
+)
+
+// startTestStunServer 启动一个仅用于测试的 STUN 服务, 返回固定的地址。
+// xor 为 false 时使用 MAPPED-ADDRESS
+func startTestStunServer(t *testing.T, network string, mapped net.IP, xor bool) string {
+	address := "127.0.0.1:0"
+	if network == "udp6" {
+		address = "[::1]:0"
+	}
+	conn, err := net.ListenPacket(network, address)
+	if err != nil {
+		t.Skipf("%s is not available: %s", network, err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	go func() {
+		buf := make([]byte, 1500)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			if n != 20 || binary.BigEndian.Uint16(buf[0:2]) != stunBindingRequest {
+				continue
+			}
+
+			family, ip := byte(0x01), mapped.To4()
+			if ip == nil {
+				family, ip = 0x02, mapped.To16()
+			}
+			value := make([]byte, 4+len(ip))
+			value[1] = family
+			copy(value[4:], ip)
+			attrType := uint16(stunMappedAddress)
+			if xor {
+				attrType = stunXorMappedAddress
+				for i := range ip {
+					value[4+i] ^= buf[4+i]
+				}
+			}
+
+			resp := make([]byte, 24, 24+len(value))
+			binary.BigEndian.PutUint16(resp[0:2], stunBindingSuccess)
+			binary.BigEndian.PutUint16(resp[2:4], uint16(4+len(value)))
+			copy(resp[4:20], buf[4:20])
+			binary.BigEndian.PutUint16(resp[20:22], attrType)
+			binary.BigEndian.PutUint16(resp[22:24], uint16(len(value)))
+			conn.WriteTo(append(resp, value...), addr)
+		}
+	}()
+
+	return conn.LocalAddr().String()
+}
+
+func TestStunMappedAddress(t *testing.T) {
+	tests := []struct {
+		name    string
+		network string
+		mapped  string
+		xor     bool
+	}{
+		{"IPv4", "udp4", "203.0.113.5", true},
+		{"IPv4 MAPPED-ADDRESS", "udp4", "203.0.113.6", false},
+		{"IPv6", "udp6", "2001:db8::5", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := startTestStunServer(t, tt.network, net.ParseIP(tt.mapped), tt.xor)
+			ip, err := StunMappedAddress(tt.network, server, 2*time.Second)
+			if err != nil {
+				t.Fatalf("Expected nil error, got %v", err)
+			}
+			if ip.String() != tt.mapped {
+				t.Errorf("Expected %s, got %s", tt.mapped, ip)
+			}
+		})
+	}
+}
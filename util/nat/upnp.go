@@ -0,0 +1,266 @@
+package nat
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jeessy2/ddns-go/v6/util"
+)
+
+// ssdpAddr SSDP 组播地址
+const ssdpAddr = "239.255.255.250:1900"
+
+// igdSearchTargets 需搜索的设备类型
+var igdSearchTargets = []string{
+	"urn:schemas-upnp-org:device:InternetGatewayDevice:2",
+	"urn:schemas-upnp-org:device:InternetGatewayDevice:1",
+}
+
+// IGD UPnP 互联网网关设备中的 WANIPConnection/WANPPPConnection 服务
+type IGD struct {
+	ServiceType string
+	ControlURL  string
+	// LocalIP 访问网关时本机使用的地址
+	LocalIP net.IP
+}
+
+type upnpService struct {
+	ServiceType string `xml:"serviceType"`
+	ControlURL  string `xml:"controlURL"`
+}
+
+type upnpDevice struct {
+	Services []upnpService `xml:"serviceList>service"`
+	Devices  []upnpDevice  `xml:"deviceList>device"`
+}
+
+type upnpRoot struct {
+	URLBase string     `xml:"URLBase"`
+	Device  upnpDevice `xml:"device"`
+}
+
+// findWANService 递归查找 WAN 连接服务
+func (d upnpDevice) findWANService() (upnpService, bool) {
+	for _, s := range d.Services {
+		if strings.Contains(s.ServiceType, "WANIPConnection") || strings.Contains(s.ServiceType, "WANPPPConnection") {
+			return s, true
+		}
+	}
+	for _, child := range d.Devices {
+		if s, ok := child.findWANService(); ok {
+			return s, true
+		}
+	}
+	return upnpService{}, false
+}
+
+// DiscoverIGD 通过 SSDP 发现局域网中的 UPnP 互联网网关设备
+func DiscoverIGD(timeout time.Duration) (*IGD, error) {
+	conn, err := net.ListenPacket("udp4", ":0")
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	dst, _ := net.ResolveUDPAddr("udp4", ssdpAddr)
+	for _, st := range igdSearchTargets {
+		msg := "M-SEARCH * HTTP/1.1\r\n" +
+			"HOST: " + ssdpAddr + "\r\n" +
+			"ST: " + st + "\r\n" +
+			"MAN: \"ssdp:discover\"\r\n" +
+			"MX: 2\r\n\r\n"
+		if _, err := conn.WriteTo([]byte(msg), dst); err != nil {
+			return nil, err
+		}
+	}
+
+	conn.SetReadDeadline(time.Now().Add(timeout))
+	buf := make([]byte, 2048)
+	for {
+		n, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			return nil, errors.New("UPnP: no Internet Gateway Device found")
+		}
+		resp, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(buf[:n])), nil)
+		if err != nil {
+			continue
+		}
+		location := resp.Header.Get("Location")
+		if location == "" {
+			continue
+		}
+		if igd, err := NewIGD(location); err == nil {
+			return igd, nil
+		}
+	}
+}
+
+// NewIGD 从设备描述文件中获得 WAN 连接服务
+func NewIGD(location string) (*IGD, error) {
+	client := util.CreateNoProxyHTTPClient("tcp4")
+	resp, err := client.Get(location)
+	body, err := util.GetHTTPResponseOrg(resp, err)
+	if err != nil {
+		return nil, err
+	}
+
+	var root upnpRoot
+	if err = xml.Unmarshal(body, &root); err != nil {
+		return nil, err
+	}
+	service, ok := root.Device.findWANService()
+	if !ok {
+		return nil, errors.New("UPnP: no WAN connection service in " + location)
+	}
+
+	base := location
+	if root.URLBase != "" {
+		base = root.URLBase
+	}
+	baseURL, err := url.Parse(base)
+	if err != nil {
+		return nil, err
+	}
+	controlURL, err := baseURL.Parse(service.ControlURL)
+	if err != nil {
+		return nil, err
+	}
+
+	localIP, err := localIPFor(controlURL.Hostname())
+	if err != nil {
+		return nil, err
+	}
+
+	return &IGD{
+		ServiceType: service.ServiceType,
+		ControlURL:  controlURL.String(),
+		LocalIP:     localIP,
+	}, nil
+}
+
+// upnpError SOAP 调用返回的 UPnP 错误
+type upnpError struct {
+	Code        string
+	Description string
+}
+
+func (e *upnpError) Error() string {
+	return fmt.Sprintf("UPnP error %s: %s", e.Code, e.Description)
+}
+
+// call 调用 SOAP 方法, 返回应答中所有叶子节点的值
+func (igd *IGD) call(action string, args [][2]string) (map[string]string, error) {
+	var body strings.Builder
+	body.WriteString(`<?xml version="1.0"?>` +
+		`<s:Envelope xmlns:s="http://schemas.xmlsoap.org/soap/envelope/" s:encodingStyle="http://schemas.xmlsoap.org/soap/encoding/">` +
+		`<s:Body><u:` + action + ` xmlns:u="` + igd.ServiceType + `">`)
+	for _, arg := range args {
+		body.WriteString("<" + arg[0] + ">")
+		xml.EscapeText(&body, []byte(arg[1]))
+		body.WriteString("</" + arg[0] + ">")
+	}
+	body.WriteString(`</u:` + action + `></s:Body></s:Envelope>`)
+
+	req, err := http.NewRequest("POST", igd.ControlURL, strings.NewReader(body.String()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", `text/xml; charset="utf-8"`)
+	req.Header.Set("SOAPAction", `"`+igd.ServiceType+"#"+action+`"`)
+
+	client := util.CreateNoProxyHTTPClient("tcp4")
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	values, err := parseSOAPResponse(io.LimitReader(resp.Body, 1024000))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		if code, ok := values["errorCode"]; ok {
+			return nil, &upnpError{Code: code, Description: values["errorDescription"]}
+		}
+		return nil, fmt.Errorf("UPnP: %s returned status %d", action, resp.StatusCode)
+	}
+	return values, nil
+}
+
+// parseSOAPResponse 解析 SOAP 应答, 返回 元素名 => 值
+func parseSOAPResponse(r io.Reader) (map[string]string, error) {
+	values := map[string]string{}
+	decoder := xml.NewDecoder(r)
+	var current string
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return values, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			current = t.Name.Local
+		case xml.CharData:
+			if current != "" {
+				values[current] = strings.TrimSpace(string(t))
+			}
+		case xml.EndElement:
+			current = ""
+		}
+	}
+}
+
+// GetExternalPort 遍历网关的端口映射, 返回映射到本机 internalPort 的外部端口
+func (igd *IGD) GetExternalPort(protocol string, internalPort int) (int, error) {
+	protocol, err := normalizeProtocol(protocol)
+	if err != nil {
+		return 0, err
+	}
+
+	// 映射数量一般很少, 限制最大遍历数量
+	for i := 0; i < 1024; i++ {
+		entry, err := igd.call("GetGenericPortMappingEntry", [][2]string{{"NewPortMappingIndex", strconv.Itoa(i)}})
+		if err != nil {
+			// 713 SpecifiedArrayIndexInvalid 表示已遍历完
+			var ue *upnpError
+			if errors.As(err, &ue) && (ue.Code == "713" || ue.Code == "714") {
+				break
+			}
+			return 0, err
+		}
+		if !strings.EqualFold(entry["NewProtocol"], protocol) ||
+			entry["NewInternalPort"] != strconv.Itoa(internalPort) ||
+			!net.ParseIP(entry["NewInternalClient"]).Equal(igd.LocalIP) {
+			continue
+		}
+		return strconv.Atoi(entry["NewExternalPort"])
+	}
+	return 0, fmt.Errorf("UPnP: no %s port mapping to %s:%d", protocol, igd.LocalIP, internalPort)
+}
+
+// GetExternalIPAddress 获得网关的外部IP地址
+func (igd *IGD) GetExternalIPAddress() (net.IP, error) {
+	values, err := igd.call("GetExternalIPAddress", nil)
+	if err != nil {
+		return nil, err
+	}
+	ip := net.ParseIP(values["NewExternalIPAddress"])
+	if ip == nil || ip.IsUnspecified() {
+		return nil, fmt.Errorf("UPnP: invalid external IP address %q", values["NewExternalIPAddress"])
+	}
+	return ip, nil
+}
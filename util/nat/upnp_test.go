@@ -0,0 +1,110 @@
+package nat
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+const testDescription = `<?xml version="1.0"?>
+<root xmlns="urn:schemas-upnp-org:device-1-0">
+  <device>
+    <deviceType>urn:schemas-upnp-org:device:InternetGatewayDevice:1</deviceType>
+    <deviceList>
+      <device>
+        <deviceType>urn:schemas-upnp-org:device:WANDevice:1</deviceType>
+        <deviceList>
+          <device>
+            <deviceType>urn:schemas-upnp-org:device:WANConnectionDevice:1</deviceType>
+            <serviceList>
+              <service>
+                <serviceType>urn:schemas-upnp-org:service:WANIPConnection:1</serviceType>
+                <controlURL>/ctl/IPConn</controlURL>
+              </service>
+            </serviceList>
+          </device>
+        </deviceList>
+      </device>
+    </deviceList>
+  </device>
+</root>`
+
+var indexReg = regexp.MustCompile(`<NewPortMappingIndex>(\d+)</NewPortMappingIndex>`)
+
+// startTestIGD 启动一个仅用于测试的 UPnP 网关
+func startTestIGD(t *testing.T, mappings [][4]string) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/rootDesc.xml" {
+			io.WriteString(w, testDescription)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		if strings.Contains(r.Header.Get("SOAPAction"), "#GetExternalIPAddress") {
+			io.WriteString(w, `<s:Envelope xmlns:s="http://schemas.xmlsoap.org/soap/envelope/"><s:Body>`+
+				`<u:GetExternalIPAddressResponse xmlns:u="urn:schemas-upnp-org:service:WANIPConnection:1">`+
+				`<NewExternalIPAddress>100.64.1.2</NewExternalIPAddress></u:GetExternalIPAddressResponse></s:Body></s:Envelope>`)
+			return
+		}
+		if !strings.Contains(r.Header.Get("SOAPAction"), "#GetGenericPortMappingEntry") {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		var index int
+		fmt.Sscan(indexReg.FindStringSubmatch(string(body))[1], &index)
+		if index >= len(mappings) {
+			w.WriteHeader(http.StatusInternalServerError)
+			io.WriteString(w, `<s:Envelope xmlns:s="http://schemas.xmlsoap.org/soap/envelope/"><s:Body><s:Fault><detail>`+
+				`<UPnPError xmlns="urn:schemas-upnp-org:control-1-0"><errorCode>713</errorCode>`+
+				`<errorDescription>SpecifiedArrayIndexInvalid</errorDescription></UPnPError></detail></s:Fault></s:Body></s:Envelope>`)
+			return
+		}
+		m := mappings[index]
+		fmt.Fprintf(w, `<s:Envelope xmlns:s="http://schemas.xmlsoap.org/soap/envelope/"><s:Body>`+
+			`<u:GetGenericPortMappingEntryResponse xmlns:u="urn:schemas-upnp-org:service:WANIPConnection:1">`+
+			`<NewExternalPort>%s</NewExternalPort><NewProtocol>%s</NewProtocol><NewInternalPort>%s</NewInternalPort>`+
+			`<NewInternalClient>%s</NewInternalClient></u:GetGenericPortMappingEntryResponse></s:Body></s:Envelope>`,
+			m[0], m[1], m[2], m[3])
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestGetExternalPort(t *testing.T) {
+	server := startTestIGD(t, [][4]string{
+		{"8080", "TCP", "80", "127.0.0.1"},
+		{"30000", "UDP", "25565", "192.168.1.9"},
+		{"30001", "UDP", "25565", "127.0.0.1"},
+	})
+
+	igd, err := NewIGD(server.URL + "/rootDesc.xml")
+	if err != nil {
+		t.Fatalf("Expected nil error, got %v", err)
+	}
+	if igd.ControlURL != server.URL+"/ctl/IPConn" {
+		t.Errorf("Expected control URL %s, got %s", server.URL+"/ctl/IPConn", igd.ControlURL)
+	}
+
+	port, err := igd.GetExternalPort("_udp", 25565)
+	if err != nil {
+		t.Fatalf("Expected nil error, got %v", err)
+	}
+	if port != 30001 {
+		t.Errorf("Expected 30001, got %d", port)
+	}
+
+	if _, err := igd.GetExternalPort("tcp", 443); err == nil {
+		t.Error("Expected error, got nil")
+	}
+
+	ip, err := igd.GetExternalIPAddress()
+	if err != nil {
+		t.Fatalf("Expected nil error, got %v", err)
+	}
+	if ip.String() != "100.64.1.2" {
+		t.Errorf("Expected 100.64.1.2, got %s", ip)
+	}
+}
@@ -0,0 +1,4 @@
+package util
+
+// VersionEnv 保存当前版本号的环境变量, 启动时设置
+const VersionEnv = "DDNS_GO_VERSION"
@@ -0,0 +1,26 @@
+package web
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/jeessy2/ddns-go/v6/config"
+)
+
+// History 分页查询更新历史, 参数: page 从1开始, size 每页条数(最大100), domain 按域名筛选
+func History(writer http.ResponseWriter, request *http.Request) {
+	query := request.URL.Query()
+	page, _ := strconv.Atoi(query.Get("page"))
+	size, _ := strconv.Atoi(query.Get("size"))
+	if size <= 0 || size > 100 {
+		size = 20
+	}
+
+	result, err := config.GetHistory(strings.TrimSpace(query.Get("domain")), page, size)
+	if err != nil {
+		returnError(writer, err.Error())
+		return
+	}
+	returnOK(writer, "", result)
+}
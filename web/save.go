@@ -28,13 +28,14 @@ func checkAndSave(request *http.Request) string {
 
 	// 从请求中读取 JSON 数据
 	var data struct {
-		Username           string       `json:"Username"`
-		Password           string       `json:"Password"`
-		NotAllowWanAccess  bool         `json:"NotAllowWanAccess"`
-		WebhookURL         string       `json:"WebhookURL"`
-		WebhookRequestBody string       `json:"WebhookRequestBody"`
-		WebhookHeaders     string       `json:"WebhookHeaders"`
-		DnsConf            []dnsConf4JS `json:"DnsConf"`
+		Username          string                 `json:"Username"`
+		Password          string                 `json:"Password"`
+		NotAllowWanAccess bool                   `json:"NotAllowWanAccess"`
+		HistoryKeepDays   int                    `json:"HistoryKeepDays"`
+		HistoryMaxEntries int                    `json:"HistoryMaxEntries"`
+		Webhooks          []config.WebhookConfig `json:"Webhooks"`
+		Mqtt              config.Mqtt            `json:"Mqtt"`
+		DnsConf           []dnsConf4JS           `json:"DnsConf"`
 	}
 
 	// 解析请求中的 JSON 数据
@@ -50,9 +51,40 @@ func checkAndSave(request *http.Request) string {
 	conf.Lang = util.InitLogLang(accept)
 
 	conf.NotAllowWanAccess = data.NotAllowWanAccess
-	conf.WebhookURL = strings.TrimSpace(data.WebhookURL)
-	conf.WebhookRequestBody = strings.TrimSpace(data.WebhookRequestBody)
-	conf.WebhookHeaders = strings.TrimSpace(data.WebhookHeaders)
+	conf.History = config.History{
+		KeepDays:   max(data.HistoryKeepDays, 0),
+		MaxEntries: max(data.HistoryMaxEntries, 0),
+	}
+	savedWebhooks := conf.Webhooks
+	conf.Webhooks = nil
+	for k, webhook := range data.Webhooks {
+		if k < len(savedWebhooks) {
+			restoreWebhookSecret(&webhook, savedWebhooks[k])
+		}
+		webhook.URL = strings.TrimSpace(webhook.URL)
+		webhook.Token = strings.TrimSpace(webhook.Token)
+		webhook.Target = strings.TrimSpace(webhook.Target)
+		webhook.Secret = strings.TrimSpace(webhook.Secret)
+		if webhook.IsEmpty() {
+			continue
+		}
+		webhook.RequestBody = strings.TrimSpace(webhook.RequestBody)
+		webhook.Headers = strings.TrimSpace(webhook.Headers)
+		webhook.DnsConf = strings.TrimSpace(webhook.DnsConf)
+		conf.Webhooks = append(conf.Webhooks, webhook)
+	}
+
+	// 提交的密码为隐藏后的值时, 使用已保存的密码
+	if data.Mqtt.Password == hideSecret(conf.Mqtt.Password) {
+		data.Mqtt.Password = conf.Mqtt.Password
+	}
+	conf.Mqtt = config.Mqtt{
+		URL:             strings.TrimSpace(data.Mqtt.URL),
+		Username:        strings.TrimSpace(data.Mqtt.Username),
+		Password:        data.Mqtt.Password,
+		TopicPrefix:     strings.TrimSpace(data.Mqtt.TopicPrefix),
+		DiscoveryPrefix: strings.TrimSpace(data.Mqtt.DiscoveryPrefix),
+	}
 
 	// 如果新密码不为空则检查是否够强, 内/外网要求强度不同
 	conf.Username = usernameNew
@@ -90,18 +122,47 @@ func checkAndSave(request *http.Request) string {
 		dnsConf.Ipv4.Enable = v.Ipv4Enable
 		dnsConf.Ipv4.GetType = v.Ipv4GetType
 		dnsConf.Ipv4.URL = strings.TrimSpace(v.Ipv4Url)
+		dnsConf.Ipv4.UrlQuorum = strings.TrimSpace(v.Ipv4UrlQuorum)
 		dnsConf.Ipv4.NetInterface = v.Ipv4NetInterface
 		dnsConf.Ipv4.Cmd = strings.TrimSpace(v.Ipv4Cmd)
+		dnsConf.Ipv4.Stun = strings.TrimSpace(v.Ipv4Stun)
+		dnsConf.Ipv4.StunConsensus = v.Ipv4StunConsensus
+		dnsConf.Ipv4.DnsQuery = strings.TrimSpace(v.Ipv4DnsQuery)
 		dnsConf.Ipv4.Domains = util.SplitLines(v.Ipv4Domains)
+		dnsConf.Ipv4.AllowPrivate = v.Ipv4AllowPrivate
+		dnsConf.Ipv4.AllowCIDR = strings.TrimSpace(v.Ipv4AllowCIDR)
+		dnsConf.Ipv4.DenyCIDR = strings.TrimSpace(v.Ipv4DenyCIDR)
+		dnsConf.Ipv4.Fallback = util.SplitLines(v.Ipv4Fallback)
 
 		dnsConf.Ipv6.Enable = v.Ipv6Enable
 		dnsConf.Ipv6.GetType = v.Ipv6GetType
 		dnsConf.Ipv6.URL = strings.TrimSpace(v.Ipv6Url)
+		dnsConf.Ipv6.UrlQuorum = strings.TrimSpace(v.Ipv6UrlQuorum)
 		dnsConf.Ipv6.NetInterface = v.Ipv6NetInterface
 		dnsConf.Ipv6.Cmd = strings.TrimSpace(v.Ipv6Cmd)
+		dnsConf.Ipv6.Stun = strings.TrimSpace(v.Ipv6Stun)
+		dnsConf.Ipv6.StunConsensus = v.Ipv6StunConsensus
+		dnsConf.Ipv6.DnsQuery = strings.TrimSpace(v.Ipv6DnsQuery)
 		dnsConf.Ipv6.Ipv6Reg = strings.TrimSpace(v.Ipv6Reg)
+		dnsConf.Ipv6.AddrPolicy = v.Ipv6AddrPolicy
 		dnsConf.Ipv6.Domains = util.SplitLines(v.Ipv6Domains)
+		dnsConf.Ipv6.AllowPrivate = v.Ipv6AllowPrivate
+		dnsConf.Ipv6.AllowCIDR = strings.TrimSpace(v.Ipv6AllowCIDR)
+		dnsConf.Ipv6.DenyCIDR = strings.TrimSpace(v.Ipv6DenyCIDR)
+		dnsConf.Ipv6.Fallback = util.SplitLines(v.Ipv6Fallback)
 		dnsConf.HttpInterface = strings.TrimSpace(v.HttpInterface)
+		dnsConf.Verify = v.Verify
+		dnsConf.VerifyTimeout = strings.TrimSpace(v.VerifyTimeout)
+		dnsConf.DriftDetect = v.DriftDetect
+		dnsConf.Svcb = util.SplitLines(v.Svcb)
+		dnsConf.Srv = util.SplitLines(v.Srv)
+		dnsConf.TxtRecord = strings.TrimSpace(v.TxtRecord)
+		dnsConf.TxtTemplate = strings.TrimSpace(v.TxtTemplate)
+
+		// 局域网主机通过 AAAA 记录发布, 不支持的DNS服务商不保存
+		if config.HasIpv6Hosts(dnsConf.Ipv6.Domains) && !dns.SupportsRecord(dnsConf.DNS.Name, "AAAA") {
+			return util.LogStr("第 %s 个配置的DNS服务商 %s 不支持局域网主机(Ipv6Suffix/Ipv6Mac)", util.Ordinal(k+1, conf.Lang), dnsConf.DNS.Name)
+		}
 
 		if k < len(conf.DnsConf) {
 			c := &conf.DnsConf[k]
@@ -9,26 +9,27 @@ import (
 )
 
 func WebhookTest(writer http.ResponseWriter, request *http.Request) {
-	var data struct {
-		URL         string `json:"URL"`
-		RequestBody string `json:"RequestBody"`
-		Headers     string `json:"Headers"`
-	}
-	err := json.NewDecoder(request.Body).Decode(&data)
+	var webhook config.WebhookConfig
+	err := json.NewDecoder(request.Body).Decode(&webhook)
 	if err != nil {
 		util.Log("数据解析失败, 请刷新页面重试")
 		return
 	}
 
-	url := data.URL
-	requestBody := data.RequestBody
-	headers := data.Headers
-
-	if url == "" {
+	if webhook.IsEmpty() {
 		util.Log("请输入Webhook的URL")
 		return
 	}
 
+	// 页面中的 Token、Secret 已隐藏, 使用已保存的同一Webhook的值
+	conf, _ := config.GetConfigCached()
+	for _, saved := range conf.Webhooks {
+		if saved.Type == webhook.Type && saved.URL == webhook.URL {
+			restoreWebhookSecret(&webhook, saved)
+			break
+		}
+	}
+
 	var domains = make([]*config.Domain, 1)
 	domains[0] = &config.Domain{}
 	domains[0].DomainName = "example.com"
@@ -37,18 +38,18 @@ func WebhookTest(writer http.ResponseWriter, request *http.Request) {
 
 	fakeDomains := &config.Domains{
 		Ipv4Addr:    "127.0.0.1",
+		Ipv4OldAddr: "127.0.0.2",
 		Ipv4Domains: domains,
 		Ipv6Addr:    "::1",
+		Ipv6OldAddr: "::2",
 		Ipv6Domains: domains,
 	}
 
-	fakeConfig := &config.Config{
-		Webhook: config.Webhook{
-			WebhookURL:         url,
-			WebhookRequestBody: requestBody,
-			WebhookHeaders:     headers,
-		},
-	}
+	webhook.Send(config.NewWebhookData(fakeDomains, "test", []string{config.EventSuccess}, config.UpdatedSuccess, config.UpdatedSuccess))
+}
 
-	config.ExecWebhook(fakeDomains, fakeConfig)
+// WebhookDeliveries 最近的Webhook发送记录
+func WebhookDeliveries(writer http.ResponseWriter, request *http.Request) {
+	byt, _ := json.Marshal(config.GetWebhookDeliveries())
+	writer.Write(byt)
 }
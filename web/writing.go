@@ -10,35 +10,60 @@ import (
 	"strings"
 
 	"github.com/jeessy2/ddns-go/v6/config"
+	"github.com/jeessy2/ddns-go/v6/util"
 )
 
 //go:embed writing.html
 var writingEmbedFile embed.FS
 
-const VersionEnv = "DDNS_GO_VERSION"
+const VersionEnv = util.VersionEnv
 
 // js中的dns配置
 type dnsConf4JS struct {
-	Name             string
-	DnsName          string
-	DnsID            string
-	DnsSecret        string
-	DnsExtParam      string
-	TTL              string
-	Ipv4Enable       bool
-	Ipv4GetType      string
-	Ipv4Url          string
-	Ipv4NetInterface string
-	Ipv4Cmd          string
-	Ipv4Domains      string
-	Ipv6Enable       bool
-	Ipv6GetType      string
-	Ipv6Url          string
-	Ipv6NetInterface string
-	Ipv6Cmd          string
-	Ipv6Reg          string
-	Ipv6Domains      string
-	HttpInterface    string
+	Name              string
+	DnsName           string
+	DnsID             string
+	DnsSecret         string
+	DnsExtParam       string
+	TTL               string
+	Ipv4Enable        bool
+	Ipv4GetType       string
+	Ipv4Url           string
+	Ipv4UrlQuorum     string
+	Ipv4NetInterface  string
+	Ipv4Cmd           string
+	Ipv4Stun          string
+	Ipv4StunConsensus bool
+	Ipv4DnsQuery      string
+	Ipv4Domains       string
+	Ipv4AllowPrivate  bool
+	Ipv4AllowCIDR     string
+	Ipv4DenyCIDR      string
+	Ipv4Fallback      string
+	Ipv6Enable        bool
+	Ipv6GetType       string
+	Ipv6Url           string
+	Ipv6UrlQuorum     string
+	Ipv6NetInterface  string
+	Ipv6Cmd           string
+	Ipv6Stun          string
+	Ipv6StunConsensus bool
+	Ipv6DnsQuery      string
+	Ipv6Reg           string
+	Ipv6AddrPolicy    string
+	Ipv6Domains       string
+	Ipv6AllowPrivate  bool
+	Ipv6AllowCIDR     string
+	Ipv6DenyCIDR      string
+	Ipv6Fallback      string
+	HttpInterface     string
+	Verify            bool
+	VerifyTimeout     string
+	DriftDetect       string
+	Svcb              string
+	Srv               string
+	TxtRecord         string
+	TxtTemplate       string
 }
 
 // Writing 填写信息
@@ -74,17 +99,21 @@ func Writing(writer http.ResponseWriter, request *http.Request) {
 	err = tmpl.Execute(writer, struct {
 		DnsConf           template.JS
 		NotAllowWanAccess bool
+		History           config.History
 		Username          string
-		config.Webhook
-		Version       string
-		Ipv4          []config.NetInterface
-		Ipv6          []config.NetInterface
-		AllInterfaces []config.NetInterface
+		Webhooks          template.JS
+		Mqtt              template.JS
+		Version           string
+		Ipv4              []config.NetInterface
+		Ipv6              []config.NetInterface
+		AllInterfaces     []config.NetInterface
 	}{
 		DnsConf:           template.JS(getDnsConfStr(conf.DnsConf)),
 		NotAllowWanAccess: conf.NotAllowWanAccess,
+		History:           conf.History,
 		Username:          conf.User.Username,
-		Webhook:           conf.Webhook,
+		Webhooks:          template.JS(getWebhooksStr(conf.Webhooks)),
+		Mqtt:              template.JS(getMqttStr(conf.Mqtt)),
 		Version:           os.Getenv(VersionEnv),
 		Ipv4:              ipv4,
 		Ipv6:              ipv6,
@@ -102,46 +131,108 @@ func getDnsConfStr(dnsConf []config.DnsConfig) string {
 		// 已存在配置文件，隐藏真实的ID、Secret
 		idHide, secretHide := getHideIDSecret(&conf)
 		dnsConfArray = append(dnsConfArray, dnsConf4JS{
-			Name:             conf.Name,
-			DnsName:          conf.DNS.Name,
-			DnsID:            idHide,
-			DnsSecret:        secretHide,
-			DnsExtParam:      conf.DNS.ExtParam,
-			TTL:              conf.TTL,
-			Ipv4Enable:       conf.Ipv4.Enable,
-			Ipv4GetType:      conf.Ipv4.GetType,
-			Ipv4Url:          conf.Ipv4.URL,
-			Ipv4NetInterface: conf.Ipv4.NetInterface,
-			Ipv4Cmd:          conf.Ipv4.Cmd,
-			Ipv4Domains:      strings.Join(conf.Ipv4.Domains, "\r\n"),
-			Ipv6Enable:       conf.Ipv6.Enable,
-			Ipv6GetType:      conf.Ipv6.GetType,
-			Ipv6Url:          conf.Ipv6.URL,
-			Ipv6NetInterface: conf.Ipv6.NetInterface,
-			Ipv6Cmd:          conf.Ipv6.Cmd,
-			Ipv6Reg:          conf.Ipv6.Ipv6Reg,
-			Ipv6Domains:      strings.Join(conf.Ipv6.Domains, "\r\n"),
-			HttpInterface:    conf.HttpInterface,
+			Name:              conf.Name,
+			DnsName:           conf.DNS.Name,
+			DnsID:             idHide,
+			DnsSecret:         secretHide,
+			DnsExtParam:       conf.DNS.ExtParam,
+			TTL:               conf.TTL,
+			Ipv4Enable:        conf.Ipv4.Enable,
+			Ipv4GetType:       conf.Ipv4.GetType,
+			Ipv4Url:           conf.Ipv4.URL,
+			Ipv4UrlQuorum:     conf.Ipv4.UrlQuorum,
+			Ipv4NetInterface:  conf.Ipv4.NetInterface,
+			Ipv4Cmd:           conf.Ipv4.Cmd,
+			Ipv4Stun:          conf.Ipv4.Stun,
+			Ipv4StunConsensus: conf.Ipv4.StunConsensus,
+			Ipv4DnsQuery:      conf.Ipv4.DnsQuery,
+			Ipv4Domains:       strings.Join(conf.Ipv4.Domains, "\r\n"),
+			Ipv4AllowPrivate:  conf.Ipv4.AllowPrivate,
+			Ipv4AllowCIDR:     conf.Ipv4.AllowCIDR,
+			Ipv4DenyCIDR:      conf.Ipv4.DenyCIDR,
+			Ipv4Fallback:      strings.Join(conf.Ipv4.Fallback, "\r\n"),
+			Ipv6Enable:        conf.Ipv6.Enable,
+			Ipv6GetType:       conf.Ipv6.GetType,
+			Ipv6Url:           conf.Ipv6.URL,
+			Ipv6UrlQuorum:     conf.Ipv6.UrlQuorum,
+			Ipv6NetInterface:  conf.Ipv6.NetInterface,
+			Ipv6Cmd:           conf.Ipv6.Cmd,
+			Ipv6Stun:          conf.Ipv6.Stun,
+			Ipv6StunConsensus: conf.Ipv6.StunConsensus,
+			Ipv6DnsQuery:      conf.Ipv6.DnsQuery,
+			Ipv6Reg:           conf.Ipv6.Ipv6Reg,
+			Ipv6AddrPolicy:    conf.Ipv6.AddrPolicy,
+			Ipv6Domains:       strings.Join(conf.Ipv6.Domains, "\r\n"),
+			Ipv6AllowPrivate:  conf.Ipv6.AllowPrivate,
+			Ipv6AllowCIDR:     conf.Ipv6.AllowCIDR,
+			Ipv6DenyCIDR:      conf.Ipv6.DenyCIDR,
+			Ipv6Fallback:      strings.Join(conf.Ipv6.Fallback, "\r\n"),
+			HttpInterface:     conf.HttpInterface,
+			Verify:            conf.Verify,
+			VerifyTimeout:     conf.VerifyTimeout,
+			DriftDetect:       conf.DriftDetect,
+			Svcb:              strings.Join(conf.Svcb, "\r\n"),
+			Srv:               strings.Join(conf.Srv, "\r\n"),
+			TxtRecord:         conf.TxtRecord,
+			TxtTemplate:       conf.TxtTemplate,
 		})
 	}
 	byt, _ := json.Marshal(dnsConfArray)
 	return string(byt)
 }
 
+func getWebhooksStr(webhooks []config.WebhookConfig) string {
+	hidden := []config.WebhookConfig{}
+	for _, webhook := range webhooks {
+		hidden = append(hidden, getHideWebhook(webhook))
+	}
+	byt, _ := json.Marshal(hidden)
+	return string(byt)
+}
+
+func getMqttStr(mqtt config.Mqtt) string {
+	// 隐藏真实的密码
+	mqtt.Password = hideSecret(mqtt.Password)
+	byt, _ := json.Marshal(mqtt)
+	return string(byt)
+}
+
 // 显示的数量
 const displayCount int = 3
 
 // hideIDSecret 隐藏真实的ID、Secret
 func getHideIDSecret(conf *config.DnsConfig) (idHide string, secretHide string) {
-	if len(conf.DNS.ID) > displayCount && conf.DNS.Name != "callback" {
-		idHide = conf.DNS.ID[:displayCount] + strings.Repeat("*", len(conf.DNS.ID)-displayCount)
-	} else {
-		idHide = conf.DNS.ID
+	if conf.DNS.Name == "callback" {
+		return conf.DNS.ID, conf.DNS.Secret
+	}
+	return hideSecret(conf.DNS.ID), hideSecret(conf.DNS.Secret)
+}
+
+// getHideWebhook 隐藏Webhook及通知渠道的 Token、Secret
+func getHideWebhook(webhook config.WebhookConfig) config.WebhookConfig {
+	// 邮件的 Token 为用户名
+	if webhook.Type != "email" {
+		webhook.Token = hideSecret(webhook.Token)
+	}
+	webhook.Secret = hideSecret(webhook.Secret)
+	return webhook
+}
+
+// restoreWebhookSecret 提交的 Token、Secret 为隐藏后的值时, 使用已保存的值
+func restoreWebhookSecret(webhook *config.WebhookConfig, saved config.WebhookConfig) {
+	hidden := getHideWebhook(saved)
+	if webhook.Token == hidden.Token {
+		webhook.Token = saved.Token
+	}
+	if webhook.Secret == hidden.Secret {
+		webhook.Secret = saved.Secret
 	}
-	if len(conf.DNS.Secret) > displayCount && conf.DNS.Name != "callback" {
-		secretHide = conf.DNS.Secret[:displayCount] + strings.Repeat("*", len(conf.DNS.Secret)-displayCount)
-	} else {
-		secretHide = conf.DNS.Secret
+}
+
+// hideSecret 只显示前 displayCount 位
+func hideSecret(secret string) string {
+	if len(secret) <= displayCount {
+		return secret
 	}
-	return
+	return secret[:displayCount] + strings.Repeat("*", len(secret)-displayCount)
 }